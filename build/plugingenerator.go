@@ -92,15 +92,22 @@ package {{.PluginPkgName}}
 import (
 {{ if .IsSchemaAPINeeded }}
 	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
-{{ end -}}
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
-	epplugin "github.com/intel/edge-conductor/pkg/plugin"
+{{ end -}}
+	pluginsdk "github.com/intel/edge-conductor/pkg/pluginsdk"
 )
 
 var (
 	Name = "{{.PluginName}}"
-	Input = eputils.NewSchemaMapData()
-	Output = eputils.NewSchemaMapData()
+	Plugin = pluginsdk.New(Name)
+{{- block "ilistdecl" .}}{{range .InputList}}.
+		WithInput("{{.NameString}}", &pluginapi.{{.SchemaName}}{})
+{{- end}}{{- end}}
+{{- block "olistdecl" .}}{{range .OutputList}}.
+		WithOutput("{{.NameString}}", &pluginapi.{{.SchemaName}}{})
+{{- end}}{{- end}}
+	Input = Plugin.Input
+	Output = Plugin.Output
 )
 
 //nolint:unparam,deadcode,unused
@@ -120,20 +127,7 @@ func output_{{.Name}}(outp *eputils.SchemaMapData) *pluginapi.{{.SchemaName}} {
 }
 {{end}}{{- end}}
 func init() {
-{{- block "ilist1" .}}{{range .InputList}}
-	eputils.AddSchemaStruct(__name("{{.NameString}}"), func() eputils.SchemaStruct { return &pluginapi.{{.SchemaName}}{} })
-{{- end -}}{{- end -}}
-{{- block "olist1" .}}{{range .OutputList}}
-	eputils.AddSchemaStruct(__name("{{.NameString}}"), func() eputils.SchemaStruct { return &pluginapi.{{.SchemaName}}{} })
-{{- end}}{{- end}}
-{{block "ilist2" .}}{{range .InputList}}
-	Input[__name("{{.NameString}}")] = &pluginapi.{{.SchemaName}}{}
-{{- end -}}{{- end -}}
-{{- block "olist2" .}}{{range .OutputList}}
-	Output[__name("{{.NameString}}")] = &pluginapi.{{.SchemaName}}{}
-{{- end}}{{- end}}
-
-	epplugin.RegisterPlugin(Name, &Input, &Output, PluginMain)
+	Plugin.Register(PluginMain)
 }
 `
 
@@ -147,102 +141,37 @@ const template_plugin_main_test_go = `/*
 
 // Template auto-generated once, maintained by plugin owner.
 
-//nolint: dupl
 package {{.PluginPkgName}}
 
 import (
+	"path/filepath"
 	"testing"
+
+	pluginsdk "github.com/intel/edge-conductor/pkg/pluginsdk"
 	// TODO: Add Plugin Unit Test Imports Here
 )
 
+// TestPluginMain runs PluginMain against each fixture in testdata/<case>,
+// with the input data in testdata/<case>/input/<input name>.yml, and checks
+// the output data against testdata/<case>/output/<output name>.yml.
 func TestPluginMain(t *testing.T) {
-	cases := []struct {
-		name string
-		input, expectedOutput map[string][]byte
-		expectError bool
-	}{
-		// TODO: Add the values to complete your test cases.
-		// Add the values for input and expectedoutput with particular struct marshal data in json format.
-		// They will be used to generate "SchemaMapData" as inputs and expected outputs of plugins under test.
-		// if the inputs in the Plugin Input List is not required in your test case, keep the value as nil.
-		{
-			name: "CASE/001",
-			input: map[string][]byte{
+	// TODO: Add the fixtures of your test cases.
 {{- block "ilistdata1" .}}{{range .InputList}}
-				"{{.NameString}}": nil,
-{{- end -}}{{- end}}
-			},
-{{block "oexpect1" .}}{{if .OutputList}}
-			expectedOutput:  map[string][]byte{
-{{block "olistdata1" .}}{{range .OutputList}}
-				"{{.NameString}}": nil,
-{{- end -}}{{- end}}
-			},
+	//   testdata/<case>/input/{{.NameString}}.yml
 {{- end -}}{{- end}}
-			expectError: false,
-		},
-
-		{
-			name: "CASE/002",
-			input: map[string][]byte{
-{{- block "ilistdata2" .}}{{range .InputList}}
-				"{{.NameString}}": nil,
+{{- block "olistdata1" .}}{{range .OutputList}}
+	//   testdata/<case>/output/{{.NameString}}.yml
 {{- end -}}{{- end}}
-			},
-{{block "oexpect2" .}}{{if .OutputList}}
-			expectedOutput:  map[string][]byte{
-{{block "olistdata2" .}}{{range .OutputList}}
-				"{{.NameString}}": nil,
-{{- end -}}{{- end}}
-			},
-{{- end -}}{{- end}}
-			expectError: true,
-		},
+	dirs, err := filepath.Glob("testdata/*")
+	if err != nil {
+		t.Fatal(err)
 	}
-
-	// Optional: add setup for the test series
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			// Run test cases in parallel if necessary.
-			// t.Parallel()
-
-			input := generateInput(tc.input)
-			if input == nil {
-				t.Fatalf("Failed to generateInput %s", tc.input)
-			}
-			testOutput := generateOutput(nil)
-{{block "conditioncheckprepare" .}}{{if .OutputList}}
-			expectedOutput := generateOutput(tc.expectedOutput)
-{{- end -}}{{- end}}
-
-			// TODO: Remove the '//' before following check condition to enable plugin test.
-			// if result := PluginMain(input, &testOutput); result != nil {
-			// 	if tc.expectError {
-			// 		t.Log("Error expected.")
-			// 		return
-			// 	} else {
-			// 		t.Logf("Failed to run PluginMain when input is %s.", tc.input)
-			// 		t.Error(result)
-			// 	}
-			// }
-
-{{block "conditioncheck1" .}}{{if .OutputList}}
-			if testOutput.EqualWith(expectedOutput) {
-				t.Log("Output expected.")
-			} else {
-				t.Errorf("Failed to get expected output when input is %s.", tc.input)
-			}
-
-			// Optional: Add additional check conditions here
-
-{{else}}
-			_ = testOutput
-			// TODO: Add check conditions
-{{- end -}}{{- end}}
+	for _, dir := range dirs {
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			// Optional: add setup and mocks for the test case.
+			Plugin.RunFixture(t, PluginMain, pluginsdk.FixtureDir(dir))
 		})
 	}
-
-	// Optional: add teardown for the test series
 }
 `
 
@@ -259,63 +188,17 @@ const template_plugin_generated_testutil_go = `/*
 package {{.PluginPkgName}}
 
 import (
-{{ if .IsSchemaAPINeeded }}
-	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
-{{ end -}}
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
 )
-{{block "ilistfunc" .}}{{range .InputList}}
-//nolint:deadcode,unused
-func generate_input_{{.Name}}(data []byte, in eputils.SchemaMapData) bool {
-	inputStruct := &pluginapi.{{.SchemaName}}{}
-	if data != nil {
-		if err := inputStruct.UnmarshalBinary(data); err != nil {
-			return false
-		}
-	}
-
-	in[__name("{{.NameString}}")] = inputStruct
-	return true
-}
-{{end}}{{- end -}}
 
 //nolint:deadcode,unused,unparam
 func generateInput(data map[string][]byte) eputils.SchemaMapData {
-	n := eputils.NewSchemaMapData()
-
-{{- block "ilistdata" .}}{{range .InputList}}
-	if result := generate_input_{{.Name}}(data["{{.NameString}}"], n); !result {
-		return nil
-	}
-{{- end}}{{- end}}
-	return n
-}
-
-{{block "olistfunc" .}}{{range .OutputList}}
-//nolint:deadcode,unused
-func generate_output_{{.Name}}(data []byte, out eputils.SchemaMapData) bool {
-	outputStruct := &pluginapi.{{.SchemaName}}{}
-	if data != nil {
-		if err := outputStruct.UnmarshalBinary(data); err != nil {
-			return false
-		}
-	}
-
-	out[__name("{{.NameString}}")] = outputStruct
-	return true
+	return Plugin.NewInput(data)
 }
-{{end}}{{- end -}}
 
 //nolint:unparam,deadcode,unused
 func generateOutput(data map[string][]byte) eputils.SchemaMapData {
-	n := eputils.NewSchemaMapData()
-
-{{- block "olistdata" .}}{{range .OutputList}}
-	if result := generate_output_{{.Name}}(data["{{.NameString}}"], n); !result {
-		return nil
-	}
-{{- end}}{{- end}}
-	return n
+	return Plugin.NewOutput(data)
 }
 `
 
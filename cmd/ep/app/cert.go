@@ -0,0 +1,163 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	cmapi "github.com/intel/edge-conductor/pkg/api/certmgr"
+	"github.com/intel/edge-conductor/pkg/certmgr"
+	"github.com/intel/edge-conductor/pkg/eputils"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	certKubeConfig string
+
+	// certStdout is where the certificate status is printed.
+	certStdout io.Writer = os.Stdout
+)
+
+// getCertBundles returns the cert bundles to operate on, all of them if no
+// name is given.
+func getCertBundles(names []string) ([]*cmapi.Certificate, error) {
+	bundles, err := certmgr.ListCertBundles()
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return bundles, nil
+	}
+	for _, cb := range bundles {
+		if cb.Name == names[0] {
+			return []*cmapi.Certificate{cb}, nil
+		}
+	}
+	log.Errorf("Cert bundle %s is not found", names[0])
+	return nil, eputils.GetError("errNoCertBundle")
+}
+
+func printCertStatus(statuses []*certmgr.CertStatus, now time.Time) error {
+	const padding = 3
+
+	w := tabwriter.NewWriter(
+		certStdout,
+		0, 0, padding, ' ',
+		tabwriter.FilterHTML)
+
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "\tNAME\tTYPE\tSUBJECT\tSANS\tISSUER\tEXPIRES\tSTATUS\t")
+	fmt.Fprintln(w, "\t====\t====\t=======\t====\t======\t=======\t======\t")
+	for _, s := range statuses {
+		days := int(s.NotAfter.Sub(now).Hours() / 24)
+		fmt.Fprintf(w, "\t%s\t%s\t%s\t%s\t%s\t%s (%dd)\t%s\t\n",
+			s.Bundle,
+			s.Type,
+			s.Subject,
+			strings.Join(s.SANs, ","),
+			s.Issuer,
+			s.NotAfter.Format("2006-01-02"),
+			days,
+			s.Status)
+	}
+	fmt.Fprintln(w, "")
+	return w.Flush()
+}
+
+var certCmd = &cobra.Command{
+	Use:   "cert",
+	Short: "Certificate operations.",
+	Long:  `Certificate operations.`,
+}
+
+var statusCertCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the status of the certificates.",
+	Long:  `List the certificates of the cert bundles in the runtime config with their subject, SANs, issuer and expiry.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bundles, err := getCertBundles(nil)
+		if err != nil {
+			log.Errorln("Failed to get cert bundles:", err)
+			return err
+		}
+		now := time.Now()
+		statuses := []*certmgr.CertStatus{}
+		for _, cb := range bundles {
+			s, err := certmgr.GetCertStatus(cb, now)
+			if err != nil {
+				log.Errorf("Failed to get the status of %s: %v", cb.Name, err)
+				return err
+			}
+			statuses = append(statuses, s...)
+		}
+		return printCertStatus(statuses, now)
+	},
+}
+
+var rotateCertCmd = &cobra.Command{
+	Use:   "rotate [name]",
+	Short: "Rotate the certificates.",
+	Long: `Re-issue the server and client certificates of a cert bundle, all the bundles by default,
+from the existing CA. The TLS secrets of the deployed services using the rotated certificates
+are updated on the cluster if the kubeconfig is found.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log.Infoln(PROJECTNAME, "- Rotate Certificates")
+		log.Infoln("==")
+
+		bundles, err := getCertBundles(args)
+		if err != nil {
+			log.Errorln("Failed to get cert bundles:", err)
+			return err
+		}
+		names := []string{}
+		for _, cb := range bundles {
+			if err := certmgr.RotateCertBundle(cb); err != nil {
+				log.Errorf("Failed to rotate %s: %v", cb.Name, err)
+				return err
+			}
+			names = append(names, cb.Name)
+		}
+
+		if _, err := os.Stat(certKubeConfig); err != nil {
+			log.Warnf("Kubeconfig %s is not found, the TLS secrets of the services are not updated.", certKubeConfig)
+		} else {
+			paramsInject := map[string]string{
+				Epkubeconfig: certKubeConfig,
+				Epcmdline:    eputils.AddCmdline("", "cert="+strings.Join(names, ",")),
+			}
+			epParams, err := EpWfPreInit(nil, paramsInject)
+			if err != nil {
+				log.Errorln("Failed to init workflow:", err)
+				return err
+			}
+			if err := EpWfStart(epParams, "cert-rotate"); err != nil {
+				log.Errorln("Failed to start workflow:", err)
+				return err
+			}
+		}
+
+		log.Infoln("==")
+		log.Infoln("Done")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(certCmd)
+	certCmd.AddCommand(statusCertCmd)
+	certCmd.AddCommand(rotateCertCmd)
+	rotateCertCmd.Flags().StringVar(&certKubeConfig, "kubeconfig", GetDefaultKubeConfig(), "kubeconfig file path")
+}
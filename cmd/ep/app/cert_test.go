@@ -0,0 +1,212 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+//nolint: dupl
+package app
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	cmapi "github.com/intel/edge-conductor/pkg/api/certmgr"
+	"github.com/intel/edge-conductor/pkg/certmgr"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+
+	mpatch "github.com/undefinedlabs/go-mpatch"
+)
+
+var testCertBundles = []*cmapi.Certificate{{Name: "registry"}, {Name: "workflow"}}
+
+func patchListCertBundles(t *testing.T, err error) *mpatch.Patch {
+	patch, patchErr := mpatch.PatchMethod(certmgr.ListCertBundles, func() ([]*cmapi.Certificate, error) {
+		return testCertBundles, err
+	})
+	if patchErr != nil {
+		t.Errorf("patch error: %v", patchErr)
+		return nil
+	}
+	return patch
+}
+
+func patchGetCertStatus(t *testing.T, err error) *mpatch.Patch {
+	patch, patchErr := mpatch.PatchMethod(certmgr.GetCertStatus, func(cb *cmapi.Certificate, now time.Time) ([]*certmgr.CertStatus, error) {
+		return []*certmgr.CertStatus{{
+			Bundle:   cb.Name,
+			Type:     "server",
+			Subject:  "CN=" + cb.Name,
+			SANs:     []string{"localhost", "10.10.10.10"},
+			Issuer:   "CN=ca",
+			NotAfter: now.Add(48 * time.Hour),
+			Status:   certmgr.CertStatusExpiring,
+		}}, err
+	})
+	if patchErr != nil {
+		t.Errorf("patch error: %v", patchErr)
+		return nil
+	}
+	return patch
+}
+
+func patchRotateCertBundle(t *testing.T, rotated *[]string, err error) *mpatch.Patch {
+	patch, patchErr := mpatch.PatchMethod(certmgr.RotateCertBundle, func(cb *cmapi.Certificate) error {
+		*rotated = append(*rotated, cb.Name)
+		return err
+	})
+	if patchErr != nil {
+		t.Errorf("patch error: %v", patchErr)
+		return nil
+	}
+	return patch
+}
+
+func TestStatusCertCmd(t *testing.T) {
+	out := &bytes.Buffer{}
+	certStdout = out
+	defer func() { certStdout = nil }()
+
+	cases := []struct {
+		funcBeforeTest func() []*mpatch.Patch
+		wantErr        error
+		wantLines      [][]string
+	}{
+		{
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{patchListCertBundles(t, testError)}
+			},
+			wantErr: testError,
+		},
+		{
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{patchListCertBundles(t, nil), patchGetCertStatus(t, testError)}
+			},
+			wantErr: testError,
+		},
+		{
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{patchListCertBundles(t, nil), patchGetCertStatus(t, nil)}
+			},
+			wantLines: [][]string{
+				{"registry", "server", "CN=registry", "localhost,10.10.10.10", "CN=ca", time.Now().Add(48 * time.Hour).Format("2006-01-02"), "(2d)", "Expiring"},
+				{"workflow", "server", "CN=workflow", "localhost,10.10.10.10", "CN=ca", time.Now().Add(48 * time.Hour).Format("2006-01-02"), "(2d)", "Expiring"},
+			},
+		},
+	}
+
+	for n, testCase := range cases {
+		t.Logf("%s case %d start", getFuncName(), n)
+		func() {
+			out.Reset()
+			pList := testCase.funcBeforeTest()
+			defer unpatchAll(t, pList)
+			err := statusCertCmd.RunE(nil, nil)
+			if !isWantedError(err, testCase.wantErr) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if testCase.wantLines == nil {
+				return
+			}
+			lines := strings.Split(out.String(), "\n")
+			for i, want := range testCase.wantLines {
+				if got := strings.Fields(lines[3+i]); strings.Join(got, " ") != strings.Join(want, " ") {
+					t.Errorf("Unexpected line %v, want %v", got, want)
+				}
+			}
+		}()
+		t.Logf("%s case %d End", getFuncName(), n)
+	}
+}
+
+func TestRotateCertCmd(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := eputils.WriteStringToFile("", kubeconfig); err != nil {
+		t.Fatal(err)
+	}
+	rotated := []string{}
+	cases := []struct {
+		args           []string
+		kubeconfig     string
+		funcBeforeTest func() []*mpatch.Patch
+		wantErr        error
+		wantRotated    []string
+	}{
+		{
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{patchListCertBundles(t, testError)}
+			},
+			wantErr:     testError,
+			wantRotated: []string{},
+		},
+		{
+			args: []string{"unknown"},
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{patchListCertBundles(t, nil)}
+			},
+			wantErr:     eputils.GetError("errNoCertBundle"),
+			wantRotated: []string{},
+		},
+		{
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{patchListCertBundles(t, nil), patchRotateCertBundle(t, &rotated, testError)}
+			},
+			wantErr:     testError,
+			wantRotated: []string{"registry"},
+		},
+		{
+			args:       []string{"workflow"},
+			kubeconfig: kubeconfig + ".notfound",
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{patchListCertBundles(t, nil), patchRotateCertBundle(t, &rotated, nil), patchEpWfStart(t, testError)}
+			},
+			wantRotated: []string{"workflow"},
+		},
+		{
+			kubeconfig: kubeconfig,
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{patchListCertBundles(t, nil), patchRotateCertBundle(t, &rotated, nil), patchEpWfPreInit(t, nil, testError)}
+			},
+			wantErr:     testError,
+			wantRotated: []string{"registry", "workflow"},
+		},
+		{
+			kubeconfig: kubeconfig,
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{patchListCertBundles(t, nil), patchRotateCertBundle(t, &rotated, nil), patchEpWfPreInit(t, nil, nil), patchEpWfStart(t, testError)}
+			},
+			wantErr:     testError,
+			wantRotated: []string{"registry", "workflow"},
+		},
+		{
+			kubeconfig: kubeconfig,
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{patchListCertBundles(t, nil), patchRotateCertBundle(t, &rotated, nil), patchEpWfPreInit(t, nil, nil), patchEpWfStart(t, nil)}
+			},
+			wantRotated: []string{"registry", "workflow"},
+		},
+	}
+
+	for n, testCase := range cases {
+		t.Logf("%s case %d start", getFuncName(), n)
+		func() {
+			rotated = []string{}
+			certKubeConfig = testCase.kubeconfig
+			pList := testCase.funcBeforeTest()
+			defer unpatchAll(t, pList)
+			err := rotateCertCmd.RunE(nil, testCase.args)
+			if !isWantedError(err, testCase.wantErr) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if strings.Join(rotated, ",") != strings.Join(testCase.wantRotated, ",") {
+				t.Errorf("Unexpected rotated bundles %v", rotated)
+			}
+		}()
+		t.Logf("%s case %d End", getFuncName(), n)
+	}
+
+	t.Log("Done")
+}
@@ -22,6 +22,10 @@ var (
 )
 
 func check_cluster_cmd() error {
+	// The kubeconfig is not used when only printing the workflow.
+	if wfDryRun {
+		return nil
+	}
 	if _, err := os.Stat(clusterKubeConfig); os.IsNotExist(err) {
 		return err
 	}
@@ -192,6 +196,7 @@ func init() {
 	clusterCmd.PersistentFlags().StringVar(&clusterExportKubeConfig, "export-kubeconfig", GetDefaultKubeConfig(), "export kubeconfig file path")
 	clusterCmd.PersistentFlags().StringVar(&clusterKubeConfig, "kubeconfig", GetDefaultKubeConfig(), "kubeconfig file path")
 	clusterCmd.AddCommand(joinClusterCmd)
+	clusterCmd.PersistentFlags().BoolVar(&wfDryRun, "dry-run", false, "print the workflow steps without running them")
 
 	buildClusterCmd.PersistentFlags().BoolVarP(&forceDownload, "force-download", "f", false, "download images with always policy")
 }
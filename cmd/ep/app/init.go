@@ -79,6 +79,14 @@ func check_init_cmd() error {
 	}
 	if kitcfg.Parameters != nil {
 		files = append(files, kitcfg.Parameters.DefaultSSHKeyPath)
+		if kitcert := kitcfg.Parameters.Certificate; kitcert != nil {
+			if kitcert.Ca != nil {
+				files = append(files, kitcert.Ca.Cert, kitcert.Ca.Key)
+			}
+			if kitcert.Acme != nil {
+				files = append(files, kitcert.Acme.RootCa)
+			}
+		}
 	}
 
 	for _, file := range files {
@@ -96,6 +104,31 @@ func check_init_cmd() error {
 	return nil
 }
 
+// init_kit_certs applies the certificate parameters of the kit config: an
+// existing CA, or intermediate CA, issuing the certificates instead of the
+// self-signed root CA, and an ACME server issuing the registry certificate.
+func init_kit_certs() error {
+	if kitcfg.Parameters == nil || kitcfg.Parameters.Certificate == nil {
+		return nil
+	}
+	kitcert := kitcfg.Parameters.Certificate
+	if kitcert.Ca != nil && (kitcert.Ca.Cert != "" || kitcert.Ca.Key != "") {
+		if err := certmgr.ImportCA(kitcert.Ca.Cert, kitcert.Ca.Key, &initcerts, &registrycerts); err != nil {
+			log.Errorln("Failed to import CA:", err)
+			return err
+		}
+	}
+	if kitcert.Acme != nil && kitcert.Acme.Directory != "" {
+		registrycerts.Acme = &cmapi.CertificateAcme{
+			Directory:        kitcert.Acme.Directory,
+			Email:            kitcert.Acme.Email,
+			ChallengeAddress: kitcert.Acme.ChallengeAddress,
+			RootCa:           kitcert.Acme.RootCa,
+		}
+	}
+	return nil
+}
+
 func init_usercfg() {
 	if usercfg.Cluster == nil {
 		usercfg.Cluster = &epapiplugins.KitconfigCluster{}
@@ -177,6 +210,9 @@ func ep_init() error {
 	}
 
 	// check and gen certs
+	if err := init_kit_certs(); err != nil {
+		return err
+	}
 	if err := certmgr.GenCertAndConfig(initcerts, kitcfg.Parameters.GlobalSettings.ProviderIP); err != nil {
 		log.Error(err)
 		return err
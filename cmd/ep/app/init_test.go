@@ -342,6 +342,78 @@ func TestCheckInitCmd(t *testing.T) {
 	t.Log("Done")
 }
 
+func TestInitKitCerts(t *testing.T) {
+	imported := []string{}
+	cases := []struct {
+		kitcert      *epapiplugins.Certificate
+		importErr    error
+		wantError    error
+		wantImported []string
+		wantAcme     string
+	}{
+		{
+			wantImported: []string{},
+		},
+		{
+			kitcert:      &epapiplugins.Certificate{Ca: &epapiplugins.CertificateCa{Cert: "ca.pem", Key: "ca-key.pem"}},
+			importErr:    testError,
+			wantError:    testError,
+			wantImported: []string{"workflow", "registry"},
+		},
+		{
+			kitcert: &epapiplugins.Certificate{
+				Ca:   &epapiplugins.CertificateCa{Cert: "ca.pem", Key: "ca-key.pem"},
+				Acme: &epapiplugins.CertificateAcme{Directory: "https://acme.example.com/directory"},
+			},
+			wantImported: []string{"workflow", "registry"},
+			wantAcme:     "https://acme.example.com/directory",
+		},
+		{
+			kitcert:      &epapiplugins.Certificate{Acme: &epapiplugins.CertificateAcme{Directory: "https://acme.example.com/directory"}},
+			wantImported: []string{},
+			wantAcme:     "https://acme.example.com/directory",
+		},
+	}
+
+	for n, testCase := range cases {
+		t.Logf("%s case %d start", getFuncName(), n)
+		func() {
+			imported = []string{}
+			registrycerts.Acme = nil
+			kitcfg = epapiplugins.Kitconfig{Parameters: &epapiplugins.KitconfigParameters{Certificate: testCase.kitcert}}
+			patch, err := mpatch.PatchMethod(certmgr.ImportCA, func(caCert, caKey string, bundles ...*cmapi.Certificate) error {
+				for _, cb := range bundles {
+					imported = append(imported, cb.Name)
+				}
+				return testCase.importErr
+			})
+			if err != nil {
+				t.Fatalf("patch error: %v", err)
+			}
+			defer unpatchAll(t, []*mpatch.Patch{patch})
+
+			err = init_kit_certs()
+			if !isWantedError(err, testCase.wantError) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if strings.Join(imported, ",") != strings.Join(testCase.wantImported, ",") {
+				t.Errorf("Unexpected imported bundles %v", imported)
+			}
+			acme := ""
+			if registrycerts.Acme != nil {
+				acme = registrycerts.Acme.Directory
+			}
+			if acme != testCase.wantAcme {
+				t.Errorf("Unexpected ACME directory %s", acme)
+			}
+		}()
+		t.Logf("%s case %d End", getFuncName(), n)
+	}
+	registrycerts.Acme = nil
+
+	t.Log("Done")
+}
+
 func TestInitCmd(t *testing.T) {
 	cases := []struct {
 		funcBeforeTest func() []*mpatch.Patch
@@ -129,4 +129,5 @@ func init() {
 	osDeployCmd.AddCommand(osDeployStartCmd)
 	osDeployCmd.AddCommand(osDeployStopCmd)
 	osDeployCmd.AddCommand(osDeployCleanupCmd)
+	osDeployCmd.PersistentFlags().BoolVar(&wfDryRun, "dry-run", false, "print the workflow steps without running them")
 }
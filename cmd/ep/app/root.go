@@ -8,12 +8,28 @@
 package app
 
 import (
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"os"
 )
 
-var verbose bool
+var (
+	verbose  bool
+	wfResume bool
+	wfDryRun bool
+	output   string
+	evFile   string
+	wfPlugin string
+)
+
+const (
+	outputText = "text"
+	outputJson = "json"
+
+	// Exit code of a workflow cancelled by SIGINT or SIGTERM.
+	EXIT_CODE_CANCELLED = 130
+)
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -25,7 +41,11 @@ var rootCmd = &cobra.Command{
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	cobra.CheckErr(rootCmd.Execute())
+	err := rootCmd.Execute()
+	if err == eputils.GetError("errWorkflowCancel") {
+		os.Exit(EXIT_CODE_CANCELLED)
+	}
+	cobra.CheckErr(err)
 }
 
 func InitConfig() {
@@ -34,7 +54,12 @@ func InitConfig() {
 	} else {
 		log.SetLevel(log.InfoLevel)
 	}
-	log.SetOutput(os.Stdout)
+	if output == outputJson {
+		// Keep stdout for the JSON lines event stream.
+		log.SetOutput(os.Stderr)
+	} else {
+		log.SetOutput(os.Stdout)
+	}
 	log.SetFormatter(&log.TextFormatter{
 		DisableColors: false,
 		FullTimestamp: false,
@@ -50,6 +75,10 @@ func init() {
 	// when this action is called directly.
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging")
+	rootCmd.PersistentFlags().BoolVar(&wfResume, "resume", false, "Resume the workflow from its last checkpoint, skip the finished steps whose inputs are not changed")
+	rootCmd.PersistentFlags().StringVar(&output, "output", outputText, "Output format of the workflow, text or json (JSON lines event stream on stdout)")
+	rootCmd.PersistentFlags().StringVar(&wfPlugin, "plugin-dir", "addon/plugins", "Directory of the standalone plugin executables, which are run instead of the built-in plugins of the same name")
+	rootCmd.PersistentFlags().StringVar(&evFile, "event-file", "", "Write the workflow events as JSON lines into this file")
 
 	cobra.OnInitialize(InitConfig)
 }
@@ -8,8 +8,10 @@
 package app
 
 import (
+	"fmt"
 	"github.com/intel/edge-conductor/pkg/eputils"
 	"os"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -17,15 +19,40 @@ import (
 
 var (
 	serviceKubeConfig string
+	rollbackRevision  int
+	serviceOnly       []string
+	serviceExclude    []string
+	serviceListFormat string
+)
+
+const (
+	outputTable = "table"
+	outputYaml  = "yaml"
 )
 
 func check_service_cmd() error {
+	// The kubeconfig is not used when only printing the workflow.
+	if wfDryRun {
+		return nil
+	}
 	if _, err := os.Stat(serviceKubeConfig); os.IsNotExist(err) {
 		return err
 	}
 	return nil
 }
 
+// serviceFilterCmdline adds the names of the services to operate on to the
+// command line.
+func serviceFilterCmdline(cmdline string) string {
+	if len(serviceOnly) > 0 {
+		cmdline = eputils.AddCmdline(cmdline, "only="+strings.Join(serviceOnly, ","))
+	}
+	if len(serviceExclude) > 0 {
+		cmdline = eputils.AddCmdline(cmdline, "exclude="+strings.Join(serviceExclude, ","))
+	}
+	return cmdline
+}
+
 // deployCmd represents deploy command
 var serviceCmd = &cobra.Command{
 	Use:   "service",
@@ -49,7 +76,7 @@ var buildServiceCmd = &cobra.Command{
 		}
 		paramsInject = map[string]string{
 			Epkubeconfig: serviceKubeConfig,
-			Epcmdline:    Epcmd,
+			Epcmdline:    serviceFilterCmdline(Epcmd),
 		}
 
 		epParams, err := EpWfPreInit(nil, paramsInject)
@@ -97,6 +124,7 @@ var deployServiceCmd = &cobra.Command{
 		}
 		paramsInject := map[string]string{
 			Epkubeconfig: serviceKubeConfig,
+			Epcmdline:    serviceFilterCmdline(""),
 		}
 		epParams, err := EpWfPreInit(nil, paramsInject)
 		if err != nil {
@@ -119,8 +147,19 @@ var deployServiceCmd = &cobra.Command{
 var listServiceCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List Planned Services.",
-	Long:  `Show the list of services planned to be deployed on the cluster.`,
+	Long: `Show the list of services planned to be deployed on the cluster, with
+their status, revision, deployed version, pod readiness, restarts and image
+digests read from the cluster.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		switch serviceListFormat {
+		case outputTable:
+		case outputJson, outputYaml:
+			// Keep stdout for the service list.
+			log.SetOutput(os.Stderr)
+		default:
+			log.Errorf("Unknown output format %s", serviceListFormat)
+			return eputils.GetError("errParameter")
+		}
 		log.Infoln(PROJECTNAME, "- List Services")
 		log.Infoln("==")
 
@@ -130,6 +169,7 @@ var listServiceCmd = &cobra.Command{
 		}
 		paramsInject := map[string]string{
 			Epkubeconfig: serviceKubeConfig,
+			Epcmdline:    serviceFilterCmdline(eputils.AddCmdline("", "format="+serviceListFormat)),
 		}
 		epParams, err := EpWfPreInit(nil, paramsInject)
 		if err != nil {
@@ -148,12 +188,135 @@ var listServiceCmd = &cobra.Command{
 	},
 }
 
+//nolint: dupl
+var diffServiceCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show Service Differences.",
+	Long: `Compare the service config with the services deployed on the cluster, without changing anything.
+Show the services to be added or removed, the services whose config changed since the last deploy,
+and the services whose Helm release or objects on the cluster no longer match the last deploy.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log.Infoln(PROJECTNAME, "- Diff Services")
+		log.Infoln("==")
+
+		if err := check_service_cmd(); err != nil {
+			log.Errorln("Invalid command line:", err)
+			return err
+		}
+		paramsInject := map[string]string{
+			Epkubeconfig: serviceKubeConfig,
+		}
+		epParams, err := EpWfPreInit(nil, paramsInject)
+		if err != nil {
+			log.Errorln("Failed to init workflow:", err)
+			return err
+		}
+
+		if err := EpWfStart(epParams, "service-diff"); err != nil {
+			log.Errorln("Failed to start workflow:", err)
+			return err
+		}
+
+		log.Infoln("==")
+		log.Infoln("Done")
+		return nil
+	},
+}
+
+//nolint: dupl
+var rollbackServiceCmd = &cobra.Command{
+	Use:   "rollback <name>",
+	Short: "Roll Back a Service.",
+	Long: `Roll back a deployed service to a revision recorded in its history, the previous one by default.
+Helm services are rolled back with the Helm release history, yaml services are applied again
+from the recorded URL.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log.Infoln(PROJECTNAME, "- Roll Back Service")
+		log.Infoln("==")
+
+		if err := check_service_cmd(); err != nil {
+			log.Errorln("Invalid command line:", err)
+			return err
+		}
+		Epcmd := eputils.AddCmdline("", "service="+args[0])
+		if rollbackRevision > 0 {
+			Epcmd = eputils.AddCmdline(Epcmd, fmt.Sprintf("revision=%d", rollbackRevision))
+		}
+		paramsInject := map[string]string{
+			Epkubeconfig: serviceKubeConfig,
+			Epcmdline:    Epcmd,
+		}
+		epParams, err := EpWfPreInit(nil, paramsInject)
+		if err != nil {
+			log.Errorln("Failed to init workflow:", err)
+			return err
+		}
+
+		if err := EpWfStart(epParams, "service-rollback"); err != nil {
+			log.Errorln("Failed to start workflow:", err)
+			return err
+		}
+
+		log.Infoln("==")
+		log.Infoln("Done")
+		return nil
+	},
+}
+
+//nolint: dupl
+var removeServiceCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a Service.",
+	Long: `Uninstall a deployed service from the cluster and remove it from the deployed services.
+The service is deployed again by the next service deploy if it is still in the service config,
+use "service deploy --exclude <name>" to keep it removed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log.Infoln(PROJECTNAME, "- Remove Service")
+		log.Infoln("==")
+
+		if err := check_service_cmd(); err != nil {
+			log.Errorln("Invalid command line:", err)
+			return err
+		}
+		paramsInject := map[string]string{
+			Epkubeconfig: serviceKubeConfig,
+			Epcmdline:    eputils.AddCmdline("", "remove="+args[0]),
+		}
+		epParams, err := EpWfPreInit(nil, paramsInject)
+		if err != nil {
+			log.Errorln("Failed to init workflow:", err)
+			return err
+		}
+
+		if err := EpWfStart(epParams, "service-remove"); err != nil {
+			log.Errorln("Failed to start workflow:", err)
+			return err
+		}
+
+		log.Infoln("==")
+		log.Infoln("Done")
+		return nil
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(serviceCmd)
 	serviceCmd.AddCommand(buildServiceCmd)
 	serviceCmd.AddCommand(deployServiceCmd)
 	serviceCmd.AddCommand(listServiceCmd)
+	serviceCmd.AddCommand(diffServiceCmd)
+	serviceCmd.AddCommand(rollbackServiceCmd)
+	serviceCmd.AddCommand(removeServiceCmd)
 	serviceCmd.PersistentFlags().StringVar(&serviceKubeConfig, "kubeconfig", GetDefaultKubeConfig(), "kubeconfig file path")
+	serviceCmd.PersistentFlags().BoolVar(&wfDryRun, "dry-run", false, "print the workflow steps without running them")
 
 	buildServiceCmd.PersistentFlags().BoolVarP(&forceDownload, "force-download", "f", false, "download images with always policy")
+	for _, c := range []*cobra.Command{buildServiceCmd, deployServiceCmd, listServiceCmd} {
+		c.Flags().StringSliceVar(&serviceOnly, "only", nil, "names of the only services to operate on, separated by commas")
+		c.Flags().StringSliceVar(&serviceExclude, "exclude", nil, "names of the services not to operate on, separated by commas")
+	}
+	listServiceCmd.Flags().StringVarP(&serviceListFormat, "format", "o", outputTable, "output format of the service list, table, json or yaml")
+	rollbackServiceCmd.Flags().IntVar(&rollbackRevision, "revision", 0, "revision to roll back to, the previous one if not set")
 }
@@ -11,6 +11,9 @@ import (
 	"os"
 	"testing"
 
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+
+	log "github.com/sirupsen/logrus"
 	mpatch "github.com/undefinedlabs/go-mpatch"
 )
 
@@ -267,6 +270,37 @@ func TestListServiceCmd(t *testing.T) {
 				}
 			},
 		},
+		{
+			funcBeforeTest: func() []*mpatch.Patch {
+				serviceListFormat = "xml"
+				return nil
+			},
+			isFunctionCorrectly: func(err error) {
+				serviceListFormat = outputTable
+				if !isWantedError(err, eputils.GetError("errParameter")) {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			funcBeforeTest: func() []*mpatch.Patch {
+				serviceListFormat = outputJson
+				patchCheckServiceCmd := patchCheckServiceCmd(t, nil)
+				patchEpWfPreInit := patchEpWfPreInit(t, nil, nil)
+				patchEpWfStart := patchEpWfStart(t, nil)
+				return []*mpatch.Patch{patchCheckServiceCmd, patchEpWfPreInit, patchEpWfStart}
+			},
+			isFunctionCorrectly: func(err error) {
+				serviceListFormat = outputTable
+				if log.StandardLogger().Out != os.Stderr {
+					t.Error("Expected the log on stderr.")
+				}
+				log.SetOutput(os.Stdout)
+				if !isWantedError(err, nil) {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			},
+		},
 	}
 
 	for n, testCase := range cases {
@@ -283,3 +317,232 @@ func TestListServiceCmd(t *testing.T) {
 
 	t.Log("Done")
 }
+
+func TestDiffServiceCmd(t *testing.T) {
+	cases := []struct {
+		funcBeforeTest      func() []*mpatch.Patch
+		isFunctionCorrectly func(err error)
+	}{
+		{
+			funcBeforeTest: func() []*mpatch.Patch {
+				patch := patchCheckServiceCmd(t, testError)
+				return []*mpatch.Patch{patch}
+			},
+			isFunctionCorrectly: func(err error) {
+				if !isWantedError(err, testError) {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			funcBeforeTest: func() []*mpatch.Patch {
+				patchCheckServiceCmd := patchCheckServiceCmd(t, nil)
+				patchEpWfPreInit := patchEpWfPreInit(t, nil, testError)
+				return []*mpatch.Patch{patchCheckServiceCmd, patchEpWfPreInit}
+			},
+			isFunctionCorrectly: func(err error) {
+				if !isWantedError(err, testError) {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			funcBeforeTest: func() []*mpatch.Patch {
+				patchCheckServiceCmd := patchCheckServiceCmd(t, nil)
+				patchEpWfPreInit := patchEpWfPreInit(t, nil, nil)
+				patchEpWfStart := patchEpWfStart(t, testError)
+				return []*mpatch.Patch{patchCheckServiceCmd, patchEpWfPreInit, patchEpWfStart}
+			},
+			isFunctionCorrectly: func(err error) {
+				if !isWantedError(err, testError) {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			funcBeforeTest: func() []*mpatch.Patch {
+				patchCheckServiceCmd := patchCheckServiceCmd(t, nil)
+				patchEpWfPreInit := patchEpWfPreInit(t, nil, nil)
+				patchEpWfStart := patchEpWfStart(t, nil)
+				return []*mpatch.Patch{patchCheckServiceCmd, patchEpWfPreInit, patchEpWfStart}
+			},
+			isFunctionCorrectly: func(err error) {
+				if !isWantedError(err, nil) {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			},
+		},
+	}
+
+	for n, testCase := range cases {
+		t.Logf("%s case %d start", getFuncName(), n)
+		func() {
+			if testCase.funcBeforeTest != nil {
+				pList := testCase.funcBeforeTest()
+				defer unpatchAll(t, pList)
+			}
+			testCase.isFunctionCorrectly(diffServiceCmd.RunE(nil, nil))
+		}()
+		t.Logf("%s case %d End", getFuncName(), n)
+	}
+
+	t.Log("Done")
+}
+
+func TestRollbackServiceCmd(t *testing.T) {
+	cases := []struct {
+		funcBeforeTest      func() []*mpatch.Patch
+		isFunctionCorrectly func(err error)
+	}{
+		{
+			funcBeforeTest: func() []*mpatch.Patch {
+				patch := patchCheckServiceCmd(t, testError)
+				return []*mpatch.Patch{patch}
+			},
+			isFunctionCorrectly: func(err error) {
+				if !isWantedError(err, testError) {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			funcBeforeTest: func() []*mpatch.Patch {
+				patchCheckServiceCmd := patchCheckServiceCmd(t, nil)
+				patchEpWfPreInit := patchEpWfPreInit(t, nil, testError)
+				return []*mpatch.Patch{patchCheckServiceCmd, patchEpWfPreInit}
+			},
+			isFunctionCorrectly: func(err error) {
+				if !isWantedError(err, testError) {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			funcBeforeTest: func() []*mpatch.Patch {
+				patchCheckServiceCmd := patchCheckServiceCmd(t, nil)
+				patchEpWfPreInit := patchEpWfPreInit(t, nil, nil)
+				patchEpWfStart := patchEpWfStart(t, testError)
+				return []*mpatch.Patch{patchCheckServiceCmd, patchEpWfPreInit, patchEpWfStart}
+			},
+			isFunctionCorrectly: func(err error) {
+				if !isWantedError(err, testError) {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			funcBeforeTest: func() []*mpatch.Patch {
+				patchCheckServiceCmd := patchCheckServiceCmd(t, nil)
+				patchEpWfPreInit := patchEpWfPreInit(t, nil, nil)
+				patchEpWfStart := patchEpWfStart(t, nil)
+				return []*mpatch.Patch{patchCheckServiceCmd, patchEpWfPreInit, patchEpWfStart}
+			},
+			isFunctionCorrectly: func(err error) {
+				if !isWantedError(err, nil) {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			},
+		},
+	}
+
+	for n, testCase := range cases {
+		t.Logf("%s case %d start", getFuncName(), n)
+		func() {
+			if testCase.funcBeforeTest != nil {
+				pList := testCase.funcBeforeTest()
+				defer unpatchAll(t, pList)
+			}
+			testCase.isFunctionCorrectly(rollbackServiceCmd.RunE(nil, []string{"service"}))
+		}()
+		t.Logf("%s case %d End", getFuncName(), n)
+	}
+
+	t.Log("Done")
+}
+
+func TestRemoveServiceCmd(t *testing.T) {
+	cases := []struct {
+		funcBeforeTest      func() []*mpatch.Patch
+		isFunctionCorrectly func(err error)
+	}{
+		{
+			funcBeforeTest: func() []*mpatch.Patch {
+				patch := patchCheckServiceCmd(t, testError)
+				return []*mpatch.Patch{patch}
+			},
+			isFunctionCorrectly: func(err error) {
+				if !isWantedError(err, testError) {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			funcBeforeTest: func() []*mpatch.Patch {
+				patchCheckServiceCmd := patchCheckServiceCmd(t, nil)
+				patchEpWfPreInit := patchEpWfPreInit(t, nil, testError)
+				return []*mpatch.Patch{patchCheckServiceCmd, patchEpWfPreInit}
+			},
+			isFunctionCorrectly: func(err error) {
+				if !isWantedError(err, testError) {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			funcBeforeTest: func() []*mpatch.Patch {
+				patchCheckServiceCmd := patchCheckServiceCmd(t, nil)
+				patchEpWfPreInit := patchEpWfPreInit(t, nil, nil)
+				patchEpWfStart := patchEpWfStart(t, testError)
+				return []*mpatch.Patch{patchCheckServiceCmd, patchEpWfPreInit, patchEpWfStart}
+			},
+			isFunctionCorrectly: func(err error) {
+				if !isWantedError(err, testError) {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			funcBeforeTest: func() []*mpatch.Patch {
+				patchCheckServiceCmd := patchCheckServiceCmd(t, nil)
+				patchEpWfPreInit := patchEpWfPreInit(t, nil, nil)
+				patchEpWfStart := patchEpWfStart(t, nil)
+				return []*mpatch.Patch{patchCheckServiceCmd, patchEpWfPreInit, patchEpWfStart}
+			},
+			isFunctionCorrectly: func(err error) {
+				if !isWantedError(err, nil) {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			},
+		},
+	}
+
+	for n, testCase := range cases {
+		t.Logf("%s case %d start", getFuncName(), n)
+		func() {
+			if testCase.funcBeforeTest != nil {
+				pList := testCase.funcBeforeTest()
+				defer unpatchAll(t, pList)
+			}
+			testCase.isFunctionCorrectly(removeServiceCmd.RunE(nil, []string{"service"}))
+		}()
+		t.Logf("%s case %d End", getFuncName(), n)
+	}
+
+	t.Log("Done")
+}
+
+func TestListServiceCmdFlags(t *testing.T) {
+	if err := listServiceCmd.ParseFlags([]string{"--output", outputJson, "-o", outputYaml}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer func() {
+		output = outputText
+		serviceListFormat = outputTable
+	}()
+	if output != outputJson {
+		t.Errorf("Expected the workflow output %s, got %s", outputJson, output)
+	}
+	if serviceListFormat != outputYaml {
+		t.Errorf("Expected the service list format %s, got %s", outputYaml, serviceListFormat)
+	}
+}
@@ -25,8 +25,18 @@ func EpWfStart(epParams *epapiplugins.EpParams, name string) error {
 	if kitcfg == nil {
 		return eputils.GetError("errKitConfig")
 	}
+	wf.PluginDir = wfPlugin
+	if wfDryRun {
+		return wf.DryRun(name, WfConfig)
+	}
 	address := fmt.Sprintf("%s:%s", kitcfg.Parameters.GlobalSettings.ProviderIP, kitcfg.Parameters.GlobalSettings.WorkflowPort)
 	plugin.Address = address
+	wf.Resume = wfResume
+	closeEvents, err := setEventOutput()
+	if err != nil {
+		return err
+	}
+	defer closeEvents()
 
 	addonbin := "addon/bin/conductor-plugin"
 
@@ -59,6 +69,37 @@ func EpWfStart(epParams *epapiplugins.EpParams, name string) error {
 	}
 }
 
+// setEventOutput sets where the workflow server writes its events, and returns
+// the function to close the event file.
+func setEventOutput() (func(), error) {
+	wf.EventOutput = nil
+	switch output {
+	case outputText:
+	case outputJson:
+		wf.EventOutput = os.Stdout
+	default:
+		log.Errorf("Unknown output format %s", output)
+		return nil, eputils.GetError("errParameter")
+	}
+	if len(evFile) == 0 {
+		return func() {}, nil
+	}
+	if wf.EventOutput != nil {
+		log.Errorln("--event-file can not be used with --output json")
+		return nil, eputils.GetError("errParameter")
+	}
+	f, err := os.OpenFile(evFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		log.Errorln("Failed to open event file", evFile, err)
+		return nil, err
+	}
+	wf.EventOutput = f
+	return func() {
+		wf.EventOutput = nil
+		f.Close()
+	}, nil
+}
+
 func setHostIptoNoProxy(input_ep_params *epapiplugins.EpParams) error {
 	no_proxy := os.Getenv("no_proxy")
 	if input_ep_params == nil {
@@ -102,6 +102,7 @@ func TestEpWfStart(t *testing.T) {
 		wantError           error
 		epParams            *epapiplugins.EpParams
 		name                string
+		dryRun              bool
 		isFunctionCorrectly func(err, wantError error)
 	}{
 		{
@@ -180,6 +181,43 @@ func TestEpWfStart(t *testing.T) {
 				return []*mpatch.Patch{patch}
 			},
 		},
+		{
+			wantError: nil,
+			epParams: &epapiplugins.EpParams{
+				Kitconfig: &epapiplugins.Kitconfig{
+					Parameters: &epapiplugins.KitconfigParameters{
+						GlobalSettings: &epapiplugins.KitconfigParametersGlobalSettings{
+							ProviderIP:   "localhost",
+							WorkflowPort: "8228",
+						},
+					},
+				},
+			},
+			isFunctionCorrectly: isFunctionCorrectlyFunc,
+			name:                "test_dry_run",
+			dryRun:              true,
+			funcBeforeTest: func() []*mpatch.Patch {
+				patchStart, patchErr := mpatch.PatchMethod(wf.Start, func(name string, address string, configFile string) error {
+					t.Errorf("The workflow should not be started in dry run")
+					return nil
+				})
+				if patchErr != nil {
+					t.Errorf("patch error: %v", patchErr)
+					return nil
+				}
+				patchDryRun, patchErr := mpatch.PatchMethod(wf.DryRun, func(name string, configFile string) error {
+					if name != "test_dry_run" || configFile != WfConfig {
+						t.Errorf("The parameters of the workflow.DryRun function are not expected")
+					}
+					return nil
+				})
+				if patchErr != nil {
+					t.Errorf("patch error: %v", patchErr)
+					return nil
+				}
+				return []*mpatch.Patch{patchStart, patchDryRun}
+			},
+		},
 	}
 
 	for n, testCase := range cases {
@@ -189,6 +227,8 @@ func TestEpWfStart(t *testing.T) {
 				pList := testCase.funcBeforeTest()
 				defer unpatchAll(t, pList)
 			}
+			wfDryRun = testCase.dryRun
+			defer func() { wfDryRun = false }()
 			err := EpWfStart(testCase.epParams, testCase.name)
 			testCase.isFunctionCorrectly(err, testCase.wantError)
 		}()
@@ -282,6 +322,61 @@ func TestSetHostIptoNoProxy(t *testing.T) {
 	t.Log("Done")
 }
 
+func TestSetEventOutput(t *testing.T) {
+	evfile := filepath.Join(t.TempDir(), "events.json")
+	cases := []struct {
+		output, evFile string
+		wantError      error
+		wantOutput     func() bool
+	}{
+		{
+			output:     outputText,
+			wantOutput: func() bool { return wf.EventOutput == nil },
+		},
+		{
+			output:     outputJson,
+			wantOutput: func() bool { return wf.EventOutput == os.Stdout },
+		},
+		{
+			output:    "xml",
+			wantError: eputils.GetError("errParameter"),
+		},
+		{
+			output:    outputJson,
+			evFile:    evfile,
+			wantError: eputils.GetError("errParameter"),
+		},
+		{
+			output:     outputText,
+			evFile:     evfile,
+			wantOutput: func() bool { return wf.EventOutput != nil && wf.EventOutput.Name() == evfile },
+		},
+	}
+
+	defer func() {
+		output = outputText
+		evFile = ""
+		wf.EventOutput = nil
+	}()
+	for n, testCase := range cases {
+		t.Logf("%s case %d start", getFuncName(), n)
+		output = testCase.output
+		evFile = testCase.evFile
+		closeEvents, err := setEventOutput()
+		if !isWantedError(err, testCase.wantError) {
+			t.Errorf("expected error: %v, but function returned error: %v", testCase.wantError, err)
+		}
+		if testCase.wantOutput != nil && !testCase.wantOutput() {
+			t.Errorf("Unexpected event output: %v", wf.EventOutput)
+		}
+		if closeEvents != nil {
+			closeEvents()
+		}
+		t.Logf("%s case %d End", getFuncName(), n)
+	}
+	t.Log("Done")
+}
+
 func TestEpWfPreInit(t *testing.T) {
 	isFunctionCorrectlyFunc := func(output *epapiplugins.EpParams, err, wantError error) {
 		if !isWantedError(err, wantError) {
@@ -11,6 +11,7 @@ import (
 	epapp "github.com/intel/edge-conductor/cmd/ep/app"
 	epapiplugins "github.com/intel/edge-conductor/pkg/api/plugins"
 	"github.com/intel/edge-conductor/pkg/epplugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
 	plugin "github.com/intel/edge-conductor/pkg/plugin"
 	log "github.com/sirupsen/logrus"
 	"os"
@@ -54,6 +55,10 @@ func main() {
 	pInit := plugin.New("__init__", epparams, nil)
 	for i := 0; i < CONNECT_RETRY; i++ {
 		err := pInit.Connect(plugin.Address)
+		if err == eputils.GetError("errPluginVersion") {
+			log.Errorf("Connect Server Err and exit: %v", err)
+			os.Exit(1)
+		}
 		if err != nil {
 			log.Debugf("Connect Server (retry #%d) : %v", i, err)
 			time.Sleep(1 * time.Second)
@@ -27,6 +27,9 @@ import (
 // swagger:model certificate
 type Certificate struct {
 
+	// acme
+	Acme *CertificateAcme `json:"acme,omitempty"`
+
 	// ca
 	Ca *CertificateCa `json:"ca,omitempty"`
 
@@ -44,6 +47,10 @@ type Certificate struct {
 func (m *Certificate) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateAcme(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateCa(formats); err != nil {
 		res = append(res, err)
 	}
@@ -62,6 +69,25 @@ func (m *Certificate) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *Certificate) validateAcme(formats strfmt.Registry) error {
+	if swag.IsZero(m.Acme) { // not required
+		return nil
+	}
+
+	if m.Acme != nil {
+		if err := m.Acme.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("acme")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("acme")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *Certificate) validateCa(formats strfmt.Registry) error {
 	if swag.IsZero(m.Ca) { // not required
 		return nil
@@ -123,6 +149,10 @@ func (m *Certificate) validateServer(formats strfmt.Registry) error {
 func (m *Certificate) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.contextValidateAcme(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.contextValidateCa(ctx, formats); err != nil {
 		res = append(res, err)
 	}
@@ -141,6 +171,22 @@ func (m *Certificate) ContextValidate(ctx context.Context, formats strfmt.Regist
 	return nil
 }
 
+func (m *Certificate) contextValidateAcme(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.Acme != nil {
+		if err := m.Acme.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("acme")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("acme")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *Certificate) contextValidateCa(ctx context.Context, formats strfmt.Registry) error {
 
 	if m.Ca != nil {
@@ -207,6 +253,91 @@ func (m *Certificate) UnmarshalBinary(b []byte) error {
 	return nil
 }
 
+// CertificateAcme certificate acme
+//
+// swagger:model CertificateAcme
+type CertificateAcme struct {
+
+	// challenge address
+	ChallengeAddress string `json:"challenge_address,omitempty"`
+
+	// directory
+	// Pattern: (?:(?:https?|http|ftp|file|oci)://|www.|ftp.)(?:([-A-Z0-9+&@#/%=~_|$?!:,.]*)|[-A-Z0-9+&@#/%=~_|$?!:,.])*(?:([-A-Z0-9+&@#/%=~_|$?!:,.]*)|[A-Z0-9+&@#/%=~_|$])
+	Directory string `json:"directory,omitempty"`
+
+	// email
+	Email string `json:"email,omitempty"`
+
+	// root ca
+	// Pattern: ^[a-zA-Z.\/][a-zA-Z0-9-_.\/]*$
+	RootCa string `json:"root_ca,omitempty"`
+}
+
+// Validate validates this certificate acme
+func (m *CertificateAcme) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateDirectory(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateRootCa(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *CertificateAcme) validateDirectory(formats strfmt.Registry) error {
+	if swag.IsZero(m.Directory) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("acme"+"."+"directory", "body", m.Directory, `(?:(?:https?|http|ftp|file|oci)://|www.|ftp.)(?:([-A-Z0-9+&@#/%=~_|$?!:,.]*)|[-A-Z0-9+&@#/%=~_|$?!:,.])*(?:([-A-Z0-9+&@#/%=~_|$?!:,.]*)|[A-Z0-9+&@#/%=~_|$])`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *CertificateAcme) validateRootCa(formats strfmt.Registry) error {
+	if swag.IsZero(m.RootCa) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("acme"+"."+"root_ca", "body", m.RootCa, `^[a-zA-Z.\/][a-zA-Z0-9-_.\/]*$`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ContextValidate validates this certificate acme based on context it is used
+func (m *CertificateAcme) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *CertificateAcme) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *CertificateAcme) UnmarshalBinary(b []byte) error {
+	var res CertificateAcme
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
+
 // CertificateCa certificate ca
 //
 // swagger:model CertificateCa
@@ -353,11 +353,17 @@ type ExecspecSpecStepsItems0 struct {
 	// commands
 	Commands []*ExecspecSpecStepsItems0CommandsItems0 `json:"commands"`
 
+	// continue on error
+	ContinueOnError bool `json:"continueOnError,omitempty"`
+
 	// name
 	Name string `json:"name,omitempty"`
 
 	// nodes
 	Nodes *ExecspecSpecStepsItems0Nodes `json:"nodes,omitempty"`
+
+	// parallel
+	Parallel int64 `json:"parallel,omitempty"`
 }
 
 // Validate validates this execspec spec steps items0
@@ -503,6 +509,9 @@ type ExecspecSpecStepsItems0CommandsItems0 struct {
 	// cmd
 	Cmd []string `json:"cmd"`
 
+	// register
+	Register string `json:"register,omitempty"`
+
 	// type
 	Type string `json:"type,omitempty"`
 
@@ -40,6 +40,9 @@ type Component struct {
 	// chartversion
 	Chartversion string `json:"chartversion,omitempty"`
 
+	// depends on
+	DependsOn []string `json:"dependsOn"`
+
 	// executor
 	Executor *ComponentExecutor `json:"executor,omitempty"`
 
@@ -65,6 +68,9 @@ type Component struct {
 	// Pattern: ^[a-zA-Z_$][a-zA-Z_.\-$0-9]*$
 	Namespace string `json:"namespace,omitempty"`
 
+	// overlay
+	Overlay string `json:"overlay,omitempty"`
+
 	// resources
 	Resources []*ComponentResourcesItems0 `json:"resources"`
 
@@ -75,7 +81,7 @@ type Component struct {
 	SupportedClusters []string `json:"supported-clusters"`
 
 	// type
-	// Enum: [yaml helm repo dce]
+	// Enum: [yaml helm repo dce kustomize]
 	Type string `json:"type,omitempty"`
 
 	// url
@@ -98,6 +104,10 @@ func (m *Component) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateDependsOn(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateExecutor(formats); err != nil {
 		res = append(res, err)
 	}
@@ -168,6 +178,22 @@ func (m *Component) validateChartoverride(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *Component) validateDependsOn(formats strfmt.Registry) error {
+	if swag.IsZero(m.DependsOn) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.DependsOn); i++ {
+
+		if err := validate.Pattern("dependsOn"+"."+strconv.Itoa(i), "body", m.DependsOn[i], `^[a-zA-Z_$][a-zA-Z_.\-$0-9]*$`); err != nil {
+			return err
+		}
+
+	}
+
+	return nil
+}
+
 func (m *Component) validateExecutor(formats strfmt.Registry) error {
 	if swag.IsZero(m.Executor) { // not required
 		return nil
@@ -308,7 +334,7 @@ var componentTypeTypePropEnum []interface{}
 
 func init() {
 	var res []string
-	if err := json.Unmarshal([]byte(`["yaml","helm","repo","dce"]`), &res); err != nil {
+	if err := json.Unmarshal([]byte(`["yaml","helm","repo","dce","kustomize"]`), &res); err != nil {
 		panic(err)
 	}
 	for _, v := range res {
@@ -329,6 +355,9 @@ const (
 
 	// ComponentTypeDce captures enum value "dce"
 	ComponentTypeDce string = "dce"
+
+	// ComponentTypeKustomize captures enum value "kustomize"
+	ComponentTypeKustomize string = "kustomize"
 )
 
 // prop value enum
@@ -582,17 +611,84 @@ func (m *ComponentResourcesItems0) UnmarshalBinary(b []byte) error {
 // swagger:model ComponentWait
 type ComponentWait struct {
 
+	// conditions
+	Conditions []*ComponentWaitConditionsItems0 `json:"conditions"`
+
 	// timeout
 	Timeout int64 `json:"timeout,omitempty"`
 }
 
 // Validate validates this component wait
 func (m *ComponentWait) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateConditions(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
 	return nil
 }
 
-// ContextValidate validates this component wait based on context it is used
+func (m *ComponentWait) validateConditions(formats strfmt.Registry) error {
+	if swag.IsZero(m.Conditions) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Conditions); i++ {
+		if swag.IsZero(m.Conditions[i]) { // not required
+			continue
+		}
+
+		if m.Conditions[i] != nil {
+			if err := m.Conditions[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("wait" + "." + "conditions" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("wait" + "." + "conditions" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+// ContextValidate validate this component wait based on the context it is used
 func (m *ComponentWait) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.contextValidateConditions(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *ComponentWait) contextValidateConditions(ctx context.Context, formats strfmt.Registry) error {
+
+	for i := 0; i < len(m.Conditions); i++ {
+
+		if m.Conditions[i] != nil {
+			if err := m.Conditions[i].ContextValidate(ctx, formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("wait" + "." + "conditions" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("wait" + "." + "conditions" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
 	return nil
 }
 
@@ -613,3 +709,91 @@ func (m *ComponentWait) UnmarshalBinary(b []byte) error {
 	*m = res
 	return nil
 }
+
+// ComponentWaitConditionsItems0 component wait conditions items0
+//
+// swagger:model ComponentWaitConditionsItems0
+type ComponentWaitConditionsItems0 struct {
+
+	// api version
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// condition
+	Condition string `json:"condition,omitempty"`
+
+	// kind
+	Kind string `json:"kind,omitempty"`
+
+	// name
+	// Pattern: ^[a-zA-Z_$][a-zA-Z_.\-$0-9]*$
+	Name string `json:"name,omitempty"`
+
+	// namespace
+	// Pattern: ^[a-zA-Z_$][a-zA-Z_.\-$0-9]*$
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Validate validates this component wait conditions items0
+func (m *ComponentWaitConditionsItems0) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateName(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateNamespace(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *ComponentWaitConditionsItems0) validateName(formats strfmt.Registry) error {
+	if swag.IsZero(m.Name) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("name", "body", m.Name, `^[a-zA-Z_$][a-zA-Z_.\-$0-9]*$`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *ComponentWaitConditionsItems0) validateNamespace(formats strfmt.Registry) error {
+	if swag.IsZero(m.Namespace) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("namespace", "body", m.Namespace, `^[a-zA-Z_$][a-zA-Z_.\-$0-9]*$`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ContextValidate validates this component wait conditions items0 based on context it is used
+func (m *ComponentWaitConditionsItems0) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *ComponentWaitConditionsItems0) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *ComponentWaitConditionsItems0) UnmarshalBinary(b []byte) error {
+	var res ComponentWaitConditionsItems0
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
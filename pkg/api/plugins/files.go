@@ -140,6 +140,9 @@ type FilesItems0 struct {
 	// Enum: [md5 sha256]
 	Hashtype string `json:"hashtype,omitempty"`
 
+	// helmchart
+	Helmchart bool `json:"helmchart,omitempty"`
+
 	// mirrorurl
 	// Pattern: (?:(?:https?|http|ftp|file|oci)://|www.|ftp.)(?:([-A-Z0-9+&@#/%=~_|$?!:,.]*)|[-A-Z0-9+&@#/%=~_|$?!:,.])*(?:([-A-Z0-9+&@#/%=~_|$?!:,.]*)|[A-Z0-9+&@#/%=~_|$])
 	Mirrorurl string `json:"mirrorurl,omitempty"`
@@ -368,6 +368,9 @@ type KitconfigComponents struct {
 	// manifests
 	Manifests []string `json:"manifests"`
 
+	// parallel
+	Parallel int64 `json:"parallel,omitempty"`
+
 	// selector
 	Selector []*KitconfigComponentsSelectorItems0 `json:"selector"`
 }
@@ -703,6 +706,9 @@ func (m *KitconfigOS) UnmarshalBinary(b []byte) error {
 // swagger:model KitconfigParameters
 type KitconfigParameters struct {
 
+	// certificate
+	Certificate *Certificate `json:"certificate,omitempty"`
+
 	// customconfig
 	Customconfig *Customconfig `json:"customconfig,omitempty"`
 
@@ -723,6 +729,10 @@ type KitconfigParameters struct {
 func (m *KitconfigParameters) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateCertificate(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateCustomconfig(formats); err != nil {
 		res = append(res, err)
 	}
@@ -745,6 +755,25 @@ func (m *KitconfigParameters) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *KitconfigParameters) validateCertificate(formats strfmt.Registry) error {
+	if swag.IsZero(m.Certificate) { // not required
+		return nil
+	}
+
+	if m.Certificate != nil {
+		if err := m.Certificate.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("Parameters" + "." + "certificate")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("Parameters" + "." + "certificate")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *KitconfigParameters) validateCustomconfig(formats strfmt.Registry) error {
 	if swag.IsZero(m.Customconfig) { // not required
 		return nil
@@ -829,6 +858,10 @@ func (m *KitconfigParameters) validateNodes(formats strfmt.Registry) error {
 func (m *KitconfigParameters) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.contextValidateCertificate(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.contextValidateCustomconfig(ctx, formats); err != nil {
 		res = append(res, err)
 	}
@@ -847,6 +880,22 @@ func (m *KitconfigParameters) ContextValidate(ctx context.Context, formats strfm
 	return nil
 }
 
+func (m *KitconfigParameters) contextValidateCertificate(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.Certificate != nil {
+		if err := m.Certificate.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("Parameters" + "." + "certificate")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("Parameters" + "." + "certificate")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *KitconfigParameters) contextValidateCustomconfig(ctx context.Context, formats strfmt.Registry) error {
 
 	if m.Customconfig != nil {
@@ -948,6 +997,10 @@ type KitconfigParametersGlobalSettings struct {
 	// Pattern: ^((6553[0-5])|(655[0-2][0-9])|(65[0-4][0-9]{2})|(6[0-4][0-9]{3})|([1-5][0-9]{4})|([0-5]{0,5})|([0-9]{1,4}))$
 	RegistryPort string `json:"registry_port,omitempty"`
 
+	// ssh host key checking
+	// Enum: [tofu strict]
+	SSHHostKeyChecking string `json:"ssh_host_key_checking,omitempty"`
+
 	// workflow port
 	// Pattern: ^((6553[0-5])|(655[0-2][0-9])|(65[0-4][0-9]{2})|(6[0-4][0-9]{3})|([1-5][0-9]{4})|([0-5]{0,5})|([0-9]{1,4}))$
 	WorkflowPort string `json:"workflow_port,omitempty"`
@@ -977,6 +1030,10 @@ func (m *KitconfigParametersGlobalSettings) Validate(formats strfmt.Registry) er
 		res = append(res, err)
 	}
 
+	if err := m.validateSSHHostKeyChecking(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateWorkflowPort(formats); err != nil {
 		res = append(res, err)
 	}
@@ -1047,6 +1104,48 @@ func (m *KitconfigParametersGlobalSettings) validateRegistryPort(formats strfmt.
 	return nil
 }
 
+var kitconfigParametersGlobalSettingsTypeSSHHostKeyCheckingPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["tofu","strict"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		kitconfigParametersGlobalSettingsTypeSSHHostKeyCheckingPropEnum = append(kitconfigParametersGlobalSettingsTypeSSHHostKeyCheckingPropEnum, v)
+	}
+}
+
+const (
+
+	// KitconfigParametersGlobalSettingsSSHHostKeyCheckingTofu captures enum value "tofu"
+	KitconfigParametersGlobalSettingsSSHHostKeyCheckingTofu string = "tofu"
+
+	// KitconfigParametersGlobalSettingsSSHHostKeyCheckingStrict captures enum value "strict"
+	KitconfigParametersGlobalSettingsSSHHostKeyCheckingStrict string = "strict"
+)
+
+// prop value enum
+func (m *KitconfigParametersGlobalSettings) validateSSHHostKeyCheckingEnum(path, location string, value string) error {
+	if err := validate.EnumCase(path, location, value, kitconfigParametersGlobalSettingsTypeSSHHostKeyCheckingPropEnum, true); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *KitconfigParametersGlobalSettings) validateSSHHostKeyChecking(formats strfmt.Registry) error {
+	if swag.IsZero(m.SSHHostKeyChecking) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateSSHHostKeyCheckingEnum("Parameters"+"."+"global_settings"+"."+"ssh_host_key_checking", "body", m.SSHHostKeyChecking); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (m *KitconfigParametersGlobalSettings) validateWorkflowPort(formats strfmt.Registry) error {
 	if swag.IsZero(m.WorkflowPort) { // not required
 		return nil
@@ -62,9 +62,24 @@ type Node struct {
 	// Pattern: ^[a-zA-Z_$][a-zA-Z_.\-$0-9]*$
 	Name string `json:"name,omitempty"`
 
+	// proxy jump
+	ProxyJump []string `json:"proxy_jump"`
+
 	// role
 	Role []string `json:"role"`
 
+	// ssh agent socket
+	SSHAgentSocket string `json:"ssh_agent_socket,omitempty"`
+
+	// ssh cert
+	SSHCert string `json:"ssh_cert,omitempty"`
+
+	// ssh cert path
+	SSHCertPath string `json:"ssh_cert_path,omitempty"`
+
+	// ssh host key fingerprints
+	SSHHostKeyFingerprints []string `json:"ssh_host_key_fingerprints"`
+
 	// ssh key
 	SSHKey string `json:"ssh_key,omitempty"`
 
@@ -15,6 +15,7 @@ package workflow
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 
 	"github.com/go-openapi/errors"
@@ -654,6 +655,9 @@ type WorkflowSpecWorkflowsItems0 struct {
 	// Pattern: ^[a-zA-Z_$][a-zA-Z_.\-$0-9]*$
 	Name string `json:"name,omitempty"`
 
+	// parallel
+	Parallel int64 `json:"parallel,omitempty"`
+
 	// steps
 	Steps []*WorkflowSpecWorkflowsItems0StepsItems0 `json:"steps"`
 }
@@ -771,6 +775,13 @@ func (m *WorkflowSpecWorkflowsItems0) UnmarshalBinary(b []byte) error {
 // swagger:model WorkflowSpecWorkflowsItems0StepsItems0
 type WorkflowSpecWorkflowsItems0StepsItems0 struct {
 
+	// backoff
+	Backoff int64 `json:"backoff,omitempty"`
+
+	// call
+	// Pattern: ^[a-zA-Z_$][a-zA-Z_.\-$0-9]*$
+	Call string `json:"call,omitempty"`
+
 	// input
 	Input []*WorkflowSpecWorkflowsItems0StepsItems0InputItems0 `json:"input"`
 
@@ -780,12 +791,29 @@ type WorkflowSpecWorkflowsItems0StepsItems0 struct {
 
 	// output
 	Output []*WorkflowSpecWorkflowsItems0StepsItems0OutputItems0 `json:"output"`
+
+	// retries
+	Retries int64 `json:"retries,omitempty"`
+
+	// timeout
+	Timeout int64 `json:"timeout,omitempty"`
+
+	// when
+	When string `json:"when,omitempty"`
 }
 
 // Validate validates this workflow spec workflows items0 steps items0
 func (m *WorkflowSpecWorkflowsItems0StepsItems0) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateOneOf(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateCall(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateInput(formats); err != nil {
 		res = append(res, err)
 	}
@@ -804,6 +832,37 @@ func (m *WorkflowSpecWorkflowsItems0StepsItems0) Validate(formats strfmt.Registr
 	return nil
 }
 
+func (m *WorkflowSpecWorkflowsItems0StepsItems0) validateOneOf(formats strfmt.Registry) error {
+	// oneOf: required name, required call
+	valid := 0
+	if !swag.IsZero(m.Name) {
+		valid++
+	}
+	if !swag.IsZero(m.Call) {
+		valid++
+	}
+	if valid == 0 {
+		return errors.New(errors.CompositeErrorCode, validate.MustValidateOnlyOneSchemaError, "body", "Found none valid")
+	}
+	if valid > 1 {
+		return errors.New(errors.CompositeErrorCode, validate.MustValidateOnlyOneSchemaError, "body", fmt.Sprintf("Found %d valid alternatives", valid))
+	}
+
+	return nil
+}
+
+func (m *WorkflowSpecWorkflowsItems0StepsItems0) validateCall(formats strfmt.Registry) error {
+	if swag.IsZero(m.Call) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("call", "body", m.Call, `^[a-zA-Z_$][a-zA-Z_.\-$0-9]*$`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (m *WorkflowSpecWorkflowsItems0StepsItems0) validateInput(formats strfmt.Registry) error {
 	if swag.IsZero(m.Input) { // not required
 		return nil
@@ -101,6 +101,7 @@ func GenerateCertBundle(cb *cmapi.Certificate, ctype CertType, hosts string) err
 
 	// Create certificates
 	var derBytes []byte
+	var chain [][]byte
 	if ctype == CACERT {
 		template.IsCA = true
 		template.KeyUsage |= x509.KeyUsageCertSign
@@ -110,37 +111,32 @@ func GenerateCertBundle(cb *cmapi.Certificate, ctype CertType, hosts string) err
 			return err
 		}
 	} else {
-		caraw, cerr := ioutil.ReadFile(cbundle.Ca.Cert)
+		cacerts, cerr := readCertChain(cbundle.Ca.Cert)
 		if cerr != nil {
-			log.Errorf("Failed to get raw Cert: %v", cerr)
+			log.Errorf("Failed to get ca certificate: %v", cerr)
 			return cerr
 		}
-		capem, _ := pem.Decode(caraw)
-		if capem == nil {
-			log.Errorf("Failed to decode Cert")
-			return eputils.GetError("errCertDecodeFail")
-		}
-		cacert, err := x509.ParseCertificate(capem.Bytes)
-		if err != nil {
-			log.Errorf("Failed to parse ca certificate: %v", err)
-			return err
-		}
 		capriv, err := ioutil.ReadFile(cbundle.Ca.Key)
 		if err != nil {
 			log.Errorf("Failed to get ca key: %v", err)
 			return err
 		}
-		caprivPem, _ := pem.Decode(capriv)
-		ecdsaCApriv, err := x509.ParsePKCS8PrivateKey(caprivPem.Bytes)
+		caSigner, err := parsePrivateKey(capriv)
 		if err != nil {
 			log.Errorf("Failed to parse ca private key: %v", err)
 			return err
 		}
-		derBytes, err = x509.CreateCertificate(rand.Reader, template, cacert, &priv.PublicKey, ecdsaCApriv)
+		if _, ok := caSigner.(*ecdsa.PrivateKey); !ok {
+			// Let the signature algorithm follow the key of an imported CA.
+			template.SignatureAlgorithm = x509.UnknownSignatureAlgorithm
+		}
+		derBytes, err = x509.CreateCertificate(rand.Reader, template, cacerts[0], &priv.PublicKey, caSigner)
 		if err != nil {
 			log.Errorf("Failed to create certificate: %v", err)
 			return err
 		}
+		// Send the intermediate CAs along with the certificate.
+		chain = issuerChain(cacerts)
 	}
 
 	// Encode cert and key to file
@@ -156,6 +152,12 @@ func GenerateCertBundle(cb *cmapi.Certificate, ctype CertType, hosts string) err
 		certFile = cbundle.Client.Cert
 		keyFile = cbundle.Client.Key
 	}
+	return writeCertAndKey(certFile, keyFile, append([][]byte{derBytes}, chain...), priv)
+}
+
+// writeCertAndKey writes a certificate, followed by its issuer chain, and its
+// private key.
+func writeCertAndKey(certFile, keyFile string, certs [][]byte, priv *ecdsa.PrivateKey) error {
 	if eputils.FileExists(certFile) {
 		valid := eputils.IsValidFile(certFile)
 		if !valid {
@@ -168,9 +170,11 @@ func GenerateCertBundle(cb *cmapi.Certificate, ctype CertType, hosts string) err
 		log.Errorf("Failed to open %v for writing: %v", certFile, err)
 		return err
 	}
-	if err := pem.Encode(certOut, &pem.Block{Type: "CERTIFICATE", Bytes: derBytes}); err != nil {
-		log.Errorf("Failed to write data to %v: %v", certFile, err)
-		return err
+	for _, der := range certs {
+		if err := pem.Encode(certOut, &pem.Block{Type: "CERTIFICATE", Bytes: der}); err != nil {
+			log.Errorf("Failed to write data to %v: %v", certFile, err)
+			return err
+		}
 	}
 	if err := certOut.Close(); err != nil {
 		log.Errorf("Error closing %v: %v", certFile, err)
@@ -278,6 +282,12 @@ func GenCertAndConfig(certbundle cmapi.Certificate, hosts string) error {
 	}
 	// sign server cert and client cert if not provided
 	if certbundle.Server != nil {
+		if acmeChanged(&certbundle) {
+			log.Infof("ACME server of %s is changed, re-issue the server cert", certbundle.Name)
+			if err := removeCertFiles(certbundle.Server.Cert, certbundle.Server.Key); err != nil {
+				return err
+			}
+		}
 		_, bundlecacerterr := os.Stat(certbundle.Ca.Cert)
 		if bundlecacerterr != nil {
 			log.Errorln("Ca Cert Failed:", bundlecacerterr)
@@ -286,8 +296,8 @@ func GenCertAndConfig(certbundle cmapi.Certificate, hosts string) error {
 		_, cakeyerr = os.Stat(certbundle.Ca.Key)
 		_, servercerterr := os.Stat(certbundle.Server.Cert)
 		if os.IsNotExist(servercerterr) {
-			if cakeyerr == nil {
-				generr := GenerateCertBundle(&certbundle, SERVERCERT, hosts)
+			if cakeyerr == nil || isACMEBundle(&certbundle) {
+				generr := issueServerCert(&certbundle, hosts)
 				if generr != nil {
 					return generr
 				}
@@ -465,7 +475,13 @@ func prepareCertTemplate(cb *cmapi.Certificate, ctype CertType, hosts string) (*
 		template.SignatureAlgorithm = x509.ECDSAWithSHA512
 	} else if ctype == SERVERCERT {
 		certHosts := append(strings.Split(hosts, ","), usercsr.Hosts...)
+		seen := map[string]bool{}
 		for _, h := range certHosts {
+			// Skip the hosts of the csr kept in a rotated certificate.
+			if h == "" || seen[h] {
+				continue
+			}
+			seen[h] = true
 			if ip := net.ParseIP(h); ip != nil {
 				template.IPAddresses = append(template.IPAddresses, ip)
 			} else {
@@ -0,0 +1,546 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package certmgr
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	cmapi "github.com/intel/edge-conductor/pkg/api/certmgr"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/acme"
+)
+
+const (
+	ACMEACCOUNTKEYFILE   = "acme-account-key.pem"
+	ACMECHALLENGEADDRESS = ":80"
+)
+
+var (
+	// ACMETimeout bounds the issuance of a certificate from an ACME server.
+	ACMETimeout = 5 * time.Minute
+)
+
+// readCertChain reads all the certificates of a PEM file.
+func readCertChain(certFile string) ([]*x509.Certificate, error) {
+	raw, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		log.Errorf("Failed to read cert %s: %v", certFile, err)
+		return nil, err
+	}
+	certs := []*x509.Certificate{}
+	for {
+		var block *pem.Block
+		block, raw = pem.Decode(raw)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			log.Errorf("Failed to parse cert %s: %v", certFile, err)
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		log.Errorf("Failed to decode cert %s", certFile)
+		return nil, eputils.GetError("errCertDecodeFail")
+	}
+	return certs, nil
+}
+
+// parsePrivateKey parses a PEM encoded PKCS8, EC or PKCS1 private key, the
+// formats an imported CA key may come in.
+func parsePrivateKey(raw []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		log.Errorf("Failed to decode private key")
+		return nil, eputils.GetError("errCertDecodeFail")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		if eckey, ecerr := x509.ParseECPrivateKey(block.Bytes); ecerr == nil {
+			return eckey, nil
+		}
+		if rsakey, rsaerr := x509.ParsePKCS1PrivateKey(block.Bytes); rsaerr == nil {
+			return rsakey, nil
+		}
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		log.Errorf("Unsupported private key type %T", key)
+		return nil, eputils.GetError("errKeyAlgo")
+	}
+	return signer, nil
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil
+}
+
+// issuerChain returns the chain of intermediate CAs from the first cert of
+// cacerts up to, and not including, the root CA.
+func issuerChain(cacerts []*x509.Certificate) [][]byte {
+	chain := [][]byte{}
+	for cert := cacerts[0]; cert != nil && !isSelfSigned(cert) && len(chain) < len(cacerts); {
+		chain = append(chain, cert.Raw)
+		var parent *x509.Certificate
+		for _, c := range cacerts {
+			if c != cert && cert.CheckSignatureFrom(c) == nil {
+				parent = c
+				break
+			}
+		}
+		cert = parent
+	}
+	return chain
+}
+
+func removeCertFiles(files ...string) error {
+	for _, f := range files {
+		if f == "" {
+			continue
+		}
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			log.Errorf("Failed to remove %s: %v", f, err)
+			return err
+		}
+	}
+	return nil
+}
+
+func removeLeafCerts(cb *cmapi.Certificate) error {
+	if cb.Server != nil {
+		if err := removeCertFiles(cb.Server.Cert, cb.Server.Key); err != nil {
+			return err
+		}
+	}
+	if cb.Client != nil {
+		if err := removeCertFiles(cb.Client.Cert, cb.Client.Key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportCA installs an existing CA, or an intermediate CA followed by its
+// chain up to the root CA, as the CA of the cert bundles, instead of the
+// self-signed root CA. If the CA of the bundles changes, their server and
+// client certificates are removed, to be re-issued by GenCertAndConfig.
+func ImportCA(caCert, caKey string, bundles ...*cmapi.Certificate) error {
+	if caCert == "" || caKey == "" || len(bundles) == 0 {
+		log.Errorf("Cert path or Key path is nil")
+		return eputils.GetError("errCertNil")
+	}
+	certs, err := readCertChain(caCert)
+	if err != nil {
+		return err
+	}
+	if !certs[0].IsCA {
+		log.Errorf("%s is not a CA certificate", caCert)
+		return eputils.GetError("errCaCertInvalid")
+	}
+	keyraw, err := ioutil.ReadFile(caKey)
+	if err != nil {
+		log.Errorf("Failed to get ca key: %v", err)
+		return err
+	}
+	signer, err := parsePrivateKey(keyraw)
+	if err != nil {
+		log.Errorf("Failed to parse ca private key: %v", err)
+		return err
+	}
+	if pub, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !pub.Equal(certs[0].PublicKey) {
+		log.Errorf("%s does not match %s", caKey, caCert)
+		return eputils.GetError("errCaKeyMismatch")
+	}
+	certraw, err := ioutil.ReadFile(caCert)
+	if err != nil {
+		return err
+	}
+
+	current := bundles[0].Ca
+	if current == nil || current.Cert == "" || current.Key == "" {
+		log.Errorf("Cert path or Key path is nil")
+		return eputils.GetError("errCertNil")
+	}
+	if cert, err := readCertFile(current.Cert); err == nil && cert.Equal(certs[0]) {
+		if raw, err := ioutil.ReadFile(current.Key); err == nil && bytes.Equal(raw, keyraw) {
+			log.Debugf("CA %s is already imported", caCert)
+			return nil
+		}
+	}
+
+	log.Infof("Importing CA %s, issued by %s", certs[0].Subject, certs[0].Issuer)
+	for _, cb := range bundles {
+		if cb.Ca == nil || cb.Ca.Cert == "" || cb.Ca.Key == "" {
+			log.Errorf("Cert path or Key path is nil")
+			return eputils.GetError("errCertNil")
+		}
+		if err := validateCertbundle(*cb); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(cb.Ca.Cert), os.ModePerm); err != nil {
+			log.Errorf("Failed to create dir: %v", err)
+			return err
+		}
+		if err := ioutil.WriteFile(cb.Ca.Cert, certraw, 0644); err != nil {
+			log.Errorf("Failed to write %s: %v", cb.Ca.Cert, err)
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(cb.Ca.Key), os.ModePerm); err != nil {
+			log.Errorf("Failed to create dir: %v", err)
+			return err
+		}
+		if err := ioutil.WriteFile(cb.Ca.Key, keyraw, 0600); err != nil {
+			log.Errorf("Failed to write %s: %v", cb.Ca.Key, err)
+			return err
+		}
+		if err := removeLeafCerts(cb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isACMEBundle(cb *cmapi.Certificate) bool {
+	return cb.Acme != nil && cb.Acme.Directory != ""
+}
+
+// acmeChanged tells if the ACME server of a cert bundle is not the one in its
+// runtime config, which issued the current server certificate.
+func acmeChanged(cb *cmapi.Certificate) bool {
+	certCfgFile, _ := getCertCfgFileFromName(cb.Name, "")
+	if _, err := os.Stat(certCfgFile); err != nil {
+		return false
+	}
+	current, err := getCertBundleFromConfigFile(certCfgFile)
+	if err != nil {
+		return false
+	}
+	currentDir, dir := "", ""
+	if current.Acme != nil {
+		currentDir = current.Acme.Directory
+	}
+	if cb.Acme != nil {
+		dir = cb.Acme.Directory
+	}
+	return currentDir != dir
+}
+
+// issueServerCert issues the server certificate of a cert bundle from its
+// ACME server if it has one, or else from its CA.
+func issueServerCert(cb *cmapi.Certificate, hosts string) error {
+	if isACMEBundle(cb) {
+		return IssueACMECert(cb, hosts)
+	}
+	return GenerateCertBundle(cb, SERVERCERT, hosts)
+}
+
+func loadACMEAccountKey(cb *cmapi.Certificate) (crypto.Signer, error) {
+	keyFile := filepath.Join(RUNTIMEPKIDIR, cb.Name, ACMEACCOUNTKEYFILE)
+	raw, err := ioutil.ReadFile(keyFile)
+	if err == nil {
+		return parsePrivateKey(raw)
+	} else if !os.IsNotExist(err) {
+		log.Errorf("Failed to read ACME account key: %v", err)
+		return nil, err
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		log.Errorf("Failed to generate ACME account key: %v", err)
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		log.Errorf("Unable to marshal private key: %v", err)
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(keyFile), os.ModePerm); err != nil {
+		log.Errorf("Failed to create dir: %v", err)
+		return nil, err
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		log.Errorf("Failed to write %s: %v", keyFile, err)
+		return nil, err
+	}
+	return key, nil
+}
+
+func newACMEClient(cb *cmapi.Certificate) (*acme.Client, error) {
+	key, err := loadACMEAccountKey(cb)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cb.Acme.RootCa != "" {
+		rootPEM, err := ioutil.ReadFile(cb.Acme.RootCa)
+		if err != nil {
+			return nil, err
+		}
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		if ok := roots.AppendCertsFromPEM(rootPEM); !ok {
+			log.Errorf("failed to parse root certificate: %q", cb.Acme.RootCa)
+			return nil, eputils.GetError("errRootCert")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+	}
+	return &acme.Client{
+		Key:          key,
+		DirectoryURL: cb.Acme.Directory,
+		HTTPClient:   &http.Client{Transport: transport},
+	}, nil
+}
+
+// acmeChallenges serves the key authorizations of the http-01 challenges.
+type acmeChallenges struct {
+	sync.Mutex
+	responses map[string]string
+}
+
+func (c *acmeChallenges) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.URL.Path, "/.well-known/acme-challenge/")
+	c.Lock()
+	resp, ok := c.responses[token]
+	c.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if _, err := w.Write([]byte(resp)); err != nil {
+		log.Warnf("Failed to answer the ACME challenge: %v", err)
+	}
+}
+
+func authorizeACMEOrder(ctx context.Context, client *acme.Client, addr string, authzURLs []string) error {
+	challenges := &acmeChallenges{responses: map[string]string{}}
+	var srv *http.Server
+	defer func() {
+		if srv != nil {
+			srv.Close()
+		}
+	}()
+
+	for _, u := range authzURLs {
+		z, err := client.GetAuthorization(ctx, u)
+		if err != nil {
+			log.Errorf("Failed to get ACME authorization: %v", err)
+			return err
+		}
+		if z.Status == acme.StatusValid {
+			continue
+		}
+		var chal *acme.Challenge
+		for _, c := range z.Challenges {
+			if c.Type == "http-01" {
+				chal = c
+				break
+			}
+		}
+		if chal == nil {
+			log.Errorf("No http-01 challenge to authorize %s", z.Identifier.Value)
+			return eputils.GetError("errAcmeChallenge")
+		}
+		resp, err := client.HTTP01ChallengeResponse(chal.Token)
+		if err != nil {
+			return err
+		}
+		challenges.Lock()
+		challenges.responses[chal.Token] = resp
+		challenges.Unlock()
+
+		if srv == nil {
+			if addr == "" {
+				addr = ACMECHALLENGEADDRESS
+			}
+			ln, err := net.Listen("tcp", addr)
+			if err != nil {
+				log.Errorf("Failed to listen on %s for the ACME challenges: %v", addr, err)
+				return err
+			}
+			srv = &http.Server{Handler: challenges, ReadHeaderTimeout: 10 * time.Second}
+			go func() {
+				if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+					log.Warnf("ACME challenge server: %v", err)
+				}
+			}()
+		}
+
+		log.Infof("Authorizing %s with the ACME server", z.Identifier.Value)
+		if _, err := client.Accept(ctx, chal); err != nil {
+			log.Errorf("Failed to accept the ACME challenge: %v", err)
+			return err
+		}
+		if _, err := client.WaitAuthorization(ctx, z.URI); err != nil {
+			log.Errorf("Failed to authorize %s: %v", z.Identifier.Value, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// addTrustedCerts appends the certs which are not there yet to a CA cert
+// file.
+func addTrustedCerts(caFile string, certs [][]byte) error {
+	cacerts, err := readCertChain(caFile)
+	if err != nil {
+		return err
+	}
+	raw, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return err
+	}
+	added := false
+	for _, der := range certs {
+		found := false
+		for _, c := range cacerts {
+			if bytes.Equal(c.Raw, der) {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return err
+		}
+		if len(raw) > 0 && raw[len(raw)-1] != '\n' {
+			raw = append(raw, '\n')
+		}
+		raw = append(raw, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+		cacerts = append(cacerts, cert)
+		added = true
+	}
+	if !added {
+		return nil
+	}
+	return ioutil.WriteFile(caFile, raw, 0644)
+}
+
+// IssueACMECert issues the server certificate of a cert bundle from the ACME
+// server of the bundle, answering the http-01 challenges of its hosts.
+// Loopback hosts can not be validated by the ACME server and are left out.
+// The issuer chain, and the root CA of the ACME server, are added to the CA
+// cert of the bundle, so that the clients trusting the CA trust the
+// certificate too.
+func IssueACMECert(cb *cmapi.Certificate, hosts string) error {
+	if cb.Ca == nil || cb.Ca.Cert == "" || cb.Server == nil || cb.Server.Cert == "" || cb.Server.Key == "" {
+		log.Errorf("Cert path or Key path is nil")
+		return eputils.GetError("errCertNil")
+	}
+	priv, err := generateECDSAPrivKey(cb, SERVERCERT)
+	if err != nil {
+		log.Errorf("Failed to generate ECDSA private key: %v", err)
+		return err
+	}
+	template, err := prepareCertTemplate(cb, SERVERCERT, hosts)
+	if err != nil {
+		log.Errorf("Failed to prepare certificate template: %v", err)
+		return err
+	}
+
+	csrTemplate := &x509.CertificateRequest{Subject: template.Subject}
+	ids := []acme.AuthzID{}
+	for _, name := range template.DNSNames {
+		if name == "localhost" || strings.HasSuffix(name, ".localhost") {
+			log.Debugf("Skip loopback host %s for ACME", name)
+			continue
+		}
+		csrTemplate.DNSNames = append(csrTemplate.DNSNames, name)
+		ids = append(ids, acme.AuthzID{Type: "dns", Value: name})
+	}
+	for _, ip := range template.IPAddresses {
+		if ip.IsLoopback() {
+			log.Debugf("Skip loopback host %s for ACME", ip)
+			continue
+		}
+		csrTemplate.IPAddresses = append(csrTemplate.IPAddresses, ip)
+		ids = append(ids, acme.AuthzID{Type: "ip", Value: ip.String()})
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, csrTemplate, priv)
+	if err != nil {
+		log.Errorf("Failed to create certificate request: %v", err)
+		return err
+	}
+
+	client, err := newACMEClient(cb)
+	if err != nil {
+		log.Errorf("Failed to create ACME client: %v", err)
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ACMETimeout)
+	defer cancel()
+
+	acct := &acme.Account{}
+	if cb.Acme.Email != "" {
+		acct.Contact = []string{"mailto:" + cb.Acme.Email}
+	}
+	if _, err := client.Register(ctx, acct, acme.AcceptTOS); err != nil && err != acme.ErrAccountAlreadyExists {
+		log.Errorf("Failed to register to the ACME server %s: %v", cb.Acme.Directory, err)
+		return err
+	}
+	order, err := client.AuthorizeOrder(ctx, ids)
+	if err != nil {
+		log.Errorf("Failed to order the certificate of %s: %v", cb.Name, err)
+		return err
+	}
+	if err := authorizeACMEOrder(ctx, client, cb.Acme.ChallengeAddress, order.AuthzURLs); err != nil {
+		return err
+	}
+	if order, err = client.WaitOrder(ctx, order.URI); err != nil {
+		log.Errorf("Failed to order the certificate of %s: %v", cb.Name, err)
+		return err
+	}
+	certs, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		log.Errorf("Failed to get the certificate of %s: %v", cb.Name, err)
+		return err
+	}
+	log.Infof("Issued the server cert of %s from %s", cb.Name, cb.Acme.Directory)
+	if err := writeCertAndKey(cb.Server.Cert, cb.Server.Key, certs, priv); err != nil {
+		return err
+	}
+
+	trusted := append([][]byte{}, certs[1:]...)
+	if cb.Acme.RootCa != "" {
+		roots, err := readCertChain(cb.Acme.RootCa)
+		if err != nil {
+			return err
+		}
+		for _, r := range roots {
+			trusted = append(trusted, r.Raw)
+		}
+	}
+	return addTrustedCerts(cb.Ca.Cert, trusted)
+}
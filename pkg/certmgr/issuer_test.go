@@ -0,0 +1,430 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package certmgr_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	cmapi "github.com/intel/edge-conductor/pkg/api/certmgr"
+	certmgr "github.com/intel/edge-conductor/pkg/certmgr"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prashantv/gostub"
+)
+
+func newTestCA(cn string, parent *x509.Certificate, parentKey crypto.Signer, key crypto.Signer) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	Expect(err).To(BeNil())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).To(BeNil())
+	return cert
+}
+
+func writePEM(file string, blocks ...*pem.Block) {
+	raw := []byte{}
+	for _, b := range blocks {
+		raw = append(raw, pem.EncodeToMemory(b)...)
+	}
+	Expect(ioutil.WriteFile(file, raw, 0600)).To(BeNil())
+}
+
+func certBlock(cert *x509.Certificate) *pem.Block {
+	return &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}
+}
+
+func verifyServerCert(certFile, caFile string) *x509.Certificate {
+	raw, err := ioutil.ReadFile(certFile)
+	Expect(err).To(BeNil())
+	certs := []*x509.Certificate{}
+	for block, rest := pem.Decode(raw); block != nil; block, rest = pem.Decode(rest) {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).To(BeNil())
+		certs = append(certs, cert)
+	}
+	Expect(certs).NotTo(BeEmpty())
+	caPEM, err := ioutil.ReadFile(caFile)
+	Expect(err).To(BeNil())
+	roots := x509.NewCertPool()
+	Expect(roots.AppendCertsFromPEM(caPEM)).To(BeTrue())
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err = certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	Expect(err).To(BeNil())
+	return certs[0]
+}
+
+func newTestBundles(tmpDir string) (*cmapi.Certificate, *cmapi.Certificate) {
+	ca := &cmapi.CertificateCa{
+		Cert: filepath.Join(tmpDir, "pki", "ca.pem"),
+		Csr:  TESTCACSR,
+		Key:  filepath.Join(tmpDir, "pki", "ca-key.pem"),
+	}
+	wf := &cmapi.Certificate{
+		Name: "workflow",
+		Ca:   ca,
+		Server: &cmapi.CertificateServer{
+			Cert: filepath.Join(tmpDir, "server.crt"),
+			Csr:  TESTWFSERVERCSR,
+			Key:  filepath.Join(tmpDir, "server.key"),
+		},
+		Client: &cmapi.CertificateClient{
+			Cert: filepath.Join(tmpDir, "client.crt"),
+			Csr:  TESTWFCLIENTCSR,
+			Key:  filepath.Join(tmpDir, "client.key"),
+		},
+	}
+	reg := &cmapi.Certificate{
+		Name: "registry",
+		Ca:   ca,
+		Server: &cmapi.CertificateServer{
+			Cert: filepath.Join(tmpDir, "registry.crt"),
+			Csr:  TESTWFSERVERCSR,
+			Key:  filepath.Join(tmpDir, "registry.key"),
+		},
+	}
+	return wf, reg
+}
+
+var _ = Describe("Import an existing CA", func() {
+	var (
+		tmpDir    string
+		stub      *gostub.Stubs
+		wf, reg   *cmapi.Certificate
+		root      *x509.Certificate
+		inter     *x509.Certificate
+		interKey  *rsa.PrivateKey
+		chainFile string
+		keyFile   string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "certmgr")
+		Expect(err).To(BeNil())
+		stub = gostub.Stub(&certmgr.RUNTIMEPKIDIR, filepath.Join(tmpDir, "pki")).Stub(&certmgr.RUNTIMECFGDIR, filepath.Join(tmpDir, "config"))
+		wf, reg = newTestBundles(tmpDir)
+
+		rootKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		Expect(err).To(BeNil())
+		root = newTestCA("Enterprise Root CA", nil, nil, rootKey)
+		interKey, err = rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).To(BeNil())
+		inter = newTestCA("Enterprise Intermediate CA", root, rootKey, interKey)
+
+		chainFile = filepath.Join(tmpDir, "chain.pem")
+		writePEM(chainFile, certBlock(inter), certBlock(root))
+		keyFile = filepath.Join(tmpDir, "inter-key.pem")
+		writePEM(keyFile, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(interKey)})
+	})
+
+	AfterEach(func() {
+		stub.Reset()
+		os.RemoveAll(tmpDir)
+	})
+
+	It("Issues the leaf certs from an intermediate CA", func() {
+		Expect(certmgr.ImportCA(chainFile, keyFile, wf, reg)).To(BeNil())
+		Expect(certmgr.GenCertAndConfig(*wf, "10.10.10.10")).To(BeNil())
+		Expect(certmgr.GenCertAndConfig(*reg, "10.10.10.10")).To(BeNil())
+
+		server := verifyServerCert(wf.Server.Cert, wf.Ca.Cert)
+		Expect(server.Issuer.CommonName).To(Equal("Enterprise Intermediate CA"))
+		Expect(server.SignatureAlgorithm).To(Equal(x509.SHA256WithRSA))
+		verifyServerCert(reg.Server.Cert, reg.Ca.Cert)
+
+		_, err := certmgr.GetTLSConfigByName("workflow", "server", "10.10.10.10")
+		Expect(err).To(BeNil())
+		_, err = certmgr.GetTLSConfigByName("workflow", "client", "")
+		Expect(err).To(BeNil())
+	})
+
+	It("Re-issues the leaf certs only if the CA changes", func() {
+		Expect(certmgr.GenCertAndConfig(*wf, "10.10.10.10")).To(BeNil())
+		selfSigned := readTestCert(wf.Server.Cert)
+
+		Expect(certmgr.ImportCA(chainFile, keyFile, wf, reg)).To(BeNil())
+		_, err := os.Stat(wf.Server.Cert)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+		Expect(certmgr.GenCertAndConfig(*wf, "10.10.10.10")).To(BeNil())
+		imported := readTestCert(wf.Server.Cert)
+		Expect(imported.SerialNumber).NotTo(Equal(selfSigned.SerialNumber))
+
+		Expect(certmgr.ImportCA(chainFile, keyFile, wf, reg)).To(BeNil())
+		Expect(readTestCert(wf.Server.Cert).SerialNumber).To(Equal(imported.SerialNumber))
+	})
+
+	It("Fails to import a CA with a wrong key or a leaf cert", func() {
+		otherKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		Expect(err).To(BeNil())
+		der, err := x509.MarshalECPrivateKey(otherKey)
+		Expect(err).To(BeNil())
+		writePEM(keyFile, &pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+		Expect(certmgr.ImportCA(chainFile, keyFile, wf)).To(Equal(eputils.GetError("errCaKeyMismatch")))
+
+		Expect(certmgr.GenCertAndConfig(*wf, "10.10.10.10")).To(BeNil())
+		Expect(certmgr.ImportCA(wf.Server.Cert, wf.Server.Key, wf)).To(Equal(eputils.GetError("errCaCertInvalid")))
+
+		Expect(certmgr.ImportCA("", keyFile, wf)).To(Equal(eputils.GetError("errCertNil")))
+	})
+})
+
+// acmeStandIn is a minimal ACME server, which issues the certificates from
+// its own CA once the http-01 challenge is answered.
+type acmeStandIn struct {
+	sync.Mutex
+	srv         *httptest.Server
+	ca          *x509.Certificate
+	caKey       crypto.Signer
+	challenge   string
+	addr        string
+	nonce       int
+	identifiers []map[string]string
+	authzValid  bool
+	cert        []byte
+}
+
+func (a *acmeStandIn) payload(r *http.Request, v interface{}) {
+	jws := struct {
+		Payload string `json:"payload"`
+	}{}
+	Expect(json.NewDecoder(r.Body).Decode(&jws)).To(BeNil())
+	if jws.Payload == "" || v == nil {
+		return
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(jws.Payload)
+	Expect(err).To(BeNil())
+	Expect(json.Unmarshal(raw, v)).To(BeNil())
+}
+
+func (a *acmeStandIn) reply(w http.ResponseWriter, status int, location string, v interface{}) {
+	if location != "" {
+		w.Header().Set("Location", a.srv.URL+location)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	Expect(json.NewEncoder(w).Encode(v)).To(BeNil())
+}
+
+func (a *acmeStandIn) order() map[string]interface{} {
+	status := "pending"
+	if a.cert != nil {
+		status = "valid"
+	} else if a.authzValid {
+		status = "ready"
+	}
+	return map[string]interface{}{
+		"status":         status,
+		"identifiers":    a.identifiers,
+		"authorizations": []string{a.srv.URL + "/authz/1"},
+		"finalize":       a.srv.URL + "/finalize/1",
+		"certificate":    a.srv.URL + "/cert/1",
+	}
+}
+
+func (a *acmeStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer GinkgoRecover()
+	a.Lock()
+	defer a.Unlock()
+	a.nonce++
+	w.Header().Set("Replay-Nonce", fmt.Sprintf("nonce%d", a.nonce))
+	challenge := map[string]string{"type": a.challenge, "url": a.srv.URL + "/chal/1", "token": "token1", "status": "pending"}
+
+	switch r.URL.Path {
+	case "/directory":
+		a.reply(w, http.StatusOK, "", map[string]string{
+			"newNonce":   a.srv.URL + "/nonce",
+			"newAccount": a.srv.URL + "/account",
+			"newOrder":   a.srv.URL + "/order",
+		})
+	case "/nonce":
+		w.WriteHeader(http.StatusOK)
+	case "/account":
+		a.payload(r, nil)
+		a.reply(w, http.StatusCreated, "/account/1", map[string]string{"status": "valid"})
+	case "/order":
+		req := struct {
+			Identifiers []map[string]string `json:"identifiers"`
+		}{}
+		a.payload(r, &req)
+		a.identifiers = req.Identifiers
+		a.reply(w, http.StatusCreated, "/order/1", a.order())
+	case "/order/1":
+		a.payload(r, nil)
+		a.reply(w, http.StatusOK, "/order/1", a.order())
+	case "/authz/1":
+		a.payload(r, nil)
+		status := "pending"
+		if a.authzValid {
+			status = "valid"
+		}
+		a.reply(w, http.StatusOK, "", map[string]interface{}{
+			"status":     status,
+			"identifier": a.identifiers[0],
+			"challenges": []map[string]string{challenge},
+		})
+	case "/chal/1":
+		a.payload(r, nil)
+		resp, err := http.Get("http://" + a.addr + "/.well-known/acme-challenge/token1")
+		Expect(err).To(BeNil())
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		Expect(err).To(BeNil())
+		Expect(strings.HasPrefix(string(body), "token1.")).To(BeTrue())
+		a.authzValid = true
+		challenge["status"] = "valid"
+		a.reply(w, http.StatusOK, "", challenge)
+	case "/finalize/1":
+		req := struct {
+			CSR string `json:"csr"`
+		}{}
+		a.payload(r, &req)
+		der, err := base64.RawURLEncoding.DecodeString(req.CSR)
+		Expect(err).To(BeNil())
+		csr, err := x509.ParseCertificateRequest(der)
+		Expect(err).To(BeNil())
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(time.Now().UnixNano()),
+			Subject:      csr.Subject,
+			DNSNames:     csr.DNSNames,
+			IPAddresses:  csr.IPAddresses,
+			NotBefore:    time.Now(),
+			NotAfter:     time.Now().AddDate(0, 3, 0),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}
+		a.cert, err = x509.CreateCertificate(rand.Reader, template, a.ca, csr.PublicKey, a.caKey)
+		Expect(err).To(BeNil())
+		a.reply(w, http.StatusOK, "/order/1", a.order())
+	case "/cert/1":
+		a.payload(r, nil)
+		w.Header().Set("Content-Type", "application/pem-certificate-chain")
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write(append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: a.cert}), pem.EncodeToMemory(certBlock(a.ca))...))
+		Expect(err).To(BeNil())
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func freeAddr() string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).To(BeNil())
+	defer ln.Close()
+	return ln.Addr().String()
+}
+
+var _ = Describe("Issue the server cert from an ACME server", func() {
+	var (
+		tmpDir string
+		stub   *gostub.Stubs
+		reg    *cmapi.Certificate
+		acmesv *acmeStandIn
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "certmgr")
+		Expect(err).To(BeNil())
+		stub = gostub.Stub(&certmgr.RUNTIMEPKIDIR, filepath.Join(tmpDir, "pki")).Stub(&certmgr.RUNTIMECFGDIR, filepath.Join(tmpDir, "config"))
+		stub.Stub(&certmgr.ACMETimeout, 30*time.Second)
+
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).To(BeNil())
+		acmesv = &acmeStandIn{ca: newTestCA("ACME CA", nil, nil, key), caKey: key, challenge: "http-01", addr: freeAddr()}
+		acmesv.srv = httptest.NewTLSServer(acmesv)
+
+		rootCA := filepath.Join(tmpDir, "acme-root.pem")
+		writePEM(rootCA, certBlock(acmesv.srv.Certificate()))
+		_, reg = newTestBundles(tmpDir)
+		reg.Acme = &cmapi.CertificateAcme{
+			Directory:        acmesv.srv.URL + "/directory",
+			Email:            "admin@example.com",
+			ChallengeAddress: acmesv.addr,
+			RootCa:           rootCA,
+		}
+	})
+
+	AfterEach(func() {
+		acmesv.srv.Close()
+		stub.Reset()
+		os.RemoveAll(tmpDir)
+	})
+
+	It("Issues and rotates the server cert", func() {
+		acme := reg.Acme
+		reg.Acme = nil
+		Expect(certmgr.GenCertAndConfig(*reg, "10.10.10.10")).To(BeNil())
+		Expect(readTestCert(reg.Server.Cert).Issuer.CommonName).NotTo(Equal("ACME CA"))
+
+		reg.Acme = acme
+		Expect(certmgr.GenCertAndConfig(*reg, "10.10.10.10")).To(BeNil())
+		Expect(acmesv.identifiers).To(Equal([]map[string]string{{"type": "ip", "value": "10.10.10.10"}}))
+
+		server := verifyServerCert(reg.Server.Cert, reg.Ca.Cert)
+		Expect(server.Issuer.CommonName).To(Equal("ACME CA"))
+		Expect(server.IPAddresses[0].String()).To(Equal("10.10.10.10"))
+		cacerts := 0
+		raw, err := ioutil.ReadFile(reg.Ca.Cert)
+		Expect(err).To(BeNil())
+		for block, rest := pem.Decode(raw); block != nil; block, rest = pem.Decode(rest) {
+			cacerts++
+		}
+		Expect(cacerts).To(Equal(3))
+		_, err = certmgr.GetTLSConfigByName("registry", "server", "10.10.10.10")
+		Expect(err).To(BeNil())
+
+		acmesv.cert = nil
+		Expect(certmgr.RotateCertBundle(reg)).To(BeNil())
+		Expect(readTestCert(reg.Server.Cert).SerialNumber).NotTo(Equal(server.SerialNumber))
+		Expect(certmgr.GetCertStatus(reg, time.Now())).To(HaveLen(2))
+	})
+
+	It("Fails without an http-01 challenge", func() {
+		acmesv.challenge = "dns-01"
+		Expect(certmgr.GenCertAndConfig(*reg, "10.10.10.10")).To(Equal(eputils.GetError("errAcmeChallenge")))
+	})
+})
@@ -0,0 +1,171 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package certmgr
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	cmapi "github.com/intel/edge-conductor/pkg/api/certmgr"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	CertStatusValid    = "Valid"
+	CertStatusExpiring = "Expiring"
+	CertStatusExpired  = "Expired"
+
+	// CertExpiryWarning is how long before the expiry a certificate is
+	// reported as expiring.
+	CertExpiryWarning = 30 * 24 * time.Hour
+)
+
+// CertStatus is the status of a certificate of a cert bundle.
+type CertStatus struct {
+	Bundle    string    `json:"bundle"`
+	Type      string    `json:"type"`
+	File      string    `json:"file"`
+	Subject   string    `json:"subject"`
+	SANs      []string  `json:"sans,omitempty"`
+	Issuer    string    `json:"issuer"`
+	NotBefore time.Time `json:"notBefore"`
+	NotAfter  time.Time `json:"notAfter"`
+	Status    string    `json:"status"`
+}
+
+// ListCertBundles returns the cert bundles of the cert config files in the
+// runtime config folder, sorted by name.
+func ListCertBundles() ([]*cmapi.Certificate, error) {
+	files, err := filepath.Glob(filepath.Join(RUNTIMECFGDIR, "*-cert.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	bundles := []*cmapi.Certificate{}
+	for _, f := range files {
+		cb, err := getCertBundleFromConfigFile(f)
+		if err != nil {
+			return nil, err
+		}
+		bundles = append(bundles, cb)
+	}
+	sort.Slice(bundles, func(i, j int) bool { return bundles[i].Name < bundles[j].Name })
+	return bundles, nil
+}
+
+func readCertFile(certFile string) (*x509.Certificate, error) {
+	raw, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		log.Errorf("Failed to read cert %s: %v", certFile, err)
+		return nil, err
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		log.Errorf("Failed to decode cert %s", certFile)
+		return nil, eputils.GetError("errCertDecodeFail")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// certSANs returns the DNS names and the IP addresses of a certificate.
+func certSANs(cert *x509.Certificate) []string {
+	sans := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return sans
+}
+
+// GetCertStatus reads the certificates of a cert bundle. The certificates
+// are reported as expiring CertExpiryWarning before they expire.
+func GetCertStatus(cb *cmapi.Certificate, now time.Time) ([]*CertStatus, error) {
+	type certFile struct{ ctype, file string }
+	certs := []certFile{}
+	if cb.Ca != nil {
+		certs = append(certs, certFile{"ca", cb.Ca.Cert})
+	}
+	if cb.Server != nil {
+		certs = append(certs, certFile{"server", cb.Server.Cert})
+	}
+	if cb.Client != nil {
+		certs = append(certs, certFile{"client", cb.Client.Cert})
+	}
+	statuses := []*CertStatus{}
+	for _, c := range certs {
+		if c.file == "" {
+			continue
+		}
+		cert, err := readCertFile(c.file)
+		if err != nil {
+			return nil, err
+		}
+		status := &CertStatus{
+			Bundle:    cb.Name,
+			Type:      c.ctype,
+			File:      c.file,
+			Subject:   cert.Subject.String(),
+			SANs:      certSANs(cert),
+			Issuer:    cert.Issuer.String(),
+			NotBefore: cert.NotBefore,
+			NotAfter:  cert.NotAfter,
+			Status:    CertStatusValid,
+		}
+		if now.After(cert.NotAfter) {
+			status.Status = CertStatusExpired
+		} else if now.Add(CertExpiryWarning).After(cert.NotAfter) {
+			status.Status = CertStatusExpiring
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// RotateCertBundle re-issues the server and client certificates of a cert
+// bundle from its existing CA, or the server certificate from the ACME server
+// of the bundle. The hosts of the server certificate are kept. The CA itself
+// is not re-issued.
+func RotateCertBundle(cb *cmapi.Certificate) error {
+	if cb.Ca == nil || cb.Ca.Cert == "" || cb.Ca.Key == "" {
+		log.Errorf("Cert path or Key path of the CA of %s is nil", cb.Name)
+		return eputils.GetError("errCertNil")
+	}
+	if err := validateCertbundle(*cb); err != nil {
+		return err
+	}
+	for _, f := range []string{cb.Ca.Cert, cb.Ca.Key} {
+		if _, err := os.Stat(f); err != nil {
+			log.Errorf("Failed to get the CA of %s: %v", cb.Name, err)
+			return err
+		}
+	}
+	if cb.Server != nil && cb.Server.Cert != "" {
+		hosts := []string{}
+		if cert, err := readCertFile(cb.Server.Cert); err == nil {
+			hosts = certSANs(cert)
+		} else {
+			log.Warnf("Server cert of %s is not readable, re-issue it for the hosts of the csr only", cb.Name)
+		}
+		log.Infof("Rotating server cert of %s", cb.Name)
+		if err := issueServerCert(cb, strings.Join(hosts, ",")); err != nil {
+			return err
+		}
+	}
+	if cb.Client != nil && cb.Client.Cert != "" {
+		log.Infof("Rotating client cert of %s", cb.Name)
+		if err := GenerateCertBundle(cb, CLIENTCERT, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
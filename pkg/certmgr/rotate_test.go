@@ -0,0 +1,130 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package certmgr_test
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	cmapi "github.com/intel/edge-conductor/pkg/api/certmgr"
+	certmgr "github.com/intel/edge-conductor/pkg/certmgr"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prashantv/gostub"
+)
+
+func readTestCert(certFile string) *x509.Certificate {
+	raw, err := ioutil.ReadFile(certFile)
+	Expect(err).To(BeNil())
+	block, _ := pem.Decode(raw)
+	Expect(block).NotTo(BeNil())
+	cert, err := x509.ParseCertificate(block.Bytes)
+	Expect(err).To(BeNil())
+	return cert
+}
+
+var _ = Describe("Check cert status and rotation", func() {
+	var (
+		tmpDir    string
+		stub      *gostub.Stubs
+		initcerts cmapi.Certificate
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "certmgr")
+		Expect(err).To(BeNil())
+		stub = gostub.Stub(&certmgr.RUNTIMEPKIDIR, filepath.Join(tmpDir, "pki")).Stub(&certmgr.RUNTIMECFGDIR, filepath.Join(tmpDir, "config"))
+
+		initcerts = cmapi.Certificate{
+			Name: "workflow",
+			Ca: &cmapi.CertificateCa{
+				Cert: filepath.Join(tmpDir, "ca.crt"),
+				Csr:  TESTCACSR,
+				Key:  filepath.Join(tmpDir, "ca.key"),
+			},
+			Server: &cmapi.CertificateServer{
+				Cert: filepath.Join(tmpDir, "server.crt"),
+				Csr:  TESTWFSERVERCSR,
+				Key:  filepath.Join(tmpDir, "server.key"),
+			},
+			Client: &cmapi.CertificateClient{
+				Cert: filepath.Join(tmpDir, "client.crt"),
+				Csr:  TESTWFCLIENTCSR,
+				Key:  filepath.Join(tmpDir, "client.key"),
+			},
+		}
+		Expect(certmgr.GenCertAndConfig(initcerts, "10.10.10.10")).To(BeNil())
+	})
+
+	AfterEach(func() {
+		stub.Reset()
+		os.RemoveAll(tmpDir)
+	})
+
+	It("Lists the cert bundles with their status", func() {
+		bundles, err := certmgr.ListCertBundles()
+		Expect(err).To(BeNil())
+		Expect(bundles).To(HaveLen(1))
+		Expect(bundles[0].Name).To(Equal("workflow"))
+
+		now := time.Now()
+		statuses, err := certmgr.GetCertStatus(bundles[0], now)
+		Expect(err).To(BeNil())
+		Expect(statuses).To(HaveLen(3))
+		Expect(statuses[0].Type).To(Equal("ca"))
+		Expect(statuses[0].Status).To(Equal(certmgr.CertStatusValid))
+		Expect(statuses[1].Type).To(Equal("server"))
+		Expect(statuses[1].Subject).To(Equal("CN=Test Self Signed"))
+		Expect(statuses[1].Issuer).To(Equal(statuses[0].Subject))
+		Expect(statuses[1].SANs).To(Equal([]string{"localhost", "10.10.10.10", "127.0.0.1"}))
+		Expect(statuses[2].Type).To(Equal("client"))
+
+		statuses, err = certmgr.GetCertStatus(bundles[0], now.AddDate(1, 0, -10))
+		Expect(err).To(BeNil())
+		Expect(statuses[1].Status).To(Equal(certmgr.CertStatusExpiring))
+		statuses, err = certmgr.GetCertStatus(bundles[0], now.AddDate(1, 0, 1))
+		Expect(err).To(BeNil())
+		Expect(statuses[1].Status).To(Equal(certmgr.CertStatusExpired))
+	})
+
+	It("Fails to get the status of a missing cert", func() {
+		Expect(os.Remove(initcerts.Client.Cert)).To(BeNil())
+		_, err := certmgr.GetCertStatus(&initcerts, time.Now())
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("Re-issues the leaf certs from the existing CA", func() {
+		ca := readTestCert(initcerts.Ca.Cert)
+		server := readTestCert(initcerts.Server.Cert)
+		client := readTestCert(initcerts.Client.Cert)
+
+		Expect(certmgr.RotateCertBundle(&initcerts)).To(BeNil())
+
+		Expect(readTestCert(initcerts.Ca.Cert).SerialNumber).To(Equal(ca.SerialNumber))
+		rotated := readTestCert(initcerts.Server.Cert)
+		Expect(rotated.SerialNumber).NotTo(Equal(server.SerialNumber))
+		Expect(rotated.DNSNames).To(Equal(server.DNSNames))
+		Expect(rotated.IPAddresses).To(Equal(server.IPAddresses))
+		Expect(rotated.CheckSignatureFrom(ca)).To(BeNil())
+		Expect(readTestCert(initcerts.Client.Cert).SerialNumber).NotTo(Equal(client.SerialNumber))
+	})
+
+	It("Fails to rotate without the CA key", func() {
+		Expect(os.Remove(initcerts.Ca.Key)).To(BeNil())
+		Expect(certmgr.RotateCertBundle(&initcerts)).NotTo(BeNil())
+
+		initcerts.Ca.Key = ""
+		Expect(certmgr.RotateCertBundle(&initcerts)).To(Equal(eputils.GetError("errCertNil")))
+	})
+})
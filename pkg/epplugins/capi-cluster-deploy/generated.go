@@ -12,13 +12,17 @@ package capiclusterdeploy
 import (
 	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
-	epplugin "github.com/intel/edge-conductor/pkg/plugin"
+	pluginsdk "github.com/intel/edge-conductor/pkg/pluginsdk"
 )
 
 var (
 	Name   = "capi-cluster-deploy"
-	Input  = eputils.NewSchemaMapData()
-	Output = eputils.NewSchemaMapData()
+	Plugin = pluginsdk.New(Name).
+		WithInput("ep-params", &pluginapi.EpParams{}).
+		WithInput("cluster-manifest", &pluginapi.Clustermanifest{}).
+		WithOutput("kubeconfig", &pluginapi.Filecontent{})
+	Input  = Plugin.Input
+	Output = Plugin.Output
 )
 
 //nolint:unparam,deadcode,unused
@@ -42,13 +46,5 @@ func output_kubeconfig(outp *eputils.SchemaMapData) *pluginapi.Filecontent {
 }
 
 func init() {
-	eputils.AddSchemaStruct(__name("ep-params"), func() eputils.SchemaStruct { return &pluginapi.EpParams{} })
-	eputils.AddSchemaStruct(__name("cluster-manifest"), func() eputils.SchemaStruct { return &pluginapi.Clustermanifest{} })
-	eputils.AddSchemaStruct(__name("kubeconfig"), func() eputils.SchemaStruct { return &pluginapi.Filecontent{} })
-
-	Input[__name("ep-params")] = &pluginapi.EpParams{}
-	Input[__name("cluster-manifest")] = &pluginapi.Clustermanifest{}
-	Output[__name("kubeconfig")] = &pluginapi.Filecontent{}
-
-	epplugin.RegisterPlugin(Name, &Input, &Output, PluginMain)
+	Plugin.Register(PluginMain)
 }
@@ -91,6 +91,7 @@ func checkProvisionedMachine(ep_params *pluginapi.EpParams, workFolder, mCluster
 	count := 0
 
 	for count < TIMEOUT {
+		Plugin.ReportProgress("wait for machines", int64(count), TIMEOUT)
 		cmd := exec.Command(ep_params.Workspace+"/kubectl", "get", "machine", "-n", clusterConfig.WorkloadCluster.Namespace, "--kubeconfig", mClusterConfig)
 		outputStr, err := eputils.RunCMD(cmd)
 		if err != nil {
@@ -170,6 +171,7 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 	}
 
 	mClusterConfig := capiutils.GetManagementClusterKubeconfig(input_ep_params)
+	Plugin.ReportProgress("apply cluster", 0, 0)
 	err = applyCluster(input_ep_params, workFolder, mClusterConfig, &clusterConfig, &tmpl)
 	if err != nil {
 		log.Errorf("Cluster %s apply fail, %v", clusterConfig.WorkloadCluster.Name, err)
@@ -182,6 +184,7 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 		return err
 	}
 
+	Plugin.ReportProgress("get kubeconfig", 0, 0)
 	err = genClusterKubeconfig(input_ep_params, workFolder, mClusterConfig, &clusterConfig, &tmpl, output_kubeconfig)
 	if err != nil {
 		log.Errorf("Failed to get cluster %s kubeconfig, %v", clusterConfig.WorkloadCluster.Name, err)
@@ -10,14 +10,14 @@
 package capideinit
 
 import (
-	eputils "github.com/intel/edge-conductor/pkg/eputils"
-	epplugin "github.com/intel/edge-conductor/pkg/plugin"
+	pluginsdk "github.com/intel/edge-conductor/pkg/pluginsdk"
 )
 
 var (
 	Name   = "capi-deinit"
-	Input  = eputils.NewSchemaMapData()
-	Output = eputils.NewSchemaMapData()
+	Plugin = pluginsdk.New(Name)
+	Input  = Plugin.Input
+	Output = Plugin.Output
 )
 
 //nolint:unparam,deadcode,unused
@@ -26,6 +26,5 @@ func __name(n string) string {
 }
 
 func init() {
-
-	epplugin.RegisterPlugin(Name, &Input, &Output, PluginMain)
+	Plugin.Register(PluginMain)
 }
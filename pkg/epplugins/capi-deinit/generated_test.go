@@ -15,12 +15,10 @@ import (
 
 //nolint:deadcode,unused,unparam
 func generateInput(data map[string][]byte) eputils.SchemaMapData {
-	n := eputils.NewSchemaMapData()
-	return n
+	return Plugin.NewInput(data)
 }
 
 //nolint:unparam,deadcode,unused
 func generateOutput(data map[string][]byte) eputils.SchemaMapData {
-	n := eputils.NewSchemaMapData()
-	return n
+	return Plugin.NewOutput(data)
 }
@@ -12,13 +12,16 @@ package capihostprovision
 import (
 	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
-	epplugin "github.com/intel/edge-conductor/pkg/plugin"
+	pluginsdk "github.com/intel/edge-conductor/pkg/pluginsdk"
 )
 
 var (
 	Name   = "capi-host-provision"
-	Input  = eputils.NewSchemaMapData()
-	Output = eputils.NewSchemaMapData()
+	Plugin = pluginsdk.New(Name).
+		WithInput("ep-params", &pluginapi.EpParams{}).
+		WithInput("cluster-manifest", &pluginapi.Clustermanifest{})
+	Input  = Plugin.Input
+	Output = Plugin.Output
 )
 
 //nolint:unparam,deadcode,unused
@@ -37,11 +40,5 @@ func input_cluster_manifest(in eputils.SchemaMapData) *pluginapi.Clustermanifest
 }
 
 func init() {
-	eputils.AddSchemaStruct(__name("ep-params"), func() eputils.SchemaStruct { return &pluginapi.EpParams{} })
-	eputils.AddSchemaStruct(__name("cluster-manifest"), func() eputils.SchemaStruct { return &pluginapi.Clustermanifest{} })
-
-	Input[__name("ep-params")] = &pluginapi.EpParams{}
-	Input[__name("cluster-manifest")] = &pluginapi.Clustermanifest{}
-
-	epplugin.RegisterPlugin(Name, &Input, &Output, PluginMain)
+	Plugin.Register(PluginMain)
 }
@@ -12,13 +12,18 @@ package capiparser
 import (
 	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
-	epplugin "github.com/intel/edge-conductor/pkg/plugin"
+	pluginsdk "github.com/intel/edge-conductor/pkg/pluginsdk"
 )
 
 var (
 	Name   = "capi-parser"
-	Input  = eputils.NewSchemaMapData()
-	Output = eputils.NewSchemaMapData()
+	Plugin = pluginsdk.New(Name).
+		WithInput("ep-params", &pluginapi.EpParams{}).
+		WithInput("cluster-manifest", &pluginapi.Clustermanifest{}).
+		WithOutput("docker-images", &pluginapi.Images{}).
+		WithOutput("files", &pluginapi.Files{})
+	Input  = Plugin.Input
+	Output = Plugin.Output
 )
 
 //nolint:unparam,deadcode,unused
@@ -47,15 +52,5 @@ func output_files(outp *eputils.SchemaMapData) *pluginapi.Files {
 }
 
 func init() {
-	eputils.AddSchemaStruct(__name("ep-params"), func() eputils.SchemaStruct { return &pluginapi.EpParams{} })
-	eputils.AddSchemaStruct(__name("cluster-manifest"), func() eputils.SchemaStruct { return &pluginapi.Clustermanifest{} })
-	eputils.AddSchemaStruct(__name("docker-images"), func() eputils.SchemaStruct { return &pluginapi.Images{} })
-	eputils.AddSchemaStruct(__name("files"), func() eputils.SchemaStruct { return &pluginapi.Files{} })
-
-	Input[__name("ep-params")] = &pluginapi.EpParams{}
-	Input[__name("cluster-manifest")] = &pluginapi.Clustermanifest{}
-	Output[__name("docker-images")] = &pluginapi.Images{}
-	Output[__name("files")] = &pluginapi.Files{}
-
-	epplugin.RegisterPlugin(Name, &Input, &Output, PluginMain)
+	Plugin.Register(PluginMain)
 }
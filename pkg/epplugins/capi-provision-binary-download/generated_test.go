@@ -10,50 +10,15 @@
 package capiprovisionbinarydownload
 
 import (
-	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
 )
 
-//nolint:deadcode,unused
-func generate_input_ep_params(data []byte, in eputils.SchemaMapData) bool {
-	inputStruct := &pluginapi.EpParams{}
-	if data != nil {
-		if err := inputStruct.UnmarshalBinary(data); err != nil {
-			return false
-		}
-	}
-
-	in[__name("ep-params")] = inputStruct
-	return true
-}
-
-//nolint:deadcode,unused
-func generate_input_cluster_manifest(data []byte, in eputils.SchemaMapData) bool {
-	inputStruct := &pluginapi.Clustermanifest{}
-	if data != nil {
-		if err := inputStruct.UnmarshalBinary(data); err != nil {
-			return false
-		}
-	}
-
-	in[__name("cluster-manifest")] = inputStruct
-	return true
-}
-
 //nolint:deadcode,unused,unparam
 func generateInput(data map[string][]byte) eputils.SchemaMapData {
-	n := eputils.NewSchemaMapData()
-	if result := generate_input_ep_params(data["ep-params"], n); !result {
-		return nil
-	}
-	if result := generate_input_cluster_manifest(data["cluster-manifest"], n); !result {
-		return nil
-	}
-	return n
+	return Plugin.NewInput(data)
 }
 
 //nolint:unparam,deadcode,unused
 func generateOutput(data map[string][]byte) eputils.SchemaMapData {
-	n := eputils.NewSchemaMapData()
-	return n
+	return Plugin.NewOutput(data)
 }
@@ -12,13 +12,17 @@ package debugdump
 import (
 	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
-	epplugin "github.com/intel/edge-conductor/pkg/plugin"
+	pluginsdk "github.com/intel/edge-conductor/pkg/pluginsdk"
 )
 
 var (
 	Name   = "debug-dump"
-	Input  = eputils.NewSchemaMapData()
-	Output = eputils.NewSchemaMapData()
+	Plugin = pluginsdk.New(Name).
+		WithInput("nodes", &pluginapi.Nodes{}).
+		WithInput("docker-images", &pluginapi.Images{}).
+		WithInput("local-docker-images", &pluginapi.Images{})
+	Input  = Plugin.Input
+	Output = Plugin.Output
 )
 
 //nolint:unparam,deadcode,unused
@@ -42,13 +46,5 @@ func input_local_docker_images(in eputils.SchemaMapData) *pluginapi.Images {
 }
 
 func init() {
-	eputils.AddSchemaStruct(__name("nodes"), func() eputils.SchemaStruct { return &pluginapi.Nodes{} })
-	eputils.AddSchemaStruct(__name("docker-images"), func() eputils.SchemaStruct { return &pluginapi.Images{} })
-	eputils.AddSchemaStruct(__name("local-docker-images"), func() eputils.SchemaStruct { return &pluginapi.Images{} })
-
-	Input[__name("nodes")] = &pluginapi.Nodes{}
-	Input[__name("docker-images")] = &pluginapi.Images{}
-	Input[__name("local-docker-images")] = &pluginapi.Images{}
-
-	epplugin.RegisterPlugin(Name, &Input, &Output, PluginMain)
+	Plugin.Register(PluginMain)
 }
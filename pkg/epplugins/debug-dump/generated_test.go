@@ -10,66 +10,15 @@
 package debugdump
 
 import (
-	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
 )
 
-//nolint:deadcode,unused
-func generate_input_nodes(data []byte, in eputils.SchemaMapData) bool {
-	inputStruct := &pluginapi.Nodes{}
-	if data != nil {
-		if err := inputStruct.UnmarshalBinary(data); err != nil {
-			return false
-		}
-	}
-
-	in[__name("nodes")] = inputStruct
-	return true
-}
-
-//nolint:deadcode,unused
-func generate_input_docker_images(data []byte, in eputils.SchemaMapData) bool {
-	inputStruct := &pluginapi.Images{}
-	if data != nil {
-		if err := inputStruct.UnmarshalBinary(data); err != nil {
-			return false
-		}
-	}
-
-	in[__name("docker-images")] = inputStruct
-	return true
-}
-
-//nolint:deadcode,unused
-func generate_input_local_docker_images(data []byte, in eputils.SchemaMapData) bool {
-	inputStruct := &pluginapi.Images{}
-	if data != nil {
-		if err := inputStruct.UnmarshalBinary(data); err != nil {
-			return false
-		}
-	}
-
-	in[__name("local-docker-images")] = inputStruct
-	return true
-}
-
 //nolint:deadcode,unused,unparam
 func generateInput(data map[string][]byte) eputils.SchemaMapData {
-	n := eputils.NewSchemaMapData()
-	if result := generate_input_nodes(data["nodes"], n); !result {
-		return nil
-	}
-	if result := generate_input_docker_images(data["docker-images"], n); !result {
-		return nil
-	}
-	if result := generate_input_local_docker_images(data["local-docker-images"], n); !result {
-		return nil
-	}
-	return n
+	return Plugin.NewInput(data)
 }
 
 //nolint:unparam,deadcode,unused
 func generateOutput(data map[string][]byte) eputils.SchemaMapData {
-	n := eputils.NewSchemaMapData()
-	return n
+	return Plugin.NewOutput(data)
 }
@@ -9,6 +9,7 @@
 package dockerimagedownloader
 
 import (
+	"fmt"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
 	docker "github.com/intel/edge-conductor/pkg/eputils/docker"
 	restfulcli "github.com/intel/edge-conductor/pkg/eputils/restfulcli"
@@ -68,9 +69,12 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 			images_download = append(images_download, url)
 		}
 	}
-	for _, v := range images_download {
+	for i, v := range images_download {
+		phase := fmt.Sprintf("pull images %d/%d", i+1, len(images_download))
 		log.Infof("Pull image %s", v)
-		if err := docker.ImagePull(v, nil); err != nil {
+		if err := docker.ImagePullWithProgress(v, nil, func(current, total int64) {
+			Plugin.ReportProgress(phase, current, total)
+		}); err != nil {
 			return err
 		}
 	}
@@ -82,7 +86,8 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 		return err
 	}
 
-	for _, img := range newImages {
+	for i, img := range newImages {
+		phase := fmt.Sprintf("push images %d/%d", i+1, len(newImages))
 		prefixUrl := img
 
 		newTag, err := docker.TagImageToLocal(prefixUrl, auth.ServerAddress)
@@ -90,7 +95,9 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 			return err
 		}
 		log.Infof("Push %s to %s", prefixUrl, newTag)
-		if err := docker.ImagePush(newTag, auth); err != nil {
+		if err := docker.ImagePushWithProgress(newTag, auth, func(current, total int64) {
+			Plugin.ReportProgress(phase, current, total)
+		}); err != nil {
 			return err
 		}
 
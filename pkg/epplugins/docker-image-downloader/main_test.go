@@ -56,7 +56,7 @@ func TestPluginMain(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		patchImagePull, err := mpatch.PatchMethod(docker.ImagePull, mockDockerCli.ImagePull)
+		patchImagePull, err := mpatch.PatchMethod(docker.ImagePullWithProgress, mockDockerCli.ImagePullWithProgress)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -68,7 +68,7 @@ func TestPluginMain(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		patchImagePush, err := mpatch.PatchMethod(docker.ImagePush, mockDockerCli.ImagePush)
+		patchImagePush, err := mpatch.PatchMethod(docker.ImagePushWithProgress, mockDockerCli.ImagePushWithProgress)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -76,11 +76,11 @@ func TestPluginMain(t *testing.T) {
 		mockDockerCli.EXPECT().GetHostImages().AnyTimes().Return(nil, nil)
 		fakeAuth := &types.AuthConfig{ServerAddress: "10.10.10.10"}
 		mockDockerCli.EXPECT().GetAuthConf(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(fakeAuth, nil)
-		mockDockerCli.EXPECT().ImagePull(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		mockDockerCli.EXPECT().ImagePullWithProgress(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 		fakeNewImages := []string{"aaa", "bbb"}
 		mockRestyCli.EXPECT().MapImageURLCreateHarborProject(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fakeNewImages, nil)
 		mockDockerCli.EXPECT().TagImageToLocal(gomock.Any(), gomock.Any()).AnyTimes().Return("", nil)
-		mockDockerCli.EXPECT().ImagePush(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		mockDockerCli.EXPECT().ImagePushWithProgress(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 		return []*mpatch.Patch{patchGetHostImages, patchGetAuthConf, patchForcedownload, patchImagePull, patchMapImageURLCreateHarborProject, patchTagImageToLocal, patchImagePush}
 	}
 
@@ -127,13 +127,13 @@ func TestPluginMain(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		patchImagePull, err := mpatch.PatchMethod(docker.ImagePull, mockDockerCli.ImagePull)
+		patchImagePull, err := mpatch.PatchMethod(docker.ImagePullWithProgress, mockDockerCli.ImagePullWithProgress)
 		if err != nil {
 			t.Fatal(err)
 		}
 		mockDockerCli.EXPECT().GetHostImages().AnyTimes().Return(nil, nil)
 		mockDockerCli.EXPECT().GetAuthConf(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
-		mockDockerCli.EXPECT().ImagePull(gomock.Any(), gomock.Any()).AnyTimes().Return(errTest)
+		mockDockerCli.EXPECT().ImagePullWithProgress(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(errTest)
 		return []*mpatch.Patch{patchGetHostImages, patchGetAuthConf, patchForcedownload, patchImagePull}
 	}
 	func_HarborProject_err := func(ctrl *gomock.Controller, ctrl1 *gomock.Controller) []*mpatch.Patch {
@@ -155,7 +155,7 @@ func TestPluginMain(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		patchImagePull, err := mpatch.PatchMethod(docker.ImagePull, mockDockerCli.ImagePull)
+		patchImagePull, err := mpatch.PatchMethod(docker.ImagePullWithProgress, mockDockerCli.ImagePullWithProgress)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -165,7 +165,7 @@ func TestPluginMain(t *testing.T) {
 		}
 		mockDockerCli.EXPECT().GetHostImages().AnyTimes().Return(nil, nil)
 		mockDockerCli.EXPECT().GetAuthConf(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
-		mockDockerCli.EXPECT().ImagePull(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		mockDockerCli.EXPECT().ImagePullWithProgress(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 		mockRestyCli.EXPECT().MapImageURLCreateHarborProject(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil, errTest)
 		return []*mpatch.Patch{patchGetHostImages, patchGetAuthConf, patchForcedownload, patchImagePull, patchMapImageURLCreateHarborProject}
 	}
@@ -189,7 +189,7 @@ func TestPluginMain(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		patchImagePull, err := mpatch.PatchMethod(docker.ImagePull, mockDockerCli.ImagePull)
+		patchImagePull, err := mpatch.PatchMethod(docker.ImagePullWithProgress, mockDockerCli.ImagePullWithProgress)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -204,7 +204,7 @@ func TestPluginMain(t *testing.T) {
 		mockDockerCli.EXPECT().GetHostImages().AnyTimes().Return(nil, nil)
 		fakeAuth := &types.AuthConfig{ServerAddress: "10.10.10.10"}
 		mockDockerCli.EXPECT().GetAuthConf(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(fakeAuth, nil)
-		mockDockerCli.EXPECT().ImagePull(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		mockDockerCli.EXPECT().ImagePullWithProgress(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 		fakeNewImages := []string{"aaa", "bbb"}
 		mockRestyCli.EXPECT().MapImageURLCreateHarborProject(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fakeNewImages, nil)
 		mockDockerCli.EXPECT().TagImageToLocal(gomock.Any(), gomock.Any()).AnyTimes().Return("", errTest)
@@ -229,7 +229,7 @@ func TestPluginMain(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		patchImagePull, err := mpatch.PatchMethod(docker.ImagePull, mockDockerCli.ImagePull)
+		patchImagePull, err := mpatch.PatchMethod(docker.ImagePullWithProgress, mockDockerCli.ImagePullWithProgress)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -241,7 +241,7 @@ func TestPluginMain(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		patchImagePush, err := mpatch.PatchMethod(docker.ImagePush, mockDockerCli.ImagePush)
+		patchImagePush, err := mpatch.PatchMethod(docker.ImagePushWithProgress, mockDockerCli.ImagePushWithProgress)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -249,11 +249,11 @@ func TestPluginMain(t *testing.T) {
 		mockDockerCli.EXPECT().GetHostImages().AnyTimes().Return(nil, nil)
 		fakeAuth := &types.AuthConfig{ServerAddress: "10.10.10.10"}
 		mockDockerCli.EXPECT().GetAuthConf(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(fakeAuth, nil)
-		mockDockerCli.EXPECT().ImagePull(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		mockDockerCli.EXPECT().ImagePullWithProgress(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 		fakeNewImages := []string{"aaa", "bbb"}
 		mockRestyCli.EXPECT().MapImageURLCreateHarborProject(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fakeNewImages, nil)
 		mockDockerCli.EXPECT().TagImageToLocal(gomock.Any(), gomock.Any()).AnyTimes().Return("", nil)
-		mockDockerCli.EXPECT().ImagePush(gomock.Any(), gomock.Any()).AnyTimes().Return(errTest)
+		mockDockerCli.EXPECT().ImagePushWithProgress(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(errTest)
 		return []*mpatch.Patch{patchGetHostImages, patchGetAuthConf, patchForcedownload, patchImagePull, patchMapImageURLCreateHarborProject, patchTagImageToLocal, patchImagePush}
 	}
 	cases := []struct {
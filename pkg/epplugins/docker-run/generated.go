@@ -12,13 +12,15 @@ package dockerrun
 import (
 	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
-	epplugin "github.com/intel/edge-conductor/pkg/plugin"
+	pluginsdk "github.com/intel/edge-conductor/pkg/pluginsdk"
 )
 
 var (
 	Name   = "docker-run"
-	Input  = eputils.NewSchemaMapData()
-	Output = eputils.NewSchemaMapData()
+	Plugin = pluginsdk.New(Name).
+		WithInput("containers", &pluginapi.Containers{})
+	Input  = Plugin.Input
+	Output = Plugin.Output
 )
 
 //nolint:unparam,deadcode,unused
@@ -32,9 +34,5 @@ func input_containers(in eputils.SchemaMapData) *pluginapi.Containers {
 }
 
 func init() {
-	eputils.AddSchemaStruct(__name("containers"), func() eputils.SchemaStruct { return &pluginapi.Containers{} })
-
-	Input[__name("containers")] = &pluginapi.Containers{}
-
-	epplugin.RegisterPlugin(Name, &Input, &Output, PluginMain)
+	Plugin.Register(PluginMain)
 }
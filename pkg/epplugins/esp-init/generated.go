@@ -12,13 +12,16 @@ package espinit
 import (
 	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
-	epplugin "github.com/intel/edge-conductor/pkg/plugin"
+	pluginsdk "github.com/intel/edge-conductor/pkg/pluginsdk"
 )
 
 var (
 	Name   = "esp-init"
-	Input  = eputils.NewSchemaMapData()
-	Output = eputils.NewSchemaMapData()
+	Plugin = pluginsdk.New(Name).
+		WithInput("ep-params", &pluginapi.EpParams{}).
+		WithInput("os-provider-manifest", &pluginapi.Osprovidermanifest{})
+	Input  = Plugin.Input
+	Output = Plugin.Output
 )
 
 //nolint:unparam,deadcode,unused
@@ -37,11 +40,5 @@ func input_os_provider_manifest(in eputils.SchemaMapData) *pluginapi.Osproviderm
 }
 
 func init() {
-	eputils.AddSchemaStruct(__name("ep-params"), func() eputils.SchemaStruct { return &pluginapi.EpParams{} })
-	eputils.AddSchemaStruct(__name("os-provider-manifest"), func() eputils.SchemaStruct { return &pluginapi.Osprovidermanifest{} })
-
-	Input[__name("ep-params")] = &pluginapi.EpParams{}
-	Input[__name("os-provider-manifest")] = &pluginapi.Osprovidermanifest{}
-
-	epplugin.RegisterPlugin(Name, &Input, &Output, PluginMain)
+	Plugin.Register(PluginMain)
 }
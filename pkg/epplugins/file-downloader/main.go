@@ -9,17 +9,62 @@
 package filedownloader
 
 import (
+	"fmt"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/registry"
 
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
 	repoutils "github.com/intel/edge-conductor/pkg/eputils/repoutils"
 )
 
+// chartFileName returns the archive name of a chart of an OCI registry, like
+// "mychart.tgz" for "org/charts/mychart:1.0.0" or "org/charts/mychart@sha256:...".
+func chartFileName(ref string) string {
+	name := path.Base(ref)
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+	if i := strings.Index(name, ":"); i >= 0 {
+		name = name[:i]
+	}
+	return name + ".tgz"
+}
+
+// downloadFile downloads a file to the target file. A local directory, like
+// the bases and overlays of a kustomize component, is archived into a tar.gz
+// file next to the target file instead, and the archive is returned. A chart
+// of an OCI registry is pulled with the Helm registry client. The bytes
+// written to the target file are reported to progress.
+func downloadFile(targetFile, fileurl string, progress eputils.ProgressFunc) (string, error) {
+	u, err := url.Parse(fileurl)
+	if err == nil && u.Scheme == registry.OCIScheme {
+		targetFile = filepath.Join(filepath.Dir(targetFile), chartFileName(u.Path))
+		return targetFile, repoutils.PullChartFromRegistry(targetFile, fileurl)
+	}
+	if err != nil || u.Scheme != "file" || !eputils.IsDirectory(u.Path) {
+		return targetFile, eputils.DownloadFileWithProgress(targetFile, fileurl, progress)
+	}
+	tarFile := targetFile + ".tar"
+	if err := eputils.CompressTar(u.Path, tarFile, 0600); err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := eputils.RemoveFile(tarFile); err != nil {
+			log.Errorln(err)
+		}
+	}()
+	if err := eputils.GzipCompress(tarFile, filepath.Dir(tarFile)); err != nil {
+		return "", err
+	}
+	return tarFile + ".gz", nil
+}
+
 func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 	input_ep_params := input_ep_params(in)
 	input_files := input_files(in)
@@ -34,7 +79,8 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 		}
 	}()
 
-	for _, file := range input_files.Files {
+	for i, file := range input_files.Files {
+		phase := fmt.Sprintf("download files %d/%d", i+1, len(input_files.Files))
 		fileurl := file.URL
 
 		fileName := path.Base(fileurl)
@@ -45,7 +91,10 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 		}
 
 		log.Infof("Downloading %s", fileurl)
-		if err := eputils.DownloadFile(targetFile, fileurl); err != nil {
+		targetFile, err := downloadFile(targetFile, fileurl, func(current, total int64) {
+			Plugin.ReportProgress(phase, current, total)
+		})
+		if err != nil {
 			log.Errorln("Failed to download", fileurl)
 			log.Errorln(err)
 			return eputils.GetError("errDownload")
@@ -68,7 +117,12 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 
 			log.Infof("Downloaded successfully.")
 
-			ref, err := repoutils.PushFileToRepo(targetFile, file.Urlreplacement.New, "")
+			var ref string
+			if file.Helmchart {
+				ref, err = repoutils.PushChartToRepo(targetFile, file.Urlreplacement.New)
+			} else {
+				ref, err = repoutils.PushFileToRepo(targetFile, file.Urlreplacement.New, "")
+			}
 			if err != nil {
 				return err
 			}
@@ -83,6 +137,5 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 				file)
 		}
 	}
-
 	return nil
 }
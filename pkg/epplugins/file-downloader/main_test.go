@@ -71,6 +71,7 @@ func patchRemoveFile(t *testing.T, retError error) {
 
 func TestPluginMain(t *testing.T) {
 	fakefile := filepath.Join(getRuntimeFolder(), "testdata", "fakefile")
+	fakedir := filepath.Join(getRuntimeFolder(), "testdata", "fakedir")
 
 	cases := []struct {
 		name                  string
@@ -177,6 +178,88 @@ func TestPluginMain(t *testing.T) {
 			expectError:    false,
 			expectErrorMsg: "",
 		},
+		{
+			name: "Success: directory",
+			input: map[string][]byte{
+				"ep-params": []byte(`{"runtimedir":"testdata"}`),
+				"files":     []byte(`{"files":[{"url":"file://` + fakedir + `","mirrorurl":"","urlreplacement":{"new":"test"}}]}`),
+			},
+			expectedOutput: map[string][]byte{
+				"files": []byte(`{"files":[{"url":"file://` + fakedir + `","mirrorurl":"","urlreplacement":{"new":"test"},"mirrorurl":"` + "testoutput" + `"}]}`),
+			},
+			funcBeforeTest: func() {
+				var patch *mpatch.Patch
+				var err error
+				patch, err = mpatch.PatchMethod(repoutils.PushFileToRepo, func(file, subRef, rev string) (string, error) {
+					unpatch(t, patch)
+					if filepath.Base(file) != "fakedir.tar.gz" {
+						t.Errorf("Unexpected archive %s", file)
+					}
+					dir := t.TempDir()
+					if err := eputils.UncompressTgz(file, dir); err != nil {
+						t.Error(err)
+					}
+					if !eputils.FileExists(filepath.Join(dir, "fakedir", "fakefile")) {
+						t.Error("fakefile not found in the archive")
+					}
+					return "testoutput", nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+			expectError:    false,
+			expectErrorMsg: "",
+		},
+		{
+			name: "Success: OCI chart",
+			input: map[string][]byte{
+				"ep-params": []byte(`{"runtimedir":"testdata"}`),
+				"files":     []byte(`{"files":[{"url":"oci://ghcr.io/org/charts/mychart:1.0.0","hash":"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08","hashtype":"sha256","helmchart":true,"urlreplacement":{"new":"test"}}]}`),
+			},
+			expectedOutput: map[string][]byte{
+				"files": []byte(`{"files":[{"url":"oci://ghcr.io/org/charts/mychart:1.0.0","hash":"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08","hashtype":"sha256","helmchart":true,"urlreplacement":{"new":"test"},"mirrorurl":"oci://10.10.10.10/library/test/mychart:1.0.0"}]}`),
+			},
+			funcBeforeTest: func() {
+				var pullPatch, pushPatch *mpatch.Patch
+				var err error
+				pullPatch, err = mpatch.PatchMethod(repoutils.PullChartFromRegistry, func(file, ref string) error {
+					unpatch(t, pullPatch)
+					if filepath.Base(file) != "mychart.tgz" {
+						t.Errorf("Unexpected chart file %s", file)
+					}
+					return eputils.WriteStringToFile("test", file)
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				pushPatch, err = mpatch.PatchMethod(repoutils.PushChartToRepo, func(file, subRef string) (string, error) {
+					unpatch(t, pushPatch)
+					return "oci://10.10.10.10/library/test/mychart:1.0.0", nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+			expectError:    false,
+			expectErrorMsg: "",
+		},
+		{
+			name: "Error Case: pull OCI chart failed",
+			input: map[string][]byte{
+				"ep-params": []byte(`{"runtimedir":"testdata"}`),
+				"files":     []byte(`{"files":[{"url":"oci://ghcr.io/org/charts/mychart@sha256:0123","helmchart":true,"urlreplacement":{"new":"test"}}]}`),
+			},
+			funcBeforeTest: func() {
+				var patch *mpatch.Patch
+				patch, _ = mpatch.PatchMethod(repoutils.PullChartFromRegistry, func(file, ref string) error {
+					unpatch(t, patch)
+					return testErr
+				})
+			},
+			expectError:    true,
+			expectErrorMsg: eputils.GetError("errDownload").Error(),
+		},
 		{
 			name: "Success: RemoveAll error",
 			input: map[string][]byte{
@@ -12,13 +12,16 @@ package fileexporter
 import (
 	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
-	epplugin "github.com/intel/edge-conductor/pkg/plugin"
+	pluginsdk "github.com/intel/edge-conductor/pkg/pluginsdk"
 )
 
 var (
 	Name   = "file-exporter"
-	Input  = eputils.NewSchemaMapData()
-	Output = eputils.NewSchemaMapData()
+	Plugin = pluginsdk.New(Name).
+		WithInput("exportcontent", &pluginapi.Filecontent{}).
+		WithInput("exportpath", &pluginapi.Filepath{})
+	Input  = Plugin.Input
+	Output = Plugin.Output
 )
 
 //nolint:unparam,deadcode,unused
@@ -37,11 +40,5 @@ func input_exportpath(in eputils.SchemaMapData) *pluginapi.Filepath {
 }
 
 func init() {
-	eputils.AddSchemaStruct(__name("exportcontent"), func() eputils.SchemaStruct { return &pluginapi.Filecontent{} })
-	eputils.AddSchemaStruct(__name("exportpath"), func() eputils.SchemaStruct { return &pluginapi.Filepath{} })
-
-	Input[__name("exportcontent")] = &pluginapi.Filecontent{}
-	Input[__name("exportpath")] = &pluginapi.Filepath{}
-
-	epplugin.RegisterPlugin(Name, &Input, &Output, PluginMain)
+	Plugin.Register(PluginMain)
 }
@@ -10,50 +10,15 @@
 package fileexporter
 
 import (
-	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
 )
 
-//nolint:deadcode,unused
-func generate_input_exportcontent(data []byte, in eputils.SchemaMapData) bool {
-	inputStruct := &pluginapi.Filecontent{}
-	if data != nil {
-		if err := inputStruct.UnmarshalBinary(data); err != nil {
-			return false
-		}
-	}
-
-	in[__name("exportcontent")] = inputStruct
-	return true
-}
-
-//nolint:deadcode,unused
-func generate_input_exportpath(data []byte, in eputils.SchemaMapData) bool {
-	inputStruct := &pluginapi.Filepath{}
-	if data != nil {
-		if err := inputStruct.UnmarshalBinary(data); err != nil {
-			return false
-		}
-	}
-
-	in[__name("exportpath")] = inputStruct
-	return true
-}
-
 //nolint:deadcode,unused,unparam
 func generateInput(data map[string][]byte) eputils.SchemaMapData {
-	n := eputils.NewSchemaMapData()
-	if result := generate_input_exportcontent(data["exportcontent"], n); !result {
-		return nil
-	}
-	if result := generate_input_exportpath(data["exportpath"], n); !result {
-		return nil
-	}
-	return n
+	return Plugin.NewInput(data)
 }
 
 //nolint:unparam,deadcode,unused
 func generateOutput(data map[string][]byte) eputils.SchemaMapData {
-	n := eputils.NewSchemaMapData()
-	return n
+	return Plugin.NewOutput(data)
 }
@@ -31,9 +31,12 @@ import (
 	_ "github.com/intel/edge-conductor/pkg/epplugins/rke-parser"
 	_ "github.com/intel/edge-conductor/pkg/epplugins/service-build"
 	_ "github.com/intel/edge-conductor/pkg/epplugins/service-deployer"
+	_ "github.com/intel/edge-conductor/pkg/epplugins/service-diff"
 	_ "github.com/intel/edge-conductor/pkg/epplugins/service-injector"
 	_ "github.com/intel/edge-conductor/pkg/epplugins/service-list"
 	_ "github.com/intel/edge-conductor/pkg/epplugins/service-parser"
+	_ "github.com/intel/edge-conductor/pkg/epplugins/service-rollback"
+	_ "github.com/intel/edge-conductor/pkg/epplugins/service-tls-secret"
 )
 
 var PluginList []string = []string{
@@ -62,6 +65,9 @@ var PluginList []string = []string{
 	"pre-service-deploy",
 	"service-deployer",
 	"service-list",
+	"service-diff",
+	"service-rollback",
+	"service-tls-secret",
 	"node-join-deploy",
 	"node-join-prepare",
 }
@@ -12,13 +12,18 @@ package kinddeployer
 import (
 	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
-	epplugin "github.com/intel/edge-conductor/pkg/plugin"
+	pluginsdk "github.com/intel/edge-conductor/pkg/pluginsdk"
 )
 
 var (
 	Name   = "kind-deployer"
-	Input  = eputils.NewSchemaMapData()
-	Output = eputils.NewSchemaMapData()
+	Plugin = pluginsdk.New(Name).
+		WithInput("ep-params", &pluginapi.EpParams{}).
+		WithInput("files", &pluginapi.Files{}).
+		WithInput("kind-config", &pluginapi.Filecontent{}).
+		WithOutput("kubeconfig", &pluginapi.Filecontent{})
+	Input  = Plugin.Input
+	Output = Plugin.Output
 )
 
 //nolint:unparam,deadcode,unused
@@ -47,15 +52,5 @@ func output_kubeconfig(outp *eputils.SchemaMapData) *pluginapi.Filecontent {
 }
 
 func init() {
-	eputils.AddSchemaStruct(__name("ep-params"), func() eputils.SchemaStruct { return &pluginapi.EpParams{} })
-	eputils.AddSchemaStruct(__name("files"), func() eputils.SchemaStruct { return &pluginapi.Files{} })
-	eputils.AddSchemaStruct(__name("kind-config"), func() eputils.SchemaStruct { return &pluginapi.Filecontent{} })
-	eputils.AddSchemaStruct(__name("kubeconfig"), func() eputils.SchemaStruct { return &pluginapi.Filecontent{} })
-
-	Input[__name("ep-params")] = &pluginapi.EpParams{}
-	Input[__name("files")] = &pluginapi.Files{}
-	Input[__name("kind-config")] = &pluginapi.Filecontent{}
-	Output[__name("kubeconfig")] = &pluginapi.Filecontent{}
-
-	epplugin.RegisterPlugin(Name, &Input, &Output, PluginMain)
+	Plugin.Register(PluginMain)
 }
@@ -46,6 +46,9 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 	var cmd string
 	cri := nodeutils.GetCRI(nodelist)
 
+	if input_ep_params.Kitconfig.Parameters.GlobalSettings != nil {
+		eputils.SetHostKeyChecking(input_ep_params.Kitconfig.Parameters.GlobalSettings.SSHHostKeyChecking)
+	}
 	for _, node := range input_ep_params.Kitconfig.Parameters.Nodes {
 		if node.IP == "" || nodeutils.FindNodeInClusterByIP(nodelist, node.IP) {
 			log.Infof("Node(%s) already joined in the cluster!", node.IP)
@@ -58,7 +61,7 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 		}
 
 		nodeAddr := fmt.Sprintf("%s:%d", node.IP, node.SSHPort)
-		sshcfg, err := eputils.GenSSHConfig(node)
+		sshcfg, proxyJump, err := eputils.GenSSHConfig(node)
 		if err != nil {
 			log.Errorf("Fail to gen config %v", err)
 			return err
@@ -70,7 +73,7 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 			cmd = fmt.Sprintf("sudo %s", joinCMD)
 		}
 
-		err = eputils.RunRemoteCMD(nodeAddr, sshcfg, cmd)
+		err = eputils.RunRemoteCMD(nodeAddr, sshcfg, proxyJump, cmd)
 		if err != nil {
 			log.Errorf("Failed to enable containerd %v ", err)
 			return err
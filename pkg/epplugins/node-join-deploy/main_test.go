@@ -188,8 +188,8 @@ func TestPluginMain(t *testing.T) {
 				return "", testError
 			})
 		patch6, _ := mpatch.PatchMethod(eputils.GenSSHConfig,
-			func(*pluginapi.Node) (*ssh.ClientConfig, error) {
-				return nil, testError
+			func(*pluginapi.Node) (*ssh.ClientConfig, []string, error) {
+				return nil, nil, testError
 			})
 		return []*mpatch.Patch{patch1, patch2, patch3, patch4, patch5, patch6}
 	}
@@ -215,11 +215,11 @@ func TestPluginMain(t *testing.T) {
 				return "", testError
 			})
 		patch6, _ := mpatch.PatchMethod(eputils.GenSSHConfig,
-			func(*pluginapi.Node) (*ssh.ClientConfig, error) {
-				return &ssh.ClientConfig{}, nil
+			func(*pluginapi.Node) (*ssh.ClientConfig, []string, error) {
+				return &ssh.ClientConfig{}, nil, nil
 			})
 		patch7, _ := mpatch.PatchMethod(eputils.RunRemoteCMD,
-			func(string, *ssh.ClientConfig, string) error {
+			func(string, *ssh.ClientConfig, []string, string) error {
 				return testError
 			})
 		return []*mpatch.Patch{patch1, patch2, patch3, patch4, patch5, patch6, patch7}
@@ -246,11 +246,11 @@ func TestPluginMain(t *testing.T) {
 				return "", testError
 			})
 		patch6, _ := mpatch.PatchMethod(eputils.GenSSHConfig,
-			func(*pluginapi.Node) (*ssh.ClientConfig, error) {
-				return &ssh.ClientConfig{}, nil
+			func(*pluginapi.Node) (*ssh.ClientConfig, []string, error) {
+				return &ssh.ClientConfig{}, nil, nil
 			})
 		patch7, _ := mpatch.PatchMethod(eputils.RunRemoteCMD,
-			func(string, *ssh.ClientConfig, string) error {
+			func(string, *ssh.ClientConfig, []string, string) error {
 				return nil
 			})
 		return []*mpatch.Patch{patch1, patch2, patch3, patch4, patch5, patch6, patch7}
@@ -12,13 +12,17 @@ package rkedeployer
 import (
 	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
-	epplugin "github.com/intel/edge-conductor/pkg/plugin"
+	pluginsdk "github.com/intel/edge-conductor/pkg/pluginsdk"
 )
 
 var (
 	Name   = "rke-deployer"
-	Input  = eputils.NewSchemaMapData()
-	Output = eputils.NewSchemaMapData()
+	Plugin = pluginsdk.New(Name).
+		WithInput("ep-params", &pluginapi.EpParams{}).
+		WithInput("files", &pluginapi.Files{}).
+		WithOutput("kubeconfig", &pluginapi.Filecontent{})
+	Input  = Plugin.Input
+	Output = Plugin.Output
 )
 
 //nolint:unparam,deadcode,unused
@@ -42,13 +46,5 @@ func output_kubeconfig(outp *eputils.SchemaMapData) *pluginapi.Filecontent {
 }
 
 func init() {
-	eputils.AddSchemaStruct(__name("ep-params"), func() eputils.SchemaStruct { return &pluginapi.EpParams{} })
-	eputils.AddSchemaStruct(__name("files"), func() eputils.SchemaStruct { return &pluginapi.Files{} })
-	eputils.AddSchemaStruct(__name("kubeconfig"), func() eputils.SchemaStruct { return &pluginapi.Filecontent{} })
-
-	Input[__name("ep-params")] = &pluginapi.EpParams{}
-	Input[__name("files")] = &pluginapi.Files{}
-	Output[__name("kubeconfig")] = &pluginapi.Filecontent{}
-
-	epplugin.RegisterPlugin(Name, &Input, &Output, PluginMain)
+	Plugin.Register(PluginMain)
 }
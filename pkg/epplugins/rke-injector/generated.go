@@ -12,13 +12,19 @@ package rkeinjector
 import (
 	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
-	epplugin "github.com/intel/edge-conductor/pkg/plugin"
+	pluginsdk "github.com/intel/edge-conductor/pkg/pluginsdk"
 )
 
 var (
 	Name   = "rke-injector"
-	Input  = eputils.NewSchemaMapData()
-	Output = eputils.NewSchemaMapData()
+	Plugin = pluginsdk.New(Name).
+		WithInput("ep-params", &pluginapi.EpParams{}).
+		WithInput("docker-images", &pluginapi.Images{}).
+		WithInput("files", &pluginapi.Files{}).
+		WithOutput("rkeconfig", &pluginapi.Filecontent{}).
+		WithOutput("docker-images", &pluginapi.Images{})
+	Input  = Plugin.Input
+	Output = Plugin.Output
 )
 
 //nolint:unparam,deadcode,unused
@@ -52,17 +58,5 @@ func output_docker_images(outp *eputils.SchemaMapData) *pluginapi.Images {
 }
 
 func init() {
-	eputils.AddSchemaStruct(__name("ep-params"), func() eputils.SchemaStruct { return &pluginapi.EpParams{} })
-	eputils.AddSchemaStruct(__name("docker-images"), func() eputils.SchemaStruct { return &pluginapi.Images{} })
-	eputils.AddSchemaStruct(__name("files"), func() eputils.SchemaStruct { return &pluginapi.Files{} })
-	eputils.AddSchemaStruct(__name("rkeconfig"), func() eputils.SchemaStruct { return &pluginapi.Filecontent{} })
-	eputils.AddSchemaStruct(__name("docker-images"), func() eputils.SchemaStruct { return &pluginapi.Images{} })
-
-	Input[__name("ep-params")] = &pluginapi.EpParams{}
-	Input[__name("docker-images")] = &pluginapi.Images{}
-	Input[__name("files")] = &pluginapi.Files{}
-	Output[__name("rkeconfig")] = &pluginapi.Filecontent{}
-	Output[__name("docker-images")] = &pluginapi.Images{}
-
-	epplugin.RegisterPlugin(Name, &Input, &Output, PluginMain)
+	Plugin.Register(PluginMain)
 }
@@ -20,7 +20,8 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 	input_ep_params := input_ep_params(in)
 	input_serviceconfig := input_serviceconfig(in)
 
-	for _, service := range input_serviceconfig.Components {
+	filter := svcutils.NewServiceFilter(input_ep_params.Cmdline)
+	for _, service := range filter.Filter(input_serviceconfig.Components) {
 		// Prepare certificates for services
 		err := svcutils.GenSvcTLSCertFromTLSExtension(input_ep_params.Extensions, service.Name)
 		if err != nil {
@@ -0,0 +1,133 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package servicedeployer
+
+import (
+	"strings"
+
+	epplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// serviceDeps returns for each service the indexes of the services it
+// depends on. A dependency which is not in the service list is not deployed
+// by this run, so it is ignored. It fails if the dependencies have a cycle.
+func serviceDeps(services []*epplugins.Component) ([][]int, error) {
+	index := map[string]int{}
+	for k, s := range services {
+		index[s.Name] = k
+	}
+	deps := make([][]int, len(services))
+	for k, s := range services {
+		for _, d := range s.DependsOn {
+			i, has := index[d]
+			if !has {
+				log.Warnf("Service %s depends on %s, which is not in the service list, ignored.", s.Name, d)
+				continue
+			}
+			deps[k] = append(deps[k], i)
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(services))
+	path := []string{}
+	var visit func(k int) error
+	visit = func(k int) error {
+		switch state[k] {
+		case visited:
+			return nil
+		case visiting:
+			log.Errorf("Service dependency cycle: %s -> %s", strings.Join(path, " -> "), services[k].Name)
+			return eputils.GetError("errServiceDependency")
+		}
+		state[k] = visiting
+		path = append(path, services[k].Name)
+		for _, d := range deps[k] {
+			if err := visit(d); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[k] = visited
+		return nil
+	}
+	for k := range services {
+		if err := visit(k); err != nil {
+			return nil, err
+		}
+	}
+	return deps, nil
+}
+
+// deployServices calls deploy for each service once the services it depends
+// on are deployed, with up to parallel services deployed at the same time.
+// Services which are ready at the same time are started in list order, so
+// with parallel 1 and no dependencies the services are deployed one by one
+// in list order. After a failure no more service is started, and the first
+// error is returned once the running services are finished.
+func deployServices(services []*epplugins.Component, parallel int, deploy func(*epplugins.Component) error) error {
+	deps, err := serviceDeps(services)
+	if err != nil {
+		return err
+	}
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	started := make([]bool, len(services))
+	done := make([]bool, len(services))
+	errs := make([]error, len(services))
+	finished := make(chan int)
+	isReady := func(k int) bool {
+		if started[k] {
+			return false
+		}
+		for _, d := range deps[k] {
+			if !done[d] {
+				return false
+			}
+		}
+		return true
+	}
+
+	running := 0
+	for {
+		if err == nil {
+			for k := range services {
+				if running >= parallel {
+					break
+				}
+				if !isReady(k) {
+					continue
+				}
+				started[k] = true
+				running++
+				go func(k int) {
+					errs[k] = deploy(services[k])
+					finished <- k
+				}(k)
+			}
+		}
+		if running == 0 {
+			return err
+		}
+		k := <-finished
+		running--
+		done[k] = true
+		if errs[k] != nil && err == nil {
+			err = errs[k]
+		}
+	}
+}
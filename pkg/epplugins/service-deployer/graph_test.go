@@ -0,0 +1,200 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package servicedeployer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	epplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	serviceutil "github.com/intel/edge-conductor/pkg/eputils/service"
+
+	"github.com/stretchr/testify/require"
+)
+
+func components(deps map[string][]string, names ...string) []*epplugins.Component {
+	services := []*epplugins.Component{}
+	for _, n := range names {
+		services = append(services, &epplugins.Component{Name: n, DependsOn: deps[n]})
+	}
+	return services
+}
+
+func TestServiceDeps(t *testing.T) {
+	deps, err := serviceDeps(components(map[string][]string{
+		"cert-manager":                {"cert-manager-crd"},
+		"cert-manager-cluster-issuer": {"cert-manager", "not-selected"},
+	}, "cert-manager-crd", "cert-manager", "cert-manager-cluster-issuer"))
+	require.NoError(t, err)
+	require.Equal(t, [][]int{nil, {0}, {1}}, deps)
+
+	_, err = serviceDeps(components(map[string][]string{
+		"a": {"c"},
+		"b": {"a"},
+		"c": {"b"},
+	}, "a", "b", "c", "d"))
+	require.Equal(t, eputils.GetError("errServiceDependency"), err)
+
+	_, err = serviceDeps(components(map[string][]string{"a": {"a"}}, "a"))
+	require.Equal(t, eputils.GetError("errServiceDependency"), err)
+}
+
+func TestDeployServices(t *testing.T) {
+	var lock sync.Mutex
+	order := []string{}
+	running, maxRunning := 0, 0
+	deploy := func(fail string) func(*epplugins.Component) error {
+		return func(s *epplugins.Component) error {
+			lock.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			lock.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			lock.Lock()
+			defer lock.Unlock()
+			running--
+			order = append(order, s.Name)
+			if s.Name == fail {
+				return eputils.GetError("errServiceStatus")
+			}
+			return nil
+		}
+	}
+	deps := map[string][]string{"b": {"d"}, "c": {"b"}}
+
+	require.NoError(t, deployServices(components(deps, "a", "b", "c", "d", "e"), 0, deploy("")))
+	require.Equal(t, []string{"a", "d", "b", "c", "e"}, order, "one by one, in list order after the dependencies")
+	require.Equal(t, 1, maxRunning)
+
+	order, maxRunning = []string{}, 0
+	require.NoError(t, deployServices(components(deps, "a", "b", "c", "d", "e"), 2, deploy("")))
+	require.Len(t, order, 5)
+	require.Equal(t, 2, maxRunning)
+	index := map[string]int{}
+	for i, n := range order {
+		index[n] = i
+	}
+	require.Less(t, index["d"], index["b"])
+	require.Less(t, index["b"], index["c"])
+
+	order, maxRunning = []string{}, 0
+	err := deployServices(components(deps, "a", "b", "c", "d", "e"), 1, deploy("d"))
+	require.Equal(t, eputils.GetError("errServiceStatus"), err)
+	require.Equal(t, []string{"a", "d"}, order, "no service is started after a failure")
+
+	require.Equal(t, eputils.GetError("errServiceDependency"),
+		deployServices(components(map[string][]string{"a": {"b"}, "b": {"a"}}, "a", "b"), 1, deploy("")))
+}
+
+// fakeKubeAPI serves the API requests of helm to install config maps, and
+// records the namespace each config map is created in by name.
+func fakeKubeAPI(t *testing.T) (*httptest.Server, map[string]string) {
+	var lock sync.Mutex
+	created := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		switch {
+		case r.URL.Path == "/version":
+			fmt.Fprint(w, `{"major":"1","minor":"23","gitVersion":"v1.23.4"}`)
+		case r.URL.Path == "/api":
+			fmt.Fprint(w, `{"kind":"APIVersions","versions":["v1"]}`)
+		case r.URL.Path == "/apis":
+			fmt.Fprint(w, `{"kind":"APIGroupList","apiVersion":"v1","groups":[]}`)
+		case r.URL.Path == "/api/v1":
+			fmt.Fprint(w, `{"kind":"APIResourceList","groupVersion":"v1","resources":[`+
+				`{"name":"configmaps","singularName":"","namespaced":true,"kind":"ConfigMap","verbs":["create","get","list"]}]}`)
+		case r.URL.Path == "/openapi/v2":
+			w.Header().Set("Content-Type", "application/octet-stream")
+		case len(parts) >= 5 && parts[4] == "configmaps" && r.Method == http.MethodPost:
+			body, _ := ioutil.ReadAll(r.Body)
+			cm := struct {
+				Metadata struct {
+					Name string `json:"name"`
+				} `json:"metadata"`
+			}{}
+			require.NoError(t, json.Unmarshal(body, &cm))
+			lock.Lock()
+			created[cm.Metadata.Name] = parts[3]
+			lock.Unlock()
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write(body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, created
+}
+
+func TestDeployServicesHelmParallel(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("HELM_DRIVER", "memory")
+	srv, created := fakeKubeAPI(t)
+
+	kubeconfig := filepath.Join(dir, "kubeconfig")
+	require.NoError(t, ioutil.WriteFile(kubeconfig, []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: %s
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+current-context: test
+users:
+- name: test
+  user:
+    token: test
+`, srv.URL)), 0600))
+
+	chart := filepath.Join(dir, "chart")
+	require.NoError(t, os.MkdirAll(filepath.Join(chart, "templates"), 0700))
+	require.NoError(t, eputils.WriteStringToFile("apiVersion: v2\nname: test\nversion: 0.1.0\n", filepath.Join(chart, "Chart.yaml")))
+	require.NoError(t, eputils.WriteStringToFile(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}
+`, filepath.Join(chart, "templates", "configmap.yaml")))
+
+	services := []*epplugins.Component{
+		{Name: "first", Namespace: "ns-first"},
+		{Name: "second", Namespace: "ns-second"},
+		{Name: "third", Namespace: "ns-third"},
+		{Name: "fourth", Namespace: "ns-fourth"},
+	}
+	// All the services start to install at once.
+	var started sync.WaitGroup
+	started.Add(len(services))
+	err := deployServices(services, len(services), func(s *epplugins.Component) error {
+		started.Done()
+		started.Wait()
+		return serviceutil.NewHelmDeployer(s.Name, s.Namespace, chart, "").HelmInstall(kubeconfig)
+	})
+	require.NoError(t, err)
+	for _, s := range services {
+		require.Equal(t, s.Namespace, created[s.Name], "service %s is installed in its namespace", s.Name)
+	}
+}
@@ -22,11 +22,15 @@ import (
 	serviceutil "github.com/intel/edge-conductor/pkg/eputils/service"
 	servicemock "github.com/intel/edge-conductor/pkg/eputils/service/mock"
 	fakekubeutils "github.com/intel/edge-conductor/pkg/eputils/test/fakekubeutils"
+	"io/ioutil"
+	"path/filepath"
 	"reflect"
 	"testing"
 
 	gomock "github.com/golang/mock/gomock"
 	mpatch "github.com/undefinedlabs/go-mpatch"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 var (
@@ -60,11 +64,18 @@ func (h *fakeDeployer) HelmStatus(loc_kubeconfig string) (string, int) {
 	//log.Infoln(h.name, h.rev)
 	return h.name, h.rev
 }
+func (h *fakeDeployer) HelmRelease(loc_kubeconfig string) (*release.Release, error) {
+	return nil, nil
+}
 func (h *fakeDeployer) HelmInstall(loc_kubeconfig string, arg ...serviceutil.InstallOpt) error {
 	h.rev = h.rev + 1
 	return nil
 }
-func (h *fakeDeployer) HelmUpgrade(loc_kubeconfig string) error {
+func (h *fakeDeployer) HelmUpgrade(loc_kubeconfig string, arg ...serviceutil.InstallOpt) error {
+	h.rev = h.rev + 1
+	return nil
+}
+func (h *fakeDeployer) HelmRollback(loc_kubeconfig string, revision int, arg ...serviceutil.InstallOpt) error {
 	h.rev = h.rev + 1
 	return nil
 }
@@ -614,6 +625,63 @@ func Test_findService(t *testing.T) {
 	}
 }
 
+func Test_recordObjects(t *testing.T) {
+	service := &epplugins.Component{Name: "test"}
+	if err := recordObjects(service, filepath.Join(t.TempDir(), "missing.yml"), "ns"); err == nil {
+		t.Error("Expected error but no error found.")
+	}
+
+	yamlFile := filepath.Join(t.TempDir(), "test.yml")
+	if err := ioutil.WriteFile(yamlFile, []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := recordObjects(service, yamlFile, "ns"); err != nil {
+		t.Error("Unexpected Error:", err)
+	}
+	objects, err := serviceutil.GetServiceObjects(service)
+	want := []*serviceutil.ReadyResource{{APIVersion: "v1", Kind: "ConfigMap", Name: "cm", Namespace: "ns"}}
+	if err != nil || !reflect.DeepEqual(objects, want) {
+		t.Errorf("recorded objects: %v, want %v", objects, want)
+	}
+}
+
+func Test_pullManifest(t *testing.T) {
+	tmpDir := t.TempDir()
+	targetFile := filepath.Join(tmpDir, "test.yml")
+	pulled := []string{}
+	p1, err := mpatch.PatchMethod(repoutils.PullFileFromRepo, func(file, url string) error {
+		pulled = append(pulled, filepath.Base(file)+" "+url)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unpatch(t, p1)
+	built := ""
+	p2, err := mpatch.PatchMethod(serviceutil.KustomizeBuild, func(archive, overlay, target string) error {
+		built = filepath.Base(archive) + " " + overlay + " " + filepath.Base(target)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unpatch(t, p2)
+
+	if err := pullManifest(&epplugins.Component{Name: "test", Type: "yaml", URL: "oci://yaml"}, targetFile, tmpDir); err != nil {
+		t.Error("Unexpected Error:", err)
+	}
+	if err := pullManifest(&epplugins.Component{Name: "test", Type: "kustomize", URL: "oci://kustomize", Overlay: "overlays/edge"}, targetFile, tmpDir); err != nil {
+		t.Error("Unexpected Error:", err)
+	}
+	want := []string{"test.yml oci://yaml", "test.tar.gz oci://kustomize"}
+	if !reflect.DeepEqual(pulled, want) {
+		t.Errorf("pulled files: %v, want %v", pulled, want)
+	}
+	if built != "test.tar.gz overlays/edge test.yml" {
+		t.Errorf("Unexpected kustomize build: %s", built)
+	}
+}
+
 func Test_getExpectedRevision(t *testing.T) {
 	cases := []struct {
 		name        string
@@ -848,3 +916,143 @@ func Test_updateConfigmap(t *testing.T) {
 		})
 	}
 }
+
+func TestPluginMainFilter(t *testing.T) {
+	newConfigMap := func(names ...string) *kubeutils.ConfigMap {
+		cm := &kubeutils.ConfigMap{Namespace: epNamespace, Name: epConfigMapName, Client: fake.NewSimpleClientset().CoreV1()}
+		if err := cm.New(); err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range names {
+			if err := serviceutil.RecordService(cm, &epplugins.Component{Name: name, Type: "yaml", URL: "file://" + name}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return cm
+	}
+
+	var configMap *kubeutils.ConfigMap
+	installed := []string{}
+	uninstalled := []string{}
+	patchFuncs := []func() (*mpatch.Patch, error){
+		func() (*mpatch.Patch, error) {
+			return mpatch.PatchMethod(kubeutils.CreateNamespace, func(string, string) error { return nil })
+		},
+		func() (*mpatch.Patch, error) {
+			return mpatch.PatchMethod(kubeutils.NewConfigMap, func(string, string, string, string) (kubeutils.ConfigMapWrapper, error) {
+				return configMap, nil
+			})
+		},
+		func() (*mpatch.Patch, error) {
+			return mpatch.PatchMethod(repoutils.PullFileFromRepo, func(string, string) error { return nil })
+		},
+		func() (*mpatch.Patch, error) {
+			return mpatch.PatchMethod(eputils.FileTemplateConvert, func(string, string) error { return nil })
+		},
+		func() (*mpatch.Patch, error) {
+			return mpatch.PatchMethod(serviceutil.NewYamlDeployer, func(name, _, _ string, _ ...interface{}) serviceutil.YamlDeployerWrapper {
+				return &fakeDeployer{name: name}
+			})
+		},
+		func() (*mpatch.Patch, error) {
+			return mpatch.PatchInstanceMethodByName(reflect.TypeOf(&fakeDeployer{}), "YamlInstall", func(h *fakeDeployer, _ string) error {
+				installed = append(installed, h.name)
+				return nil
+			})
+		},
+		func() (*mpatch.Patch, error) {
+			return mpatch.PatchInstanceMethodByName(reflect.TypeOf(&fakeDeployer{}), "YamlUninstall", func(h *fakeDeployer, _ string) error {
+				uninstalled = append(uninstalled, h.name)
+				return nil
+			})
+		},
+	}
+	for _, f := range patchFuncs {
+		p, err := f()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer unpatch(t, p)
+	}
+
+	cases := []struct {
+		name        string
+		applied     []string
+		cmdline     string
+		expectedErr error
+		installed   []string
+		uninstalled []string
+		left        []string
+	}{
+		{
+			name:        "remove_not_deployed",
+			applied:     []string{"a"},
+			cmdline:     "remove=b",
+			expectedErr: eputils.GetError("errServiceNotDeployed"),
+			installed:   []string{},
+			uninstalled: []string{},
+			left:        []string{"a"},
+		},
+		{
+			name:        "remove",
+			applied:     []string{"a", "b"},
+			cmdline:     "remove=b",
+			installed:   []string{},
+			uninstalled: []string{"b"},
+			left:        []string{"a"},
+		},
+		{
+			name:        "only",
+			applied:     []string{"old1", "old2"},
+			cmdline:     "only=a,old2",
+			installed:   []string{"a"},
+			uninstalled: []string{"old2"},
+			left:        []string{"a", "old1"},
+		},
+		{
+			name:        "exclude",
+			applied:     []string{"old1", "old2"},
+			cmdline:     "exclude=a,old1",
+			installed:   []string{"b"},
+			uninstalled: []string{"old2"},
+			left:        []string{"b", "old1"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			configMap = newConfigMap(tc.applied...)
+			installed = []string{}
+			uninstalled = []string{}
+			input := generateInput(map[string][]byte{
+				"ep-params": []byte(fmt.Sprintf(`{"runtimedir":"%s","kubeconfig":"","cmdline":%q}`, t.TempDir(), tc.cmdline)),
+				"serviceconfig": []byte(`{"Components":[{"name":"a","type":"yaml","url":"file://a"},
+				{"name":"b","type":"yaml","url":"file://b"}]}`),
+			})
+			if input == nil {
+				t.Fatal("Failed to generateInput")
+			}
+			testOutput := generateOutput(nil)
+			if err := PluginMain(input, &testOutput); err != tc.expectedErr {
+				t.Errorf("PluginMain() = %v, want %v", err, tc.expectedErr)
+			}
+			if !reflect.DeepEqual(installed, tc.installed) {
+				t.Errorf("installed %v, want %v", installed, tc.installed)
+			}
+			if !reflect.DeepEqual(uninstalled, tc.uninstalled) {
+				t.Errorf("uninstalled %v, want %v", uninstalled, tc.uninstalled)
+			}
+			left := []string{}
+			for _, name := range []string{"a", "b", "old1", "old2"} {
+				if _, ok := configMap.GetData()[name]; ok {
+					left = append(left, name)
+				}
+				if _, ok := configMap.GetBinaryData()[name]; ok != (configMap.GetData()[name] != "") {
+					t.Errorf("history of %s not in sync", name)
+				}
+			}
+			if !reflect.DeepEqual(left, tc.left) {
+				t.Errorf("services left %v, want %v", left, tc.left)
+			}
+		})
+	}
+}
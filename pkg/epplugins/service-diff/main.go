@@ -0,0 +1,322 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+// Template auto-generated once, maintained by plugin owner.
+
+package servicediff
+
+import (
+	"fmt"
+	epplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	kubeutils "github.com/intel/edge-conductor/pkg/eputils/kubeutils"
+	repoutils "github.com/intel/edge-conductor/pkg/eputils/repoutils"
+	serviceutil "github.com/intel/edge-conductor/pkg/eputils/service"
+	log "github.com/sirupsen/logrus"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+)
+
+const (
+	epConfigMapName          = "edgeconductor-service"
+	epNamespace              = "edgeconductor"
+	epFieldManagerName       = "Edge Conductor"
+	epConfigmapResourcesName = "serviceOverrideHash"
+)
+
+const (
+	changeAdd    = "add"
+	changeRemove = "remove"
+	changeUpdate = "update"
+	changeDrift  = "drift"
+)
+
+// serviceChange is a difference found for a service. An update is a change
+// of the service config since the last deploy, a drift is a change of the
+// cluster since the last deploy.
+type serviceChange struct {
+	name   string
+	typ    string
+	change string
+	detail string
+}
+
+// getAppliedServices returns the services recorded in the service ConfigMap
+// by the last deploy, none if the ConfigMap is not found.
+func getAppliedServices(kubeconfig string) (map[string]*epplugins.Component, error) {
+	applied := map[string]*epplugins.Component{}
+	configMap, err := kubeutils.NewConfigMap(epNamespace, epConfigMapName, epFieldManagerName, kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := configMap.Get(); err != nil {
+		log.Infoln("ConfigMap", epConfigMapName, "not found on cluster, no service deployed.")
+		return applied, nil
+	}
+	for name, yml := range configMap.GetData() {
+		service := &epplugins.Component{}
+		if err := eputils.LoadSchemaStructFromYaml(service, yml); err != nil {
+			log.Errorln("Failed to load service ConfigMap:", err)
+			return nil, err
+		}
+		applied[name] = service
+	}
+	return applied, nil
+}
+
+func overrideHash(service *epplugins.Component) string {
+	for _, resource := range service.Resources {
+		if resource.Name == epConfigmapResourcesName {
+			return resource.Value
+		}
+	}
+	return ""
+}
+
+// pullFile pulls a file of a service and returns its local path, after the
+// template conversion when convert is set, as the service-deployer does.
+func pullFile(tmpDir, name, url string, convert bool) (string, error) {
+	target := filepath.Join(tmpDir, name)
+	if err := repoutils.PullFileFromRepo(target, url); err != nil {
+		log.Errorln("Failed to pull file", url)
+		return "", err
+	}
+	if convert {
+		if err := eputils.FileTemplateConvert(target, target); err != nil {
+			log.Errorln("File Template Convert Failed:", err)
+		}
+	}
+	return target, nil
+}
+
+// pullManifest pulls the yaml file of a yaml service, or renders the overlay
+// of a kustomize service into a yaml file.
+func pullManifest(service *epplugins.Component, tmpDir string) (string, error) {
+	if service.Type != "kustomize" {
+		return pullFile(tmpDir, service.Name+".yml", service.URL, true)
+	}
+	archive, err := pullFile(tmpDir, service.Name+".tar.gz", service.URL, false)
+	if err != nil {
+		return "", err
+	}
+	target := filepath.Join(tmpDir, service.Name+".yml")
+	if err := serviceutil.KustomizeBuild(archive, service.Overlay, target); err != nil {
+		return "", err
+	}
+	if err := eputils.FileTemplateConvert(target, target); err != nil {
+		log.Errorln("File Template Convert Failed:", err)
+	}
+	return target, nil
+}
+
+// diffSpec returns the changes of the service config since the service was
+// applied.
+func diffSpec(service, applied *epplugins.Component, tmpDir string) ([]string, error) {
+	details := []string{}
+	compare := func(field, old, new string) {
+		if old != new {
+			details = append(details, fmt.Sprintf("%s: %q -> %q", field, old, new))
+		}
+	}
+	compare("type", applied.Type, service.Type)
+	compare("namespace", applied.Namespace, service.Namespace)
+	if service.Type != "helm" {
+		compare("url", applied.URL, service.URL)
+		compare("hash", applied.Hash, service.Hash)
+		compare("overlay", applied.Overlay, service.Overlay)
+		return details, nil
+	}
+
+	compare("chartversion", applied.Chartversion, service.Chartversion)
+	chartHash := service.Hash
+	if len(chartHash) == 0 && len(service.URL) > 0 {
+		chart, err := pullFile(tmpDir, service.Name+".tgz", service.URL, false)
+		if err != nil {
+			return nil, err
+		}
+		if chartHash, err = eputils.GenFileSHA256(chart); err != nil {
+			return nil, err
+		}
+	}
+	if applied.Hash != chartHash {
+		details = append(details, "chart changed")
+	}
+	valueHash := ""
+	if len(service.Chartoverride) > 0 {
+		value, err := pullFile(tmpDir, service.Name+".yml", service.Chartoverride, true)
+		if err != nil {
+			return nil, err
+		}
+		if valueHash, err = eputils.GenFileSHA256(value); err != nil {
+			return nil, err
+		}
+	}
+	if overrideHash(applied) != valueHash {
+		details = append(details, "chartoverride changed")
+	}
+	return details, nil
+}
+
+// diffRelease returns the changes of the Helm release of the service since
+// it was deployed.
+func diffRelease(applied *epplugins.Component, kubeconfig string) []string {
+	namespace := applied.Namespace
+	if len(namespace) <= 0 {
+		namespace = "default"
+	}
+	deployer := serviceutil.NewHelmDeployer(applied.Name, namespace, "", "")
+	status, rev := deployer.HelmStatus(kubeconfig)
+	switch {
+	case status == serviceutil.HELM_STATUS_NOT_DEPLOYED:
+		return []string{"release not found"}
+	case status != serviceutil.HELM_STATUS_DEPLOYED:
+		return []string{fmt.Sprintf("release status %s", status)}
+	case applied.Revision != fmt.Sprintf("%d", rev):
+		return []string{fmt.Sprintf("release revision %d, %s expected", rev, applied.Revision)}
+	}
+	return nil
+}
+
+// diffObjects returns the objects of an applied yaml or kustomize service
+// which are not on the cluster.
+func diffObjects(service *epplugins.Component, kubeconfig, tmpDir string) ([]string, error) {
+	namespace := service.Namespace
+	if len(namespace) <= 0 {
+		namespace = "default"
+	}
+	file, err := pullManifest(service, tmpDir)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := serviceutil.ResourcesFromManifest(data, namespace)
+	if err != nil {
+		return nil, err
+	}
+	checker, err := serviceutil.NewReadinessChecker(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	details := []string{}
+	for _, r := range resources {
+		exists, err := checker.Exists(r)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			details = append(details, fmt.Sprintf("%s not found", r))
+		}
+	}
+	return details, nil
+}
+
+// diffServices compares the services of the service config with the
+// applied services and the cluster.
+func diffServices(services []*epplugins.Component, applied map[string]*epplugins.Component, kubeconfig, tmpDir string) ([]serviceChange, error) {
+	changes := []serviceChange{}
+	add := func(service *epplugins.Component, change string, details []string) {
+		for _, d := range details {
+			changes = append(changes, serviceChange{service.Name, service.Type, change, d})
+		}
+	}
+	listed := map[string]bool{}
+	for _, service := range services {
+		// DCE services are not recorded by the service-deployer.
+		if service.Type != "yaml" && service.Type != "helm" && service.Type != "kustomize" {
+			continue
+		}
+		listed[service.Name] = true
+		a, found := applied[service.Name]
+		if !found {
+			add(service, changeAdd, []string{""})
+			continue
+		}
+		details, err := diffSpec(service, a, tmpDir)
+		if err != nil {
+			return nil, err
+		}
+		add(service, changeUpdate, details)
+
+		if a.Type == "helm" {
+			details = diffRelease(a, kubeconfig)
+		} else if a.Type == "yaml" || a.Type == "kustomize" {
+			if details, err = diffObjects(a, kubeconfig, tmpDir); err != nil {
+				return nil, err
+			}
+		}
+		add(service, changeDrift, details)
+	}
+
+	removed := []string{}
+	for name := range applied {
+		if !listed[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		add(applied[name], changeRemove, []string{""})
+	}
+	return changes, nil
+}
+
+func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
+	input_ep_params := input_ep_params(in)
+	input_serviceconfig := input_serviceconfig(in)
+
+	runtime_kubeconfig := input_ep_params.Kubeconfig
+
+	tmpDir := filepath.Join(input_ep_params.Runtimedir, "tmp")
+	defer func() {
+		err := os.RemoveAll(tmpDir)
+		if err != nil {
+			log.Errorln("failed to remove", tmpDir, err)
+		}
+	}()
+	if err := os.MkdirAll(tmpDir, 0700); err != nil {
+		return err
+	}
+
+	applied, err := getAppliedServices(runtime_kubeconfig)
+	if err != nil {
+		return err
+	}
+	changes, err := diffServices(input_serviceconfig.Components, applied, runtime_kubeconfig, tmpDir)
+	if err != nil {
+		return err
+	}
+
+	const padding = 3
+
+	w := tabwriter.NewWriter(
+		os.Stdout,
+		0, 0, padding, ' ',
+		tabwriter.FilterHTML)
+
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "\tNAME\tTYPE\tCHANGE\tDETAIL\t")
+	fmt.Fprintln(w, "\t====\t====\t======\t======\t")
+	for _, c := range changes {
+		fmt.Fprintf(w, "\t%s\t%s\t%s\t%s\t\n", c.name, c.typ, c.change, c.detail)
+	}
+	fmt.Fprintln(w, "")
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		log.Infoln("No difference found, the services are up to date.")
+	} else {
+		log.Infof("%d differences found.", len(changes))
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+// Template auto-generated once, maintained by plugin owner.
+
+package servicediff
+
+import (
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	epplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	kubeutils "github.com/intel/edge-conductor/pkg/eputils/kubeutils"
+	repoutils "github.com/intel/edge-conductor/pkg/eputils/repoutils"
+	serviceutil "github.com/intel/edge-conductor/pkg/eputils/service"
+	fakekubeutils "github.com/intel/edge-conductor/pkg/eputils/test/fakekubeutils"
+	fakeserviceutils "github.com/intel/edge-conductor/pkg/eputils/test/fakeserviceutils"
+
+	"github.com/stretchr/testify/require"
+	mpatch "github.com/undefinedlabs/go-mpatch"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+var errNotFound = errors.New("not found")
+
+// files are the contents pulled from the repo.
+var files = map[string]string{
+	"file://chart":    "chart",
+	"file://override": "override",
+	"file://yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: found
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: lost
+`,
+}
+
+// releases are the status and revision of the Helm releases.
+var releases = map[string]int{
+	"helm-same":    1,
+	"helm-changed": 2,
+	"helm-drift":   3,
+}
+
+func patchAll(t *testing.T, configMapData map[string]string) {
+	patches := []*mpatch.Patch{}
+	patch := func(p *mpatch.Patch, err error) {
+		require.NoError(t, err)
+		patches = append(patches, p)
+	}
+	t.Cleanup(func() {
+		for _, p := range patches {
+			require.NoError(t, p.Unpatch())
+		}
+	})
+
+	fakecm := &fakekubeutils.FakeConfigMap{}
+	patch(mpatch.PatchMethod(kubeutils.NewConfigMap, func(string, string, string, string) (kubeutils.ConfigMapWrapper, error) {
+		return fakecm, nil
+	}))
+	patch(mpatch.PatchInstanceMethodByName(reflect.TypeOf(fakecm), "Get", func(*fakekubeutils.FakeConfigMap) error {
+		if configMapData == nil {
+			return errNotFound
+		}
+		return nil
+	}))
+	patch(mpatch.PatchInstanceMethodByName(reflect.TypeOf(fakecm), "GetData", func(*fakekubeutils.FakeConfigMap) map[string]string {
+		return configMapData
+	}))
+	patch(mpatch.PatchMethod(repoutils.PullFileFromRepo, func(target, url string) error {
+		return ioutil.WriteFile(target, []byte(files[url]), 0600)
+	}))
+
+	var name string
+	fakeHelm := &fakeserviceutils.FakeHelmDeployer{}
+	patch(mpatch.PatchMethod(serviceutil.NewHelmDeployer, func(n, ns, charts, values string) serviceutil.HelmDeployerWrapper {
+		name = n
+		return fakeHelm
+	}))
+	patch(mpatch.PatchInstanceMethodByName(reflect.TypeOf(fakeHelm), "HelmStatus", func(*fakeserviceutils.FakeHelmDeployer, string) (string, int) {
+		if rev, ok := releases[name]; ok {
+			return serviceutil.HELM_STATUS_DEPLOYED, rev
+		}
+		return serviceutil.HELM_STATUS_NOT_DEPLOYED, 0
+	}))
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	found := &unstructured.Unstructured{}
+	found.SetAPIVersion("v1")
+	found.SetKind("ConfigMap")
+	found.SetNamespace("default")
+	found.SetName("found")
+	patch(mpatch.PatchMethod(serviceutil.NewReadinessChecker, func(string) (*serviceutil.ReadinessChecker, error) {
+		return &serviceutil.ReadinessChecker{
+			Client: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), found),
+			Mapper: mapper,
+		}, nil
+	}))
+}
+
+func applied(t *testing.T, services ...*epplugins.Component) map[string]string {
+	data := map[string]string{}
+	for _, s := range services {
+		yml, err := eputils.SchemaStructToYaml(s)
+		require.NoError(t, err)
+		data[s.Name] = yml
+	}
+	return data
+}
+
+func hash(t *testing.T, content string) string {
+	f := t.TempDir() + "/file"
+	require.NoError(t, ioutil.WriteFile(f, []byte(content), 0600))
+	h, err := eputils.GenFileSHA256(f)
+	require.NoError(t, err)
+	return h
+}
+
+func TestDiffServices(t *testing.T) {
+	overrideHash := []*epplugins.ComponentResourcesItems0{{Name: epConfigmapResourcesName, Value: hash(t, "override")}}
+	patchAll(t, applied(t,
+		&epplugins.Component{Name: "helm-same", Type: "helm", URL: "file://chart", Hash: hash(t, "chart"),
+			Chartoverride: "file://override", Revision: "1", Resources: overrideHash},
+		&epplugins.Component{Name: "helm-changed", Type: "helm", URL: "file://chart", Hash: "old",
+			Chartversion: "1.0", Revision: "2"},
+		&epplugins.Component{Name: "helm-drift", Type: "helm", Hash: "h", Revision: "1"},
+		&epplugins.Component{Name: "helm-lost", Type: "helm", Hash: "h", Revision: "1"},
+		&epplugins.Component{Name: "yaml", Type: "yaml", URL: "file://yaml"},
+		&epplugins.Component{Name: "removed", Type: "yaml", URL: "file://removed"},
+	))
+
+	applied, err := getAppliedServices("kubeconfig")
+	require.NoError(t, err)
+	require.Len(t, applied, 6)
+
+	changes, err := diffServices([]*epplugins.Component{
+		{Name: "helm-same", Type: "helm", URL: "file://chart", Chartoverride: "file://override"},
+		{Name: "helm-changed", Type: "helm", URL: "file://chart", Chartversion: "1.1", Chartoverride: "file://override"},
+		{Name: "helm-drift", Type: "helm", Hash: "h"},
+		{Name: "helm-lost", Type: "helm", Hash: "h"},
+		{Name: "yaml", Type: "yaml", URL: "file://yaml"},
+		{Name: "added", Type: "yaml", URL: "file://added"},
+		{Name: "dce", Type: "dce"},
+	}, applied, "kubeconfig", t.TempDir())
+	require.NoError(t, err)
+	require.Equal(t, []serviceChange{
+		{"helm-changed", "helm", changeUpdate, `chartversion: "1.0" -> "1.1"`},
+		{"helm-changed", "helm", changeUpdate, "chart changed"},
+		{"helm-changed", "helm", changeUpdate, "chartoverride changed"},
+		{"helm-drift", "helm", changeDrift, "release revision 3, 1 expected"},
+		{"helm-lost", "helm", changeDrift, "release not found"},
+		{"yaml", "yaml", changeDrift, "ConfigMap default/lost not found"},
+		{"added", "yaml", changeAdd, ""},
+		{"removed", "yaml", changeRemove, ""},
+	}, changes)
+}
+
+func TestPluginMain(t *testing.T) {
+	patchAll(t, nil)
+
+	in := eputils.SchemaMapData{
+		__name("ep-params"): &epplugins.EpParams{Kubeconfig: "kubeconfig", Runtimedir: t.TempDir()},
+		__name("serviceconfig"): &epplugins.Serviceconfig{Components: []*epplugins.Component{
+			{Name: "added", Type: "helm"},
+		}},
+	}
+	require.NoError(t, PluginMain(in, nil))
+}
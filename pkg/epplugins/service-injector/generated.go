@@ -12,13 +12,18 @@ package serviceinjector
 import (
 	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
-	epplugin "github.com/intel/edge-conductor/pkg/plugin"
+	pluginsdk "github.com/intel/edge-conductor/pkg/pluginsdk"
 )
 
 var (
 	Name   = "service-injector"
-	Input  = eputils.NewSchemaMapData()
-	Output = eputils.NewSchemaMapData()
+	Plugin = pluginsdk.New(Name).
+		WithInput("ep-params", &pluginapi.EpParams{}).
+		WithInput("downloadfiles", &pluginapi.Files{}).
+		WithInput("serviceconfig", &pluginapi.Serviceconfig{}).
+		WithOutput("serviceconfig", &pluginapi.Serviceconfig{})
+	Input  = Plugin.Input
+	Output = Plugin.Output
 )
 
 //nolint:unparam,deadcode,unused
@@ -47,15 +52,5 @@ func output_serviceconfig(outp *eputils.SchemaMapData) *pluginapi.Serviceconfig
 }
 
 func init() {
-	eputils.AddSchemaStruct(__name("ep-params"), func() eputils.SchemaStruct { return &pluginapi.EpParams{} })
-	eputils.AddSchemaStruct(__name("downloadfiles"), func() eputils.SchemaStruct { return &pluginapi.Files{} })
-	eputils.AddSchemaStruct(__name("serviceconfig"), func() eputils.SchemaStruct { return &pluginapi.Serviceconfig{} })
-	eputils.AddSchemaStruct(__name("serviceconfig"), func() eputils.SchemaStruct { return &pluginapi.Serviceconfig{} })
-
-	Input[__name("ep-params")] = &pluginapi.EpParams{}
-	Input[__name("downloadfiles")] = &pluginapi.Files{}
-	Input[__name("serviceconfig")] = &pluginapi.Serviceconfig{}
-	Output[__name("serviceconfig")] = &pluginapi.Serviceconfig{}
-
-	epplugin.RegisterPlugin(Name, &Input, &Output, PluginMain)
+	Plugin.Register(PluginMain)
 }
@@ -17,6 +17,7 @@ import (
 	papi "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
 	docker "github.com/intel/edge-conductor/pkg/eputils/docker"
+	serviceutil "github.com/intel/edge-conductor/pkg/eputils/service"
 )
 
 func getFileFromList(filelist *papi.Files, url string) (*papi.FilesItems0, error) {
@@ -49,6 +50,7 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 		return err
 	}
 
+	filter := serviceutil.NewServiceFilter(input_ep_params.Cmdline)
 	for _, service := range input_serviceconfig.Components {
 		log.Infof("Injector service %s", service.Name)
 		if service.Type == "repo" || service.Type == "dce" {
@@ -76,6 +78,12 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 		for i, wanted_image := range service.Images {
 			if strings.Index(wanted_image, "/") > 0 {
 				registryUrl := fmt.Sprintf("%s:%s", input_kitcfg.Parameters.GlobalSettings.ProviderIP, input_kitcfg.Parameters.GlobalSettings.RegistryPort)
+				if !filter.Selected(service.Name) {
+					// The image is not pulled in this build, it was pushed
+					// to the registry by a previous build.
+					service.Images[i] = docker.GetImageNewTag(wanted_image, registryUrl)
+					continue
+				}
 				newTag, err := docker.TagImageToLocal(wanted_image, registryUrl)
 				if err != nil {
 					return err
@@ -43,6 +43,18 @@ func TestPluginMain(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "Image_Not_Pulled",
+			input: map[string][]byte{
+				"ep-params":     []byte(`{"cmdline":"exclude=svc","kitconfig":{"Parameters": {"global_settings": {"provider_ip": "test","registry_port": "9000"}, "customconfig": {"registry": {"user": "test", "password": "test123"}}}}}`),
+				"downloadfiles": []byte(`{"files":[{"mirrorurl":"http://localhost","url":"http://127.0.0.1"}]}`),
+				"serviceconfig": []byte(`{"components":[{"name":"svc","url":"http://127.0.0.1","supported-clusters": ["default"],"images":["k8s.gcr.io/ingress-nginx/controller:v1.1.0"]}]}`),
+			},
+			expectedOutput: map[string][]byte{
+				"serviceconfig": []byte(`{"components":[{"name":"svc","url":"http://localhost","supported-clusters": ["default"],"images":["test:9000/k8s.gcr.io/ingress-nginx/controller:v1.1.0"]}]}`),
+			},
+			expectError: false,
+		},
 		{
 			name: "No_Change_for_Repo",
 			input: map[string][]byte{
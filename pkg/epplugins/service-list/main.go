@@ -9,66 +9,236 @@
 package servicelist
 
 import (
+	"encoding/json"
 	"fmt"
+	epplugins "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
-	repoutils "github.com/intel/edge-conductor/pkg/eputils/repoutils"
+	kubeutils "github.com/intel/edge-conductor/pkg/eputils/kubeutils"
+	serviceutil "github.com/intel/edge-conductor/pkg/eputils/service"
 	log "github.com/sirupsen/logrus"
+	"io"
 	"os"
-	"path/filepath"
+	"sigs.k8s.io/yaml"
+	"strings"
 	"text/tabwriter"
 )
 
-func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
-	input_ep_params := input_ep_params(in)
-	input_serviceconfig := input_serviceconfig(in)
+const (
+	epConfigMapName    = "edgeconductor-service"
+	epNamespace        = "edgeconductor"
+	epFieldManagerName = "Edge Conductor"
+)
 
-	runtime_kubeconfig := input_ep_params.Kubeconfig
+const (
+	outputJSON = "json"
+	outputYAML = "yaml"
+
+	// statusIncomplete is the status of a yaml service with objects
+	// missing on the cluster.
+	statusIncomplete = "Incomplete"
+)
+
+// stdout is where the service list is printed.
+var stdout io.Writer = os.Stdout
 
-	tmpFolder := filepath.Join(input_ep_params.Runtimedir, "tmp")
-	defer func() {
-		err := os.RemoveAll(tmpFolder)
+// serviceStatus is the status of a service read from the cluster and the
+// service ConfigMap.
+type serviceStatus struct {
+	Name            string                 `json:"name"`
+	Type            string                 `json:"type"`
+	Namespace       string                 `json:"namespace"`
+	Status          string                 `json:"status"`
+	Revision        string                 `json:"revision,omitempty"`
+	KitVersion      string                 `json:"kitVersion,omitempty"`
+	DeployedVersion string                 `json:"deployedVersion,omitempty"`
+	Pods            *serviceutil.PodHealth `json:"pods,omitempty"`
+}
+
+// getAppliedServices returns the services recorded in the service ConfigMap,
+// none if the ConfigMap is not found.
+func getAppliedServices(kubeconfig string) (map[string]*epplugins.Component, error) {
+	applied := map[string]*epplugins.Component{}
+	configMap, err := kubeutils.NewConfigMap(epNamespace, epConfigMapName, epFieldManagerName, kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := configMap.Get(); err != nil {
+		log.Infoln("ConfigMap", epConfigMapName, "not found on cluster, no service deployed.")
+		return applied, nil
+	}
+	for name, yml := range configMap.GetData() {
+		service := &epplugins.Component{}
+		if err := eputils.LoadSchemaStructFromYaml(service, yml); err != nil {
+			log.Errorln("Failed to load service ConfigMap:", err)
+			return nil, err
+		}
+		applied[name] = service
+	}
+	return applied, nil
+}
+
+func getHelmStatus(status *serviceStatus, checker *serviceutil.ReadinessChecker, kubeconfig string) (*serviceStatus, error) {
+	deployer := serviceutil.NewHelmDeployer(status.Name, status.Namespace, "", "")
+	rel, err := deployer.HelmRelease(kubeconfig)
+	if err != nil {
+		log.Warnf("Failed to get the release of %s: %v", status.Name, err)
+		status.Status = serviceutil.HELM_STATUS_UNKNOWN
+		return status, nil
+	}
+	if rel == nil {
+		status.Status = serviceutil.HELM_STATUS_NOT_DEPLOYED
+		return status, nil
+	}
+	status.Status = strings.Title(rel.Info.Status.String())
+	status.Revision = fmt.Sprintf("%d", rel.Version)
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		status.DeployedVersion = rel.Chart.Metadata.Version
+	}
+	objects, err := serviceutil.ResourcesFromManifest([]byte(rel.Manifest), status.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if status.Pods, err = checker.PodHealth(objects); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func getYamlStatus(status *serviceStatus, applied *epplugins.Component, checker *serviceutil.ReadinessChecker) (*serviceStatus, error) {
+	if applied == nil {
+		status.Status = serviceutil.HELM_STATUS_NOT_DEPLOYED
+		return status, nil
+	}
+	status.Status = serviceutil.HELM_STATUS_DEPLOYED
+	status.Revision = applied.Revision
+	objects, err := serviceutil.GetServiceObjects(applied)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range objects {
+		exists, err := checker.Exists(r)
 		if err != nil {
-			log.Errorln("failed to remove", tmpFolder, err)
+			return nil, err
+		}
+		if !exists {
+			log.Warnf("%s of %s is not found", r, status.Name)
+			status.Status = statusIncomplete
+		}
+	}
+	if status.Pods, err = checker.PodHealth(objects); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// getServiceStatus reads the status of the services from the cluster and
+// the service ConfigMap.
+func getServiceStatus(services []*epplugins.Component, kubeconfig string) ([]*serviceStatus, error) {
+	applied, err := getAppliedServices(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	checker, err := serviceutil.NewReadinessChecker(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	statuses := []*serviceStatus{}
+	for _, service := range services {
+		namespace := service.Namespace
+		if len(namespace) <= 0 {
+			namespace = "default"
+		}
+		status := &serviceStatus{Name: service.Name, Type: service.Type, Namespace: namespace}
+		if service.Type == "helm" {
+			status.KitVersion = service.Chartversion
+			if status, err = getHelmStatus(status, checker, kubeconfig); err != nil {
+				return nil, err
+			}
+		} else if service.Type == "yaml" || service.Type == "kustomize" {
+			if status, err = getYamlStatus(status, applied[service.Name], checker); err != nil {
+				return nil, err
+			}
+		} else {
+			continue
 		}
-	}()
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
 
+func shortDigest(digest string) string {
+	if i := strings.Index(digest, ":"); i >= 0 && len(digest) > i+13 {
+		return digest[:i+13]
+	}
+	return digest
+}
+
+func printTable(statuses []*serviceStatus) error {
 	const padding = 3
 
 	w := tabwriter.NewWriter(
-		os.Stdout,
+		stdout,
 		0, 0, padding, ' ',
 		tabwriter.FilterHTML)
 
 	fmt.Fprintln(w, "")
-	fmt.Fprintln(w, "\tNAME\tTYPE\tSTATUS\tURL\tOVERRIDE\t")
-	fmt.Fprintln(w, "\t====\t====\t======\t===\t========\t")
-	for _, service := range input_serviceconfig.Components {
-		if service.Type == "helm" {
-			fmt.Fprintf(w, "\t%s\t%s\t%s\t%s\t%s\t\n",
-				service.Name,
-				service.Type,
-				getHelmStatus(runtime_kubeconfig, service.Name, service.Namespace),
-				service.URL,
-				service.Chartoverride)
-		} else if service.Type == "yaml" {
-			targetFile := filepath.Join(tmpFolder, service.Name+".yml")
-			if err := repoutils.PullFileFromRepo(targetFile, service.URL); err != nil {
-				log.Errorln("Failed to pull file", service.URL)
-				return err
+	fmt.Fprintln(w, "\tNAME\tTYPE\tSTATUS\tREVISION\tVERSION\tPODS\tRESTARTS\tIMAGES\t")
+	fmt.Fprintln(w, "\t====\t====\t======\t========\t=======\t====\t========\t======\t")
+	for _, s := range statuses {
+		version := s.DeployedVersion
+		if len(s.KitVersion) > 0 && s.KitVersion != s.DeployedVersion {
+			version = fmt.Sprintf("%s (kit %s)", s.DeployedVersion, s.KitVersion)
+		}
+		pods, restarts, images := "", "", []string{}
+		if s.Pods != nil && s.Pods.Total > 0 {
+			pods = fmt.Sprintf("%d/%d", s.Pods.Ready, s.Pods.Total)
+			restarts = fmt.Sprintf("%d", s.Pods.Restarts)
+			for _, image := range s.Pods.Images {
+				images = append(images, fmt.Sprintf("%s@%s", image.Image, shortDigest(image.Digest)))
 			}
-
-			fmt.Fprintf(w, "\t%s\t%s\t%s\t%s\t%s\t\n",
-				service.Name,
-				service.Type,
-				getYamlStatus(runtime_kubeconfig, targetFile),
-				service.URL,
-				"N/A")
 		}
+		fmt.Fprintf(w, "\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
+			s.Name,
+			s.Type,
+			s.Status,
+			s.Revision,
+			version,
+			pods,
+			restarts,
+			strings.Join(images, ","))
 	}
 	fmt.Fprintln(w, "")
-	if err := w.Flush(); err != nil {
+	return w.Flush()
+}
+
+func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
+	input_ep_params := input_ep_params(in)
+	input_serviceconfig := input_serviceconfig(in)
+
+	runtime_kubeconfig := input_ep_params.Kubeconfig
+
+	filter := serviceutil.NewServiceFilter(input_ep_params.Cmdline)
+	statuses, err := getServiceStatus(filter.Filter(input_serviceconfig.Components), runtime_kubeconfig)
+	if err != nil {
 		return err
 	}
 
+	format, _ := eputils.GetCmdlineValue(input_ep_params.Cmdline, "format")
+	switch format {
+	case outputJSON:
+		data, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+	case outputYAML:
+		data, err := yaml.Marshal(statuses)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(stdout, string(data))
+	default:
+		return printTable(statuses)
+	}
 	return nil
 }
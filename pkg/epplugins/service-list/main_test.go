@@ -7,304 +7,246 @@
 
 // Template auto-generated once, maintained by plugin owner.
 
-//nolint: dupl
 package servicelist
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
-	"fmt"
-	repoutils "github.com/intel/edge-conductor/pkg/eputils/repoutils"
-	repomock "github.com/intel/edge-conductor/pkg/eputils/repoutils/mock"
-	"os/exec"
-	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
-	gomock "github.com/golang/mock/gomock"
+	epplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	kubeutils "github.com/intel/edge-conductor/pkg/eputils/kubeutils"
+	serviceutil "github.com/intel/edge-conductor/pkg/eputils/service"
+	fakeserviceutils "github.com/intel/edge-conductor/pkg/eputils/test/fakeserviceutils"
+
+	"github.com/stretchr/testify/require"
 	mpatch "github.com/undefinedlabs/go-mpatch"
-	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
 )
 
-var (
-	kubeerr      = errors.New("kubernetes error")
-	errNotFound  = errors.New("not found")
-	errNoRelease = errors.New("release: not found")
-
-	testerrmsg = "test error"
-	testerr    = errors.New(testerrmsg)
-)
-
-func unpatch(t *testing.T, m *mpatch.Patch) {
-	err := m.Unpatch()
-	if err != nil {
-		t.Fatal(err)
-	}
+const testManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+`
+
+var kubeerr = errors.New("kubernetes error")
+
+func newObject(apiVersion, kind, namespace, name string, fields map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: fields}
+	if obj.Object == nil {
+		obj.Object = map[string]interface{}{}
+	}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
 }
 
-func TestPluginMain(t *testing.T) {
-
-	func_err_PullFileFromRepo := func(ctrl *gomock.Controller) []*mpatch.Patch {
-		// Repo Utils
-		mockRepoWrapper := repomock.NewMockRepoUtilsInterface(ctrl)
-		p1, err := mpatch.PatchMethod(repoutils.PullFileFromRepo, mockRepoWrapper.PullFileFromRepo)
-		if err != nil {
-			t.Fatal(err)
-		}
-		mockRepoWrapper.EXPECT().PullFileFromRepo(gomock.Any(), gomock.Any()).Return(kubeerr)
-		// Status functions
-		p2, err := mpatch.PatchMethod(getHelmStatus, func(string, string, string) string { return "Deployed" })
-		if err != nil {
-			t.Fatal(err)
-		}
-		p3, err := mpatch.PatchMethod(getYamlStatus, func(string, string) string { return "Deployed" })
-		if err != nil {
-			t.Fatal(err)
-		}
-		return []*mpatch.Patch{p1, p2, p3}
-	}
-
-	func_expected_status := func(ctrl *gomock.Controller) []*mpatch.Patch {
-		// Repo Utils
-		mockRepoWrapper := repomock.NewMockRepoUtilsInterface(ctrl)
-		p1, err := mpatch.PatchMethod(repoutils.PullFileFromRepo, mockRepoWrapper.PullFileFromRepo)
-		if err != nil {
-			t.Fatal(err)
-		}
-		mockRepoWrapper.EXPECT().PullFileFromRepo(gomock.Any(), gomock.Any()).Return(nil)
-		// Status functions
-		p2, err := mpatch.PatchMethod(getHelmStatus, func(string, string, string) string { return "Deployed" })
-		if err != nil {
-			t.Fatal(err)
-		}
-		p3, err := mpatch.PatchMethod(getYamlStatus, func(string, string) string { return "Deployed" })
-		if err != nil {
-			t.Fatal(err)
-		}
-		return []*mpatch.Patch{p1, p2, p3}
-	}
-
-	cases := []struct {
-		name           string
-		input          map[string][]byte
-		expectedErr    error
-		funcBeforeTest func(*gomock.Controller) []*mpatch.Patch
-	}{
-		{
-			name: "err_test_pull_file_fail",
-			input: map[string][]byte{
-				"ep-params":     []byte(`{"runtimedir":"","kubeconfig":""}`),
-				"serviceconfig": []byte(`{"components":[{"name":"testhelm","type":"helm"},{"name":"testyaml","type":"yaml"}]}`),
+func newChecker() *serviceutil.ReadinessChecker {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, gvk := range []schema.GroupVersionKind{
+		{Group: "apps", Version: "v1", Kind: "Deployment"},
+		{Group: "", Version: "v1", Kind: "ConfigMap"},
+		{Group: "", Version: "v1", Kind: "Pod"},
+	} {
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+	pod := newObject("v1", "Pod", "default", "web-1", map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{"app": "web"},
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+			"containerStatuses": []interface{}{
+				map[string]interface{}{"image": "nginx:1.21", "imageID": "nginx@sha256:0123456789abcdef", "restartCount": int64(1)},
 			},
-			expectedErr:    kubeerr,
-			funcBeforeTest: func_err_PullFileFromRepo,
 		},
-		{
-			name: "simple_list",
-			input: map[string][]byte{
-				"ep-params":     []byte(`{"runtimedir":"","kubeconfig":""}`),
-				"serviceconfig": []byte(`{"components":[{"name":"testhelm","type":"helm"},{"name":"testyaml","type":"yaml"}]}`),
+	})
+	deployment := newObject("apps/v1", "Deployment", "default", "web", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{"app": "web"},
 			},
-			expectedErr:    nil,
-			funcBeforeTest: func_expected_status,
 		},
-	}
-
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			if tc.funcBeforeTest != nil {
-				plist := tc.funcBeforeTest(ctrl)
-				for _, p := range plist {
-					defer unpatch(t, p)
-				}
-			}
-
-			input := generateInput(tc.input)
-			if input == nil {
-				t.Fatalf("Failed to generateInput %s", tc.input)
-			}
-			testOutput := generateOutput(nil)
-			err := PluginMain(input, &testOutput)
-
-			if tc.expectedErr != nil {
-				if err == nil {
-					t.Error("Expected error but no error found.")
-				} else {
-					if fmt.Sprint(err) == fmt.Sprint(tc.expectedErr) {
-						t.Log("Error expected.")
-					} else {
-						t.Error("Expect:", tc.expectedErr, "; But found:", err)
-					}
-				}
-			} else {
-				if err != nil {
-					t.Error("Unexpected Error:", err)
-				}
-			}
-		})
+	})
+	return &serviceutil.ReadinessChecker{
+		Client: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), pod, deployment,
+			newObject("v1", "ConfigMap", "default", "cm", nil)),
+		Mapper: mapper,
 	}
 }
 
-func TestHelmService(t *testing.T) {
-	// mock functions
-	func_status_run_ok := func(*action.Status, string) (*release.Release, error) {
-		return &release.Release{
-			Info: &release.Info{Status: "Test"},
-		}, nil
-	}
-	func_status_run_err := func(*action.Status, string) (*release.Release, error) {
-		return nil, kubeerr
-	}
-	func_status_run_err_notdeployed := func(*action.Status, string) (*release.Release, error) {
-		return nil, errNoRelease
-	}
-
-	// before test functions
-	func_init_helm_failed := func(ctrl *gomock.Controller, mockfunc func(*action.Status, string) (*release.Release, error)) []*mpatch.Patch {
-		p, err := mpatch.PatchMethod(initHelm, func(string, string) error { return kubeerr })
-		if err != nil {
-			t.Fatal(err)
+func patchAll(t *testing.T, rel *release.Release, relErr error, applied ...*epplugins.Component) *bytes.Buffer {
+	patches := []*mpatch.Patch{}
+	patch := func(p *mpatch.Patch, err error) {
+		require.NoError(t, err)
+		patches = append(patches, p)
+	}
+	out := &bytes.Buffer{}
+	stdout = out
+	t.Cleanup(func() {
+		for _, p := range patches {
+			require.NoError(t, p.Unpatch())
 		}
-		return []*mpatch.Patch{p}
-	}
+	})
 
-	func_status_run_mock := func(ctrl *gomock.Controller, mockfunc func(*action.Status, string) (*release.Release, error)) []*mpatch.Patch {
-		cli_status := action.NewStatus(new(action.Configuration))
-		p, err := mpatch.PatchInstanceMethodByName(reflect.TypeOf(cli_status), "Run", mockfunc)
-		if err != nil {
-			t.Fatal(err)
-		}
-		return []*mpatch.Patch{p}
+	configMap := &kubeutils.ConfigMap{
+		Namespace: epNamespace,
+		Name:      epConfigMapName,
+		Client:    fake.NewSimpleClientset().CoreV1(),
 	}
-
-	cases := []struct {
-		name           string
-		expectResult   string
-		funcBeforeTest func(*gomock.Controller, func(*action.Status, string) (*release.Release, error)) []*mpatch.Patch
-		funcStatusRun  func(*action.Status, string) (*release.Release, error)
-	}{
-		{
-			name:           "err_test_wrong_kubeconfig",
-			expectResult:   "Unknown",
-			funcBeforeTest: func_init_helm_failed,
-			funcStatusRun:  nil,
-		},
-		{
-			name:           "err_test_wrong_release_status",
-			expectResult:   "Unknown",
-			funcBeforeTest: func_status_run_mock,
-			funcStatusRun:  func_status_run_err,
-		},
-		{
-			name:           "test_release_status_notdeployed",
-			expectResult:   "Not Deployed",
-			funcBeforeTest: func_status_run_mock,
-			funcStatusRun:  func_status_run_err_notdeployed,
-		},
-		{
-			name:           "test_release_status",
-			expectResult:   "Test",
-			funcBeforeTest: func_status_run_mock,
-			funcStatusRun:  func_status_run_ok,
-		},
-	}
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			if tc.funcBeforeTest != nil {
-				plist := tc.funcBeforeTest(ctrl, tc.funcStatusRun)
-				for _, p := range plist {
-					defer unpatch(t, p)
-				}
-			}
-
-			status := getHelmStatus("kubeconfig", "name", "ns")
-
-			if status != tc.expectResult {
-				t.Error("Expected", tc.expectResult, "but got", status)
-			} else {
-				t.Log("Done")
-			}
-		})
+	if len(applied) > 0 {
+		require.NoError(t, configMap.New())
+		for _, s := range applied {
+			require.NoError(t, serviceutil.RecordService(configMap, s))
+		}
 	}
+	patch(mpatch.PatchMethod(kubeutils.NewConfigMap, func(string, string, string, string) (kubeutils.ConfigMapWrapper, error) {
+		return configMap, nil
+	}))
+	patch(mpatch.PatchMethod(serviceutil.NewReadinessChecker, func(string) (*serviceutil.ReadinessChecker, error) {
+		return newChecker(), nil
+	}))
+	fakeHelm := &fakeserviceutils.FakeHelmDeployer{}
+	patch(mpatch.PatchMethod(serviceutil.NewHelmDeployer, func(string, string, string, string) serviceutil.HelmDeployerWrapper {
+		return fakeHelm
+	}))
+	patch(mpatch.PatchInstanceMethodByName(reflect.TypeOf(fakeHelm), "HelmRelease", func(*fakeserviceutils.FakeHelmDeployer, string) (*release.Release, error) {
+		return rel, relErr
+	}))
+	return out
 }
 
-func TestInitHelmError(t *testing.T) {
-	gActionConfig = nil
-
-	p1, err := mpatch.PatchMethod(filepath.Abs, func(string) (string, error) { return "", testerr })
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer unpatch(t, p1)
+func run(t *testing.T, cmdline string) error {
+	input := generateInput(map[string][]byte{
+		"ep-params":     []byte(`{"runtimedir":"","kubeconfig":"","cmdline":"` + cmdline + `"}`),
+		"serviceconfig": []byte(`{"components":[{"name":"helm","type":"helm","chartversion":"1.1"},{"name":"yaml","type":"yaml"},{"name":"repo","type":"repo"}]}`),
+	})
+	require.NotNil(t, input)
+	output := generateOutput(nil)
+	return PluginMain(input, &output)
+}
 
-	if err := initHelm("testconfig", "testspace"); errors.Is(err, testerr) {
-		t.Log("Error Expected")
-	} else {
-		t.Error("Expect", testerr, "but found", err)
-	}
+func yamlService(t *testing.T, objects ...*serviceutil.ReadyResource) *epplugins.Component {
+	service := &epplugins.Component{Name: "yaml", Type: "yaml"}
+	require.NoError(t, serviceutil.SetServiceObjects(service, objects))
+	return service
 }
 
-func TestYamlService(t *testing.T) {
-	// mock functions
-	func_cmdrun_ok := func(*exec.Cmd) error {
-		return nil
-	}
-	func_cmdrun_fail := func(*exec.Cmd) error {
-		return kubeerr
+func TestPluginMain(t *testing.T) {
+	rel := &release.Release{
+		Version:  3,
+		Info:     &release.Info{Status: release.StatusDeployed},
+		Chart:    &chart.Chart{Metadata: &chart.Metadata{Version: "1.0"}},
+		Manifest: testManifest,
 	}
-	func_cmdrun_notfound := func(c *exec.Cmd) error {
-		fmt.Fprintf(c.Stdout, "not found")
-		return errNotFound
+	web := &serviceutil.ReadyResource{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Namespace: "default"}
+	pods := &serviceutil.PodHealth{
+		Ready: 1, Total: 1, Restarts: 1,
+		Images: []serviceutil.ImageStatus{{Image: "nginx:1.21", Digest: "sha256:0123456789abcdef"}},
 	}
 
 	cases := []struct {
-		name           string
-		expectResult   string
-		funcMockCMDRun func(*exec.Cmd) error
+		name    string
+		rel     *release.Release
+		relErr  error
+		applied []*epplugins.Component
+		want    []*serviceStatus
 	}{
 		{
-			name:           "err_test_cmdrun_failed",
-			expectResult:   "Unknown",
-			funcMockCMDRun: func_cmdrun_fail,
+			name:    "deployed",
+			rel:     rel,
+			applied: []*epplugins.Component{yamlService(t, web, &serviceutil.ReadyResource{APIVersion: "v1", Kind: "ConfigMap", Name: "cm", Namespace: "default"})},
+			want: []*serviceStatus{
+				{Name: "helm", Type: "helm", Namespace: "default", Status: "Deployed", Revision: "3", KitVersion: "1.1", DeployedVersion: "1.0", Pods: pods},
+				{Name: "yaml", Type: "yaml", Namespace: "default", Status: "Deployed", Revision: "1", Pods: pods},
+			},
 		},
 		{
-			name:           "test_cmdrun_ok",
-			expectResult:   "Deployed",
-			funcMockCMDRun: func_cmdrun_ok,
+			name:    "incomplete",
+			relErr:  kubeerr,
+			applied: []*epplugins.Component{yamlService(t, &serviceutil.ReadyResource{APIVersion: "v1", Kind: "ConfigMap", Name: "missing", Namespace: "default"})},
+			want: []*serviceStatus{
+				{Name: "helm", Type: "helm", Namespace: "default", Status: "Unknown", KitVersion: "1.1"},
+				{Name: "yaml", Type: "yaml", Namespace: "default", Status: "Incomplete", Revision: "1", Pods: &serviceutil.PodHealth{}},
+			},
 		},
 		{
-			name:           "test_cmdrun_notfound",
-			expectResult:   "Not Deployed",
-			funcMockCMDRun: func_cmdrun_notfound,
+			name: "not_deployed",
+			want: []*serviceStatus{
+				{Name: "helm", Type: "helm", Namespace: "default", Status: "Not Deployed", KitVersion: "1.1"},
+				{Name: "yaml", Type: "yaml", Namespace: "default", Status: "Not Deployed"},
+			},
 		},
 	}
+
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
+			out := patchAll(t, tc.rel, tc.relErr, tc.applied...)
+			require.NoError(t, run(t, "format=json"))
+			got := []*serviceStatus{}
+			require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+			require.Equal(t, tc.want, got)
+
+			out.Reset()
+			require.NoError(t, run(t, "format=yaml"))
+			got = []*serviceStatus{}
+			require.NoError(t, yaml.Unmarshal(out.Bytes(), &got))
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
 
-			if tc.funcMockCMDRun != nil {
-				cmd := exec.Command("ls")
-				p, err := mpatch.PatchInstanceMethodByName(reflect.TypeOf(cmd), "Run", tc.funcMockCMDRun)
-				if err != nil {
-					t.Fatal(err)
-				}
-				defer unpatch(t, p)
-			}
+func TestPluginMainTable(t *testing.T) {
+	out := patchAll(t, &release.Release{
+		Version:  2,
+		Info:     &release.Info{Status: release.StatusFailed},
+		Chart:    &chart.Chart{Metadata: &chart.Metadata{Version: "1.0"}},
+		Manifest: testManifest,
+	}, nil)
+
+	require.NoError(t, run(t, "exclude=yaml"))
+	lines := strings.Split(out.String(), "\n")
+	require.Len(t, lines, 6)
+	require.Equal(t, []string{"helm", "helm", "Failed", "2", "1.0", "(kit", "1.1)", "1/1", "1", "nginx:1.21@sha256:0123456789ab"}, strings.Fields(lines[3]))
+}
 
-			status := getYamlStatus("kubeconfig", "yamlfile")
+func TestPluginMainError(t *testing.T) {
+	p1, err := mpatch.PatchMethod(kubeutils.NewConfigMap, func(string, string, string, string) (kubeutils.ConfigMapWrapper, error) {
+		return nil, kubeerr
+	})
+	require.NoError(t, err)
+	require.Equal(t, kubeerr, run(t, ""))
+	require.NoError(t, p1.Unpatch())
 
-			if status != tc.expectResult {
-				t.Error("Expected", tc.expectResult, "but got", status)
-			} else {
-				t.Log("Done")
-			}
-		})
-	}
+	p2, err := mpatch.PatchMethod(serviceutil.NewReadinessChecker, func(string) (*serviceutil.ReadinessChecker, error) {
+		return nil, kubeerr
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, p2.Unpatch()) }()
+	p3, err := mpatch.PatchMethod(kubeutils.NewConfigMap, func(string, string, string, string) (kubeutils.ConfigMapWrapper, error) {
+		return &kubeutils.ConfigMap{Namespace: epNamespace, Name: epConfigMapName, Client: fake.NewSimpleClientset().CoreV1()}, nil
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, p3.Unpatch()) }()
+	require.Equal(t, kubeerr, run(t, ""))
 }
@@ -12,13 +12,18 @@ package serviceparser
 import (
 	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
-	epplugin "github.com/intel/edge-conductor/pkg/plugin"
+	pluginsdk "github.com/intel/edge-conductor/pkg/pluginsdk"
 )
 
 var (
 	Name   = "service-parser"
-	Input  = eputils.NewSchemaMapData()
-	Output = eputils.NewSchemaMapData()
+	Plugin = pluginsdk.New(Name).
+		WithInput("ep-params", &pluginapi.EpParams{}).
+		WithOutput("serviceconfig", &pluginapi.Serviceconfig{}).
+		WithOutput("downloadfiles", &pluginapi.Files{}).
+		WithOutput("docker-images", &pluginapi.Images{})
+	Input  = Plugin.Input
+	Output = Plugin.Output
 )
 
 //nolint:unparam,deadcode,unused
@@ -47,15 +52,5 @@ func output_docker_images(outp *eputils.SchemaMapData) *pluginapi.Images {
 }
 
 func init() {
-	eputils.AddSchemaStruct(__name("ep-params"), func() eputils.SchemaStruct { return &pluginapi.EpParams{} })
-	eputils.AddSchemaStruct(__name("serviceconfig"), func() eputils.SchemaStruct { return &pluginapi.Serviceconfig{} })
-	eputils.AddSchemaStruct(__name("downloadfiles"), func() eputils.SchemaStruct { return &pluginapi.Files{} })
-	eputils.AddSchemaStruct(__name("docker-images"), func() eputils.SchemaStruct { return &pluginapi.Images{} })
-
-	Input[__name("ep-params")] = &pluginapi.EpParams{}
-	Output[__name("serviceconfig")] = &pluginapi.Serviceconfig{}
-	Output[__name("downloadfiles")] = &pluginapi.Files{}
-	Output[__name("docker-images")] = &pluginapi.Images{}
-
-	epplugin.RegisterPlugin(Name, &Input, &Output, PluginMain)
+	Plugin.Register(PluginMain)
 }
@@ -9,26 +9,30 @@
 package serviceparser
 
 import (
+	"fmt"
 	"path"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
 	"helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/registry"
 	"helm.sh/helm/v3/pkg/repo"
 	"sigs.k8s.io/yaml"
 
 	papi "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	serviceutil "github.com/intel/edge-conductor/pkg/eputils/service"
 )
 
-func addFile(filelist *papi.Files, url, hash, hashtype, subfolder string) {
+func addFile(filelist *papi.Files, url, hash, hashtype, subfolder string, helmchart bool) {
 	url_origin := strings.Replace(url, path.Base(url), "", 1)
 
 	file := papi.FilesItems0{
-		URL:      url,
-		Hash:     hash,
-		Hashtype: hashtype,
+		URL:       url,
+		Hash:      hash,
+		Hashtype:  hashtype,
+		Helmchart: helmchart,
 		Urlreplacement: &papi.FilesItems0Urlreplacement{
 			New:    subfolder,
 			Origin: url_origin,
@@ -46,6 +50,9 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 
 	origin_configfiles := input_kitcfg.Components.Manifests
 	selectorList := input_kitcfg.Components.Selector
+	// The filtered out services are kept in the service config, only their
+	// images are not pulled again.
+	filter := serviceutil.NewServiceFilter(input_ep_params.Cmdline)
 
 	all_services := papi.Serviceconfig{}
 	for _, origin_configfile := range origin_configfiles {
@@ -67,6 +74,10 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 			if isSupported(input_kitcfg.Cluster.Provider, service.SupportedClusters) {
 				output_serviceconfig.Components = append(
 					output_serviceconfig.Components, service)
+				if !filter.Selected(service.Name) {
+					log.Infof("Service %s is skipped.", service.Name)
+					continue
+				}
 				for _, wanted_image := range service.Images {
 					log.Infof("Docker image %s will be pulled for %s.", wanted_image, service.Name)
 					output_docker_images.Images = append(output_docker_images.Images,
@@ -86,7 +97,14 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 
 		subfolder := path.Join(service.Type, service.Name)
 		if service.Type == "helm" && service.URL == "" {
-			if service.Helmrepo != "" && service.Chartname != "" {
+			if strings.HasPrefix(service.Helmrepo, registry.OCIScheme+"://") && service.Chartname != "" {
+				// The charts of an OCI registry are pulled by version.
+				if service.Chartversion == "" {
+					log.Errorf("Chart version of %s is empty", service.Name)
+					return eputils.GetError("errHelmChartVersion")
+				}
+				service.URL = fmt.Sprintf("%s/%s:%s", strings.TrimSuffix(service.Helmrepo, "/"), service.Chartname, service.Chartversion)
+			} else if service.Helmrepo != "" && service.Chartname != "" {
 				ref, err := repo.FindChartInRepoURL(
 					service.Helmrepo, service.Chartname,
 					service.Chartversion, "", "", "",
@@ -104,9 +122,10 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 				return eputils.GetError("errHelmEmpty")
 			}
 		}
-		addFile(output_downloadfiles, service.URL, service.Hash, service.Hashtype, subfolder)
+		// Helm charts are pushed to the registry as OCI Helm charts.
+		addFile(output_downloadfiles, service.URL, service.Hash, service.Hashtype, subfolder, service.Type == "helm")
 		if len(service.Chartoverride) > 0 {
-			addFile(output_downloadfiles, service.Chartoverride, "", "", subfolder)
+			addFile(output_downloadfiles, service.Chartoverride, "", "", subfolder, false)
 		}
 	}
 
@@ -26,6 +26,7 @@ import (
 
 var emptyChartNameInput = "Components:\n  - name: testhelmrepo\n    helmrepo: https://prometheus-community.github.io/helm-charts\n    chartversion: 14.1.3\n    chartoverride: file://testoverrideurl\n    supported-clusters:\n      - default\n    type: helm\n"
 var emptyRepoInput = "Components:\n  - name: testhelmrepo\n    chartname: prometheus\n    chartversion: 14.1.3\n    chartoverride: file://testoverrideurl\n    supported-clusters:\n      - default\n    type: helm\n"
+var emptyChartVersionInput = "Components:\n  - name: testhelmoci\n    helmrepo: oci://ghcr.io/org/charts\n    chartname: mychart\n    supported-clusters:\n      - default\n    type: helm\n"
 
 func unpatch(t *testing.T, m *mpatch.Patch) {
 	err := m.Unpatch()
@@ -80,7 +81,7 @@ func TestPluginMain(t *testing.T) {
 			expectedOutput: map[string][]byte{
 				"serviceconfig": []byte(`{
 				"Components":[{"images":null,"name":"testhelm","chartoverride":"file://testfakeoverride","resources":null,"supported-clusters":["default"],"type":"helm","url":"file://testhelmurl"}]}`),
-				"downloadfiles": []byte(`{"files":[{"url":"file://testhelmurl","urlreplacement":{"new":"helm/testhelm","origin":"file://"}},
+				"downloadfiles": []byte(`{"files":[{"helmchart":true,"url":"file://testhelmurl","urlreplacement":{"new":"helm/testhelm","origin":"file://"}},
 					{"url":"file://testfakeoverride","urlreplacement":{"new":"helm/testhelm","origin":"file://"}}]}`),
 				"docker-images": []byte(`{"images":null}`),
 			},
@@ -101,7 +102,7 @@ func TestPluginMain(t *testing.T) {
 				"serviceconfig": []byte(`{
 				"Components":[{"chartname":"prometheus","chartversion":"14.1.3","helmrepo":"https://prometheus-community.github.io/helm-charts","images":null,"name":"testhelmrepo","chartoverride":"file://testoverrideurl","resources":null,"supported-clusters":["default"],"type":"helm","url":"https://github.com/prometheus-community/helm-charts/releases/download/prometheus-14.1.3/prometheus-14.1.3.tgz"}]}`),
 				"downloadfiles": []byte(`{"files":[
-					{"url":"https://github.com/prometheus-community/helm-charts/releases/download/prometheus-14.1.3/prometheus-14.1.3.tgz","urlreplacement":{"new":"helm/testhelmrepo","origin":"https://github.com/prometheus-community/helm-charts/releases/download/prometheus-14.1.3/"}},
+					{"helmchart":true,"url":"https://github.com/prometheus-community/helm-charts/releases/download/prometheus-14.1.3/prometheus-14.1.3.tgz","urlreplacement":{"new":"helm/testhelmrepo","origin":"https://github.com/prometheus-community/helm-charts/releases/download/prometheus-14.1.3/"}},
 					{"url":"file://testoverrideurl","urlreplacement":{"new":"helm/testhelmrepo","origin":"file://"}}]}`),
 				"docker-images": []byte(`{"images":null}`),
 			},
@@ -109,6 +110,26 @@ func TestPluginMain(t *testing.T) {
 			expectErrorMsg: "",
 			funcBeforeTest: success_find_helm_repofunc,
 		},
+		{
+			name: "success_helm_oci",
+			input: map[string][]byte{
+				"ep-params": []byte(`{"kitconfig":{
+					"Cluster":{"Provider":"kind"},
+					"Components":{"selector":[{"name": "testhelmoci"}], "manifests":["testdata/fake.yml"]}},
+					"Runtimedir": "testdata"
+				}`),
+			},
+			expectedOutput: map[string][]byte{
+				"serviceconfig": []byte(`{
+				"Components":[{"chartname":"mychart","chartversion":"1.0.0","helmrepo":"oci://ghcr.io/org/charts","images":null,"name":"testhelmoci","resources":null,"supported-clusters":["default"],"type":"helm","url":"oci://ghcr.io/org/charts/mychart:1.0.0"}]}`),
+				"downloadfiles": []byte(`{"files":[
+					{"helmchart":true,"url":"oci://ghcr.io/org/charts/mychart:1.0.0","urlreplacement":{"new":"helm/testhelmoci","origin":"oci://ghcr.io/org/charts/"}}]}`),
+				"docker-images": []byte(`{"images":null}`),
+			},
+			expectError:    false,
+			expectErrorMsg: "",
+			funcBeforeTest: nil,
+		},
 		{
 			name: "success_repo",
 			input: map[string][]byte{
@@ -188,6 +209,26 @@ func TestPluginMain(t *testing.T) {
 			expectErrorMsg: "",
 			funcBeforeTest: success_find_helm_repofunc,
 		},
+		{
+			name: "success_withimage_excluded",
+			input: map[string][]byte{
+				"ep-params": []byte(`{"kitconfig":{
+					"Cluster":{"Provider":"kind"},
+					"Components":{"selector":[{"name": "testimage"}], "manifests":["testdata/fake.yml"]}},
+					"Runtimedir": "testdata",
+					"cmdline": "exclude=testimage"
+				}`),
+			},
+			expectedOutput: map[string][]byte{
+				"serviceconfig": []byte(`{
+				"Components":[{"images":["k8s.gcr.io/ingress-nginx/controller:v1.1.0","k8s.gcr.io/ingress-nginx/kube-webhook-certgen:v1.1.1"],"name":"testimage","resources":null,"supported-clusters":["default"],"type":"yaml","url":"file://testyamlurl"}]}`),
+				"downloadfiles": []byte(`{"files":[{"url":"file://testyamlurl","urlreplacement":{"new":"yaml/testimage","origin":"file://"}}]}`),
+				"docker-images": []byte(`{"images":null}`),
+			},
+			expectError:    false,
+			expectErrorMsg: "",
+			funcBeforeTest: success_find_helm_repofunc,
+		},
 		{
 			name: "err_empty_HelmChart_Name",
 			input: map[string][]byte{
@@ -198,6 +239,16 @@ func TestPluginMain(t *testing.T) {
 			expectErrorMsg: eputils.GetError("errHelmEmpty").Error(),
 			funcBeforeTest: nil,
 		},
+		{
+			name: "err_empty_HelmChart_Version",
+			input: map[string][]byte{
+				"ep-params": []byte(`{"kitconfig":{"Cluster":{"Provider":"kind"},"Components":{"selector":[{"name": "testhelmoci"}],"manifests":["test-chart-version.yml"]}}}`),
+			},
+			expectedOutput: nil,
+			expectError:    true,
+			expectErrorMsg: eputils.GetError("errHelmChartVersion").Error(),
+			funcBeforeTest: nil,
+		},
 		{
 			name: "err_empty_HelmRepo",
 			input: map[string][]byte{
@@ -215,6 +266,11 @@ func TestPluginMain(t *testing.T) {
 
 	defer os.RemoveAll("test-chart-name.yml")
 
+	errVersion := eputils.WriteStringToFile(emptyChartVersionInput, "test-chart-version.yml")
+	require.NoError(t, errVersion, "Write String To File Error:")
+
+	defer os.RemoveAll("test-chart-version.yml")
+
 	errHelm := eputils.WriteStringToFile(emptyRepoInput, "test-helm-repo.yml")
 	require.NoError(t, errHelm, "Write String To File Error:")
 
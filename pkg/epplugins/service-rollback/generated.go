@@ -0,0 +1,38 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+// Auto generated, do not modify.
+
+package servicerollback
+
+import (
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	pluginsdk "github.com/intel/edge-conductor/pkg/pluginsdk"
+)
+
+var (
+	Name   = "service-rollback"
+	Plugin = pluginsdk.New(Name).
+		WithInput("ep-params", &pluginapi.EpParams{})
+	Input  = Plugin.Input
+	Output = Plugin.Output
+)
+
+//nolint:unparam,deadcode,unused
+func __name(n string) string {
+	return Name + "." + n
+}
+
+//nolint:deadcode,unused
+func input_ep_params(in eputils.SchemaMapData) *pluginapi.EpParams {
+	return in[__name("ep-params")].(*pluginapi.EpParams)
+}
+
+func init() {
+	Plugin.Register(PluginMain)
+}
@@ -0,0 +1,184 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+// Template auto-generated once, maintained by plugin owner.
+
+package servicerollback
+
+import (
+	"fmt"
+	epplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	kubeutils "github.com/intel/edge-conductor/pkg/eputils/kubeutils"
+	repoutils "github.com/intel/edge-conductor/pkg/eputils/repoutils"
+	serviceutil "github.com/intel/edge-conductor/pkg/eputils/service"
+	log "github.com/sirupsen/logrus"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+const (
+	epConfigMapName    = "edgeconductor-service"
+	epNamespace        = "edgeconductor"
+	epFieldManagerName = "Edge Conductor"
+)
+
+// findRevision returns the revision of the service history to roll back to,
+// the one before the latest when revision is empty.
+func findRevision(history []*epplugins.Component, revision string) (*epplugins.Component, error) {
+	if len(revision) == 0 {
+		if len(history) < 2 {
+			return nil, eputils.GetError("errServiceRollback")
+		}
+		return history[len(history)-2], nil
+	}
+	for _, service := range history {
+		if service.Revision == revision {
+			return service, nil
+		}
+	}
+	return nil, eputils.GetError("errServiceRollback")
+}
+
+func rollbackHelm(service *epplugins.Component, kubeconfig string) error {
+	namespace := service.Namespace
+	if len(namespace) <= 0 {
+		namespace = "default"
+	}
+	revision, err := strconv.Atoi(service.Revision)
+	if err != nil {
+		log.Errorf("Wrong revision %s of helm service %s", service.Revision, service.Name)
+		return eputils.GetError("errServiceRollback")
+	}
+	opts := []serviceutil.InstallOpt{}
+	if service.Wait != nil && service.Wait.Timeout != 0 {
+		opts = append(opts,
+			serviceutil.WithWaitAndTimeout(true, int(service.Wait.Timeout)),
+			serviceutil.WithWaitConditions(service.Wait.Conditions))
+	}
+	deployer := serviceutil.NewHelmDeployer(service.Name, namespace, "", "")
+	if err := deployer.HelmRollback(kubeconfig, revision, opts...); err != nil {
+		log.Errorln(err)
+		return err
+	}
+	status, rev := deployer.HelmStatus(kubeconfig)
+	if rev == 0 {
+		log.Errorf("Helm service %s with wrong status: %s", service.Name, status)
+		return eputils.GetError("errServiceStatus")
+	}
+	// The rollback is a new revision of the release.
+	service.Revision = fmt.Sprintf("%d", rev)
+	return nil
+}
+
+func rollbackYaml(service *epplugins.Component, kubeconfig, tmpDir string) error {
+	namespace := service.Namespace
+	if len(namespace) <= 0 {
+		namespace = "default"
+	}
+	targetFile := filepath.Join(tmpDir, service.Name+".yml")
+	pulledFile := targetFile
+	if service.Type == "kustomize" {
+		pulledFile = filepath.Join(tmpDir, service.Name+".tar.gz")
+	}
+	if err := repoutils.PullFileFromRepo(pulledFile, service.URL); err != nil {
+		log.Errorln("Failed to pull file", service.URL)
+		return err
+	}
+	if len(service.Hash) > 0 && service.Hashtype == "sha256" {
+		if err := eputils.CheckFileSHA256(pulledFile, service.Hash); err != nil {
+			log.Errorln("The file of", service.URL, "changed since it was applied")
+			return err
+		}
+	}
+	if service.Type == "kustomize" {
+		if err := serviceutil.KustomizeBuild(pulledFile, service.Overlay, targetFile); err != nil {
+			return err
+		}
+	}
+	if err := eputils.FileTemplateConvert(targetFile, targetFile); err != nil {
+		log.Errorln("File Template Convert Failed:", err)
+	}
+	wait := &serviceutil.YamlWait{Timeout: 0}
+	if service.Wait != nil && service.Wait.Timeout != 0 {
+		wait.Timeout = service.Wait.Timeout
+		wait.Conditions = service.Wait.Conditions
+	}
+	deployer := serviceutil.NewYamlDeployer(service.Name, namespace, targetFile, wait)
+	if err := deployer.YamlInstall(kubeconfig); err != nil {
+		log.Errorln(err)
+		return err
+	}
+	return nil
+}
+
+func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
+	input_ep_params := input_ep_params(in)
+
+	runtime_kubeconfig := input_ep_params.Kubeconfig
+
+	name, _ := eputils.GetCmdlineValue(input_ep_params.Cmdline, "service")
+	revision, _ := eputils.GetCmdlineValue(input_ep_params.Cmdline, "revision")
+	if len(name) == 0 {
+		log.Errorln("No service to roll back")
+		return eputils.GetError("errServiceRollback")
+	}
+
+	serviceConfigMap, err := kubeutils.NewConfigMap(epNamespace, epConfigMapName, epFieldManagerName, runtime_kubeconfig)
+	if err != nil {
+		return err
+	}
+	if err := serviceConfigMap.Get(); err != nil {
+		log.Errorln("ConfigMap", epConfigMapName, "not found on cluster, no service deployed.")
+		return err
+	}
+	history, err := serviceutil.GetServiceHistory(serviceConfigMap, name)
+	if err != nil {
+		return err
+	}
+	if len(history) == 0 {
+		log.Errorf("Service %s is not deployed", name)
+		return eputils.GetError("errServiceRollback")
+	}
+	target, err := findRevision(history, revision)
+	if err != nil {
+		log.Errorf("Revision %q of service %s is not found in the service history", revision, name)
+		return err
+	}
+	service := *target
+	log.Infof("Service %s will be rolled back to revision %s.", name, service.Revision)
+
+	switch service.Type {
+	case "helm":
+		if err := rollbackHelm(&service, runtime_kubeconfig); err != nil {
+			return err
+		}
+	case "yaml", "kustomize":
+		tmpDir := filepath.Join(input_ep_params.Runtimedir, "tmp")
+		defer func() {
+			err := os.RemoveAll(tmpDir)
+			if err != nil {
+				log.Errorln("failed to remove", tmpDir, err)
+			}
+		}()
+		if err := os.MkdirAll(tmpDir, 0700); err != nil {
+			return err
+		}
+		if err := rollbackYaml(&service, runtime_kubeconfig, tmpDir); err != nil {
+			return err
+		}
+	default:
+		log.Errorf("No rollback supported for %s %s", service.Type, name)
+		return eputils.GetError("errServiceRollback")
+	}
+
+	if err := serviceutil.RecordService(serviceConfigMap, &service); err != nil {
+		return err
+	}
+	log.Infof("Service %s rolled back to revision %s, as revision %s.", name, target.Revision, service.Revision)
+	return nil
+}
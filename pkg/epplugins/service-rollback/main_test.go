@@ -0,0 +1,146 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+// Template auto-generated once, maintained by plugin owner.
+
+package servicerollback
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	epplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	kubeutils "github.com/intel/edge-conductor/pkg/eputils/kubeutils"
+	repoutils "github.com/intel/edge-conductor/pkg/eputils/repoutils"
+	serviceutil "github.com/intel/edge-conductor/pkg/eputils/service"
+	fakeserviceutils "github.com/intel/edge-conductor/pkg/eputils/test/fakeserviceutils"
+
+	"github.com/stretchr/testify/require"
+	mpatch "github.com/undefinedlabs/go-mpatch"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// release is the state of the fake Helm release.
+type release struct {
+	revision   int
+	rolledBack int
+}
+
+func patchAll(t *testing.T, configMap *kubeutils.ConfigMap, rel *release) *[]string {
+	patches := []*mpatch.Patch{}
+	patch := func(p *mpatch.Patch, err error) {
+		require.NoError(t, err)
+		patches = append(patches, p)
+	}
+	t.Cleanup(func() {
+		for _, p := range patches {
+			require.NoError(t, p.Unpatch())
+		}
+	})
+
+	patch(mpatch.PatchMethod(kubeutils.NewConfigMap, func(string, string, string, string) (kubeutils.ConfigMapWrapper, error) {
+		return configMap, nil
+	}))
+	patch(mpatch.PatchMethod(repoutils.PullFileFromRepo, func(target, url string) error {
+		return ioutil.WriteFile(target, []byte(url), 0600)
+	}))
+
+	fakeHelm := &fakeserviceutils.FakeHelmDeployer{}
+	patch(mpatch.PatchMethod(serviceutil.NewHelmDeployer, func(string, string, string, string) serviceutil.HelmDeployerWrapper {
+		return fakeHelm
+	}))
+	patch(mpatch.PatchInstanceMethodByName(reflect.TypeOf(fakeHelm), "HelmRollback", func(_ *fakeserviceutils.FakeHelmDeployer, _ string, revision int, _ ...serviceutil.InstallOpt) error {
+		rel.rolledBack = revision
+		rel.revision++
+		return nil
+	}))
+	patch(mpatch.PatchInstanceMethodByName(reflect.TypeOf(fakeHelm), "HelmStatus", func(*fakeserviceutils.FakeHelmDeployer, string) (string, int) {
+		return serviceutil.HELM_STATUS_DEPLOYED, rel.revision
+	}))
+
+	installed := []string{}
+	fakeYaml := &fakeserviceutils.FakeYamlDeployer{}
+	patch(mpatch.PatchMethod(serviceutil.NewYamlDeployer, func(_, _, file string, _ ...interface{}) serviceutil.YamlDeployerWrapper {
+		data, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		installed = append(installed, string(data))
+		return fakeYaml
+	}))
+	return &installed
+}
+
+func newConfigMap(t *testing.T, services ...*epplugins.Component) *kubeutils.ConfigMap {
+	configMap := &kubeutils.ConfigMap{
+		Namespace: epNamespace,
+		Name:      epConfigMapName,
+		Client:    fake.NewSimpleClientset().CoreV1(),
+	}
+	require.NoError(t, configMap.New())
+	for _, s := range services {
+		require.NoError(t, serviceutil.RecordService(configMap, s))
+	}
+	return configMap
+}
+
+func run(t *testing.T, cmdline ...string) error {
+	cmd := ""
+	for _, c := range cmdline {
+		cmd = eputils.AddCmdline(cmd, c)
+	}
+	in := eputils.SchemaMapData{
+		__name("ep-params"): &epplugins.EpParams{Kubeconfig: "kubeconfig", Runtimedir: t.TempDir(), Cmdline: cmd},
+	}
+	return PluginMain(in, nil)
+}
+
+func latest(t *testing.T, configMap *kubeutils.ConfigMap, name string) *epplugins.Component {
+	service := &epplugins.Component{}
+	require.NoError(t, eputils.LoadSchemaStructFromYaml(service, configMap.GetData()[name]))
+	return service
+}
+
+func TestPluginMainHelm(t *testing.T) {
+	configMap := newConfigMap(t,
+		&epplugins.Component{Name: "helm", Type: "helm", Chartversion: "1.0", Revision: "1"},
+		&epplugins.Component{Name: "helm", Type: "helm", Chartversion: "1.1", Revision: "2"},
+		&epplugins.Component{Name: "helm", Type: "helm", Chartversion: "1.2", Revision: "3"},
+	)
+	rel := &release{revision: 3}
+	patchAll(t, configMap, rel)
+
+	require.NoError(t, run(t, "service=helm"))
+	require.Equal(t, 2, rel.rolledBack)
+	require.Equal(t, "1.1", latest(t, configMap, "helm").Chartversion)
+	require.Equal(t, "4", latest(t, configMap, "helm").Revision)
+
+	require.NoError(t, run(t, "service=helm", "revision=1"))
+	require.Equal(t, 1, rel.rolledBack)
+	require.Equal(t, "1.0", latest(t, configMap, "helm").Chartversion)
+	require.Equal(t, "5", latest(t, configMap, "helm").Revision)
+
+	require.Equal(t, eputils.GetError("errServiceRollback"), run(t, "service=helm", "revision=9"))
+	require.Equal(t, eputils.GetError("errServiceRollback"), run(t, "service=missing"))
+	require.Equal(t, eputils.GetError("errServiceRollback"), run(t))
+}
+
+func TestPluginMainYaml(t *testing.T) {
+	configMap := newConfigMap(t,
+		&epplugins.Component{Name: "yaml", Type: "yaml", URL: "file://v1"},
+		&epplugins.Component{Name: "yaml", Type: "yaml", URL: "file://v2"},
+		&epplugins.Component{Name: "single", Type: "yaml", URL: "file://v1"},
+	)
+	installed := patchAll(t, configMap, &release{})
+
+	require.NoError(t, run(t, "service=yaml"))
+	require.Equal(t, []string{"file://v1"}, *installed)
+	require.Equal(t, "file://v1", latest(t, configMap, "yaml").URL)
+	require.Equal(t, "3", latest(t, configMap, "yaml").Revision)
+
+	require.Equal(t, eputils.GetError("errServiceRollback"), run(t, "service=single"), "no previous revision")
+}
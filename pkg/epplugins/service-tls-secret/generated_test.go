@@ -0,0 +1,24 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+// Auto generated, do not modify.
+
+package servicetlssecret
+
+import (
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+)
+
+//nolint:deadcode,unused,unparam
+func generateInput(data map[string][]byte) eputils.SchemaMapData {
+	return Plugin.NewInput(data)
+}
+
+//nolint:unparam,deadcode,unused
+func generateOutput(data map[string][]byte) eputils.SchemaMapData {
+	return Plugin.NewOutput(data)
+}
@@ -0,0 +1,76 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+// Template auto-generated once, maintained by plugin owner.
+
+package servicetlssecret
+
+import (
+	"strings"
+
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	serviceutil "github.com/intel/edge-conductor/pkg/eputils/service"
+	log "github.com/sirupsen/logrus"
+)
+
+// rotatedCerts returns the names of the rotated cert bundles from the
+// "cert=<name,...>" option of the command line, none if all are rotated.
+func rotatedCerts(cmdline string) map[string]bool {
+	rotated := map[string]bool{}
+	if names, ok := eputils.GetCmdlineValue(cmdline, "cert"); ok {
+		for _, name := range strings.Split(names, ",") {
+			if name = strings.TrimSpace(name); len(name) > 0 {
+				rotated[name] = true
+			}
+		}
+	}
+	return rotated
+}
+
+func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
+	input_ep_params := input_ep_params(in)
+	input_serviceconfig := input_serviceconfig(in)
+
+	runtime_kubeconfig := input_ep_params.Kubeconfig
+	rotated := rotatedCerts(input_ep_params.Cmdline)
+
+	for _, service := range input_serviceconfig.Components {
+		// Only helm services get the secrets of the service-tls extension.
+		if service.Type != "helm" {
+			continue
+		}
+		found := false
+		for _, name := range serviceutil.SvcTLSCertNames(input_ep_params.Extensions, service.Name) {
+			if len(rotated) == 0 || rotated[name] {
+				found = true
+			}
+		}
+		if !found {
+			continue
+		}
+
+		namespace := service.Namespace
+		if len(namespace) <= 0 {
+			namespace = "default"
+		}
+		deployer := serviceutil.NewHelmDeployer(service.Name, namespace, "", "")
+		rel, err := deployer.HelmRelease(runtime_kubeconfig)
+		if err != nil {
+			log.Errorf("Failed to get the release of %s: %v", service.Name, err)
+			return err
+		}
+		if rel == nil {
+			log.Infof("Service %s is not deployed, skip its TLS secrets.", service.Name)
+			continue
+		}
+		log.Infof("Updating the TLS secrets of %s.", service.Name)
+		err = serviceutil.GenSvcSecretFromTLSExtension(input_ep_params.Extensions, service.Name, namespace, runtime_kubeconfig)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+// Template auto-generated once, maintained by plugin owner.
+
+package servicetlssecret
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	epplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	serviceutil "github.com/intel/edge-conductor/pkg/eputils/service"
+	fakeserviceutils "github.com/intel/edge-conductor/pkg/eputils/test/fakeserviceutils"
+
+	"github.com/stretchr/testify/require"
+	mpatch "github.com/undefinedlabs/go-mpatch"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+const testExtensions = `"extensions":[{"name":"service-tls","extension":{"extension":[
+	{"name":"web-cert","config":[{"name":"service-name","value":"web"}]},
+	{"name":"db-cert","config":[{"name":"service-name","value":"db"}]},
+	{"name":"new-cert","config":[{"name":"service-name","value":"new"}]}]}}]`
+
+var kubeerr = errors.New("kubernetes error")
+
+func patchAll(t *testing.T, relErr error, secretErr error) *[]string {
+	patches := []*mpatch.Patch{}
+	patch := func(p *mpatch.Patch, err error) {
+		require.NoError(t, err)
+		patches = append(patches, p)
+	}
+	t.Cleanup(func() {
+		for _, p := range patches {
+			require.NoError(t, p.Unpatch())
+		}
+	})
+
+	fakeHelm := &fakeserviceutils.FakeHelmDeployer{}
+	names := []string{}
+	patch(mpatch.PatchMethod(serviceutil.NewHelmDeployer, func(name, namespace, chart, values string) serviceutil.HelmDeployerWrapper {
+		names = append(names, name)
+		return fakeHelm
+	}))
+	patch(mpatch.PatchInstanceMethodByName(reflect.TypeOf(fakeHelm), "HelmRelease", func(*fakeserviceutils.FakeHelmDeployer, string) (*release.Release, error) {
+		if relErr != nil {
+			return nil, relErr
+		}
+		// The last service of the service config is not deployed.
+		if names[len(names)-1] == "new" {
+			return nil, nil
+		}
+		return &release.Release{Version: 1}, nil
+	}))
+	pushed := []string{}
+	patch(mpatch.PatchMethod(serviceutil.GenSvcSecretFromTLSExtension, func(exts []*epplugins.EpParamsExtensionsItems0, tgtSvc, ns, kubeconfig string) error {
+		pushed = append(pushed, ns+"/"+tgtSvc)
+		return secretErr
+	}))
+	return &pushed
+}
+
+func run(t *testing.T, cmdline string) error {
+	input := generateInput(map[string][]byte{
+		"ep-params": []byte(`{"kubeconfig":"","cmdline":"` + cmdline + `",` + testExtensions + `}`),
+		"serviceconfig": []byte(`{"components":[
+			{"name":"web","type":"helm","namespace":"web"},
+			{"name":"db","type":"helm"},
+			{"name":"yaml","type":"yaml"},
+			{"name":"new","type":"helm"}]}`),
+	})
+	require.NotNil(t, input)
+	output := generateOutput(nil)
+	return PluginMain(input, &output)
+}
+
+func TestPluginMain(t *testing.T) {
+	cases := []struct {
+		name      string
+		cmdline   string
+		relErr    error
+		secretErr error
+		want      []string
+		wantErr   error
+	}{
+		{name: "all", want: []string{"web/web", "default/db"}},
+		{name: "rotated", cmdline: "cert=db-cert", want: []string{"default/db"}},
+		{name: "not_rotated", cmdline: "cert=workflow", want: []string{}},
+		{name: "release_error", relErr: kubeerr, want: []string{}, wantErr: kubeerr},
+		{name: "secret_error", secretErr: kubeerr, want: []string{"web/web"}, wantErr: kubeerr},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pushed := patchAll(t, tc.relErr, tc.secretErr)
+			require.Equal(t, tc.wantErr, run(t, tc.cmdline))
+			require.Equal(t, tc.want, *pushed)
+		})
+	}
+}
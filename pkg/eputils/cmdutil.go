@@ -27,3 +27,13 @@ func CheckCmdline(cmdline, cmd string) bool {
 	}
 	return false
 }
+
+func GetCmdlineValue(cmdline, key string) (string, bool) {
+	aryTmp := strings.Split(cmdline, CMD_SPLIT)
+	for _, k := range aryTmp {
+		if strings.HasPrefix(k, key+"=") {
+			return strings.TrimPrefix(k, key+"="), true
+		}
+	}
+	return "", false
+}
@@ -73,3 +73,43 @@ func TestAddCmdline(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCmdlineValue(t *testing.T) {
+	type args struct {
+		cmdline string
+		key     string
+	}
+	tests := []struct {
+		name  string
+		args  args
+		want  string
+		found bool
+	}{
+		{
+			name: "no-value",
+			args: args{
+				cmdline: "force-download\nrevision",
+				key:     "revision",
+			},
+			want:  "",
+			found: false,
+		},
+		{
+			name: "value",
+			args: args{
+				cmdline: "force-download\nservice=nginx\nrevision=2",
+				key:     "revision",
+			},
+			want:  "2",
+			found: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := GetCmdlineValue(tt.args.cmdline, tt.args.key)
+			if got != tt.want || found != tt.found {
+				t.Errorf("GetCmdlineValue() = %v, %v, want %v, %v", got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
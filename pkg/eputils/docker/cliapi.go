@@ -14,6 +14,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"github.com/intel/edge-conductor/pkg/eputils"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/signal"
@@ -138,6 +139,17 @@ func ImagePushToRegistry(image string, registry string, conf *api.Customconfig)
 //   authConf:   The authentication configuration
 //
 func ImagePull(imageRef string, authConf *types.AuthConfig) error {
+	return ImagePullWithProgress(imageRef, authConf, nil)
+}
+
+// ImagePullWithProgress: Pull image and report the bytes pulled
+//
+// Parameters:
+//   imageRef:   Tag of the image
+//   authConf:   The authentication configuration
+//   progress:   Called with the bytes pulled of all the layers, can be nil
+//
+func ImagePullWithProgress(imageRef string, authConf *types.AuthConfig, progress eputils.ProgressFunc) error {
 	ctx := getDefaultContext()
 	cli, err := getDockerClient()
 	if err != nil {
@@ -170,8 +182,7 @@ func ImagePull(imageRef string, authConf *types.AuthConfig) error {
 	}
 	defer logreader.Close()
 
-	terminalFD, isTerminal := term.GetFdInfo(os.Stdout)
-	if err = jsonmessage.DisplayJSONMessagesStream(logreader, os.Stdout, terminalFD, isTerminal, nil); err != nil {
+	if err = displayJSONMessages(logreader, progress); err != nil {
 		log.Error(err)
 		return err
 	}
@@ -185,6 +196,17 @@ func ImagePull(imageRef string, authConf *types.AuthConfig) error {
 //   authConf:   The authentication configuration
 //
 func ImagePush(imageRef string, authConf *types.AuthConfig) error {
+	return ImagePushWithProgress(imageRef, authConf, nil)
+}
+
+// ImagePushWithProgress: Push image and report the bytes pushed
+//
+// Parameters:
+//   imageRef:   Tag of the image
+//   authConf:   The authentication configuration
+//   progress:   Called with the bytes pushed of all the layers, can be nil
+//
+func ImagePushWithProgress(imageRef string, authConf *types.AuthConfig, progress eputils.ProgressFunc) error {
 	ctx := getDefaultContext()
 	cli, err := getDockerClient()
 	if err != nil {
@@ -209,14 +231,73 @@ func ImagePush(imageRef string, authConf *types.AuthConfig) error {
 	}
 	defer logreader.Close()
 
-	terminalFD, isTerminal := term.GetFdInfo(os.Stdout)
-	if err = jsonmessage.DisplayJSONMessagesStream(logreader, os.Stdout, terminalFD, isTerminal, nil); err != nil {
+	if err = displayJSONMessages(logreader, progress); err != nil {
 		log.Error(err)
 		return err
 	}
 	return nil
 }
 
+// displayJSONMessages shows the messages of an image pull or push on
+// stdout, and calls progress, if not nil, with the bytes transferred.
+func displayJSONMessages(in io.Reader, progress eputils.ProgressFunc) error {
+	terminalFD, isTerminal := term.GetFdInfo(os.Stdout)
+	if progress == nil {
+		return jsonmessage.DisplayJSONMessagesStream(in, os.Stdout, terminalFD, isTerminal, nil)
+	}
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		readLayerProgress(pr, progress)
+	}()
+	err := jsonmessage.DisplayJSONMessagesStream(io.TeeReader(in, pw), os.Stdout, terminalFD, isTerminal, nil)
+	pw.Close()
+	<-done
+	return err
+}
+
+// readLayerProgress sums the bytes downloaded or pushed of the layers in a
+// stream of JSON messages, and calls progress when they change.
+func readLayerProgress(in io.Reader, progress eputils.ProgressFunc) {
+	// Drain the stream if a message can not be decoded.
+	defer func() {
+		if _, err := io.Copy(ioutil.Discard, in); err != nil {
+			log.Debugln(err)
+		}
+	}()
+	type layer struct{ current, total int64 }
+	layers := map[string]*layer{}
+	dec := json.NewDecoder(in)
+	for {
+		var jm jsonmessage.JSONMessage
+		if err := dec.Decode(&jm); err != nil {
+			return
+		}
+		l := layers[jm.ID]
+		switch {
+		case jm.ID == "":
+			continue
+		case (jm.Status == "Downloading" || jm.Status == "Pushing") && jm.Progress != nil && jm.Progress.Total > 0:
+			if l == nil {
+				l = &layer{}
+				layers[jm.ID] = l
+			}
+			l.current, l.total = jm.Progress.Current, jm.Progress.Total
+		case l != nil && (jm.Status == "Download complete" || jm.Status == "Pull complete" || jm.Status == "Pushed"):
+			l.current = l.total
+		default:
+			continue
+		}
+		var current, total int64
+		for _, l := range layers {
+			current += l.current
+			total += l.total
+		}
+		progress(current, total)
+	}
+}
+
 // ImageBuild: build image
 //
 // Parameters:
@@ -350,6 +350,24 @@ func TestImagePull(t *testing.T) {
 	t.Log("Done")
 }
 
+func TestReadLayerProgress(t *testing.T) {
+	stream := `{"status":"Pulling from library/busybox","id":"latest"}
+{"status":"Downloading","progressDetail":{"current":10,"total":100},"id":"a"}
+{"status":"Downloading","progressDetail":{"current":5,"total":50},"id":"b"}
+{"status":"Extracting","progressDetail":{"current":1,"total":100},"id":"a"}
+{"status":"Download complete","id":"a"}
+{"status":"Pushed","id":"b"}
+not json`
+	var got [][2]int64
+	readLayerProgress(strings.NewReader(stream), func(current, total int64) {
+		got = append(got, [2]int64{current, total})
+	})
+	expected := [][2]int64{{10, 100}, {15, 150}, {105, 150}, {150, 150}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Progress %v, expected %v", got, expected)
+	}
+}
+
 func TestImagePush(t *testing.T) {
 	normalFunc := func(t *testing.T, ctrl *gomock.Controller) []*mpatch.Patch {
 
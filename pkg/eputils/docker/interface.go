@@ -10,6 +10,7 @@ package docker
 import (
 	"context"
 	api "github.com/intel/edge-conductor/pkg/api/plugins"
+	"github.com/intel/edge-conductor/pkg/eputils"
 	"io"
 	"time"
 
@@ -152,6 +153,14 @@ type DockerClientWrapperImage interface {
 	//   authConf:   The authentication configuration
 	//
 	ImagePull(imageRef string, authConf *types.AuthConfig) error
+	// ImagePullWithProgress: Pull image and report the bytes pulled
+	//
+	// Parameters:
+	//   imageRef:   Tag of the image
+	//   authConf:   The authentication configuration
+	//   progress:   Called with the bytes pulled of all the layers, can be nil
+	//
+	ImagePullWithProgress(imageRef string, authConf *types.AuthConfig, progress eputils.ProgressFunc) error
 	// ImagePush: Push image
 	//
 	// Parameters:
@@ -159,6 +168,14 @@ type DockerClientWrapperImage interface {
 	//   authConf:   The authentication configuration
 	//
 	ImagePush(imageRef string, authConf *types.AuthConfig) error
+	// ImagePushWithProgress: Push image and report the bytes pushed
+	//
+	// Parameters:
+	//   imageRef:   Tag of the image
+	//   authConf:   The authentication configuration
+	//   progress:   Called with the bytes pushed of all the layers, can be nil
+	//
+	ImagePushWithProgress(imageRef string, authConf *types.AuthConfig, progress eputils.ProgressFunc) error
 	// ImageBuild: build image
 	//
 	// Parameters:
@@ -25,6 +25,7 @@ import (
 	client "github.com/docker/docker/client"
 	gomock "github.com/golang/mock/gomock"
 	plugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
 )
 
 // MockDockerClientWrapperContainer is a mock of DockerClientWrapperContainer interface.
@@ -246,6 +247,20 @@ func (mr *MockDockerClientWrapperImageMockRecorder) ImagePull(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImagePull", reflect.TypeOf((*MockDockerClientWrapperImage)(nil).ImagePull), arg0, arg1)
 }
 
+// ImagePullWithProgress mocks base method.
+func (m *MockDockerClientWrapperImage) ImagePullWithProgress(arg0 string, arg1 *types.AuthConfig, arg2 eputils.ProgressFunc) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImagePullWithProgress", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImagePullWithProgress indicates an expected call of ImagePullWithProgress.
+func (mr *MockDockerClientWrapperImageMockRecorder) ImagePullWithProgress(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImagePullWithProgress", reflect.TypeOf((*MockDockerClientWrapperImage)(nil).ImagePullWithProgress), arg0, arg1, arg2)
+}
+
 // ImagePush mocks base method.
 func (m *MockDockerClientWrapperImage) ImagePush(arg0 string, arg1 *types.AuthConfig) error {
 	m.ctrl.T.Helper()
@@ -274,6 +289,20 @@ func (mr *MockDockerClientWrapperImageMockRecorder) ImagePushToRegistry(arg0, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImagePushToRegistry", reflect.TypeOf((*MockDockerClientWrapperImage)(nil).ImagePushToRegistry), arg0, arg1, arg2)
 }
 
+// ImagePushWithProgress mocks base method.
+func (m *MockDockerClientWrapperImage) ImagePushWithProgress(arg0 string, arg1 *types.AuthConfig, arg2 eputils.ProgressFunc) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImagePushWithProgress", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImagePushWithProgress indicates an expected call of ImagePushWithProgress.
+func (mr *MockDockerClientWrapperImageMockRecorder) ImagePushWithProgress(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImagePushWithProgress", reflect.TypeOf((*MockDockerClientWrapperImage)(nil).ImagePushWithProgress), arg0, arg1, arg2)
+}
+
 // TagImage mocks base method.
 func (m *MockDockerClientWrapperImage) TagImage(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
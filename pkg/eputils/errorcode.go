@@ -96,6 +96,19 @@ var ErrorGroup = map[string]error{
 	"errIgnoreFormat":           &EC_errors{"E001.049", "Ignore format error", ""},
 	"errUnknownCmdType":         &EC_errors{"E001.050", "Unknown command type", ""},
 	"errBinary":                 &EC_errors{"E001.051", "binary is not specified in cluster manifest", ""},
+	"errCheckpoint":             &EC_errors{"E001.052", "Failed to load or save workflow checkpoint", ""},
+	"errStepTimeout":            &EC_errors{"E001.053", "Workflow step timed out", ""},
+	"errWorkflowCancel":         &EC_errors{"E001.054", "Workflow is cancelled", ""},
+	"errWorkflowCall":           &EC_errors{"E001.055", "Invalid workflow call step", ""},
+	"errWhen":                   &EC_errors{"E001.056", "Failed to evaluate the when condition of step", ""},
+	"errPluginExec":             &EC_errors{"E001.057", "Failed to run plugin executable", ""},
+	"errPluginVersion":          &EC_errors{"E001.058", "Plugin protocol version or schemas do not match the workflow server", ""},
+	"errExecNodeFailed":         &EC_errors{"E001.059", "Executor commands failed on some nodes", ""},
+	"errExecTemplate":           &EC_errors{"E001.060", "Invalid executor template command", ""},
+	"errWaitFor":                &EC_errors{"E001.061", "Invalid executor waitFor command", ""},
+	"errWaitForTimeout":         &EC_errors{"E001.062", "Timed out waiting for the executor waitFor condition", ""},
+	"errFileCheck":              &EC_errors{"E001.063", "Executor fileCheck failed", ""},
+	"errExecRegister":           &EC_errors{"E001.064", "Invalid executor register, only shell commands can be registered", ""},
 
 	// E001.1**: kind cluster errors
 	"errCreateKIND": &EC_errors{"E001.101", "Failed to create KIND cluster", ""},
@@ -134,27 +147,36 @@ var ErrorGroup = map[string]error{
 	"errMgmtCluster":          &EC_errors{"E001.331", "Failed to get management cluster binary list", ""},
 
 	// E001.4**: Service errors
-	"errExtNotFound":     &EC_errors{"E001.401", "service's tls extension of  is not found", ""},
-	"errPullInvalidCmd":  &EC_errors{"E001.402", "pullFile: invalid command", ""},
-	"errExtCfgNotFound":  &EC_errors{"E001.403", "service's tls config is not found", ""},
-	"errCSRFileNotFound": &EC_errors{"E001.404", "Service TLS error: CSR filename not found", ""},
-	"errPullOnlyOnDay0":  &EC_errors{"E001.405", "pullFile: only supported on day-0", ""},
-	"errPushInvalidCmd":  &EC_errors{"E001.406", "pushFile: invalid command", ""},
-	"errPushOnlyOnDay0":  &EC_errors{"E001.407", "pullFile: only supported on day-0", ""},
-	"errHelmEmpty":       &EC_errors{"E001.408", "Helm repo or chart name is empty", ""},
-	"errServiceStatus":   &EC_errors{"E001.409", "Helm service is in a wrong status", ""},
-	"errUnknownStatus":   &EC_errors{"E001.410", "Helm service status is unknown", ""},
-	"errPluginReturn":    &EC_errors{"E001.411", "failed to return schemaMap data", ""},
-	"errNotInList":       &EC_errors{"E001.412", "File not found in download list.", ""},
-	"errNoServerPort":    &EC_errors{"E001.413", "Server address or port is missing in kitconfig", ""},
+	"errExtNotFound":        &EC_errors{"E001.401", "service's tls extension of  is not found", ""},
+	"errPullInvalidCmd":     &EC_errors{"E001.402", "pullFile: invalid command", ""},
+	"errExtCfgNotFound":     &EC_errors{"E001.403", "service's tls config is not found", ""},
+	"errCSRFileNotFound":    &EC_errors{"E001.404", "Service TLS error: CSR filename not found", ""},
+	"errPullOnlyOnDay0":     &EC_errors{"E001.405", "pullFile: only supported on day-0", ""},
+	"errPushInvalidCmd":     &EC_errors{"E001.406", "pushFile: invalid command", ""},
+	"errPushOnlyOnDay0":     &EC_errors{"E001.407", "pullFile: only supported on day-0", ""},
+	"errHelmEmpty":          &EC_errors{"E001.408", "Helm repo or chart name is empty", ""},
+	"errServiceStatus":      &EC_errors{"E001.409", "Helm service is in a wrong status", ""},
+	"errUnknownStatus":      &EC_errors{"E001.410", "Helm service status is unknown", ""},
+	"errPluginReturn":       &EC_errors{"E001.411", "failed to return schemaMap data", ""},
+	"errNotInList":          &EC_errors{"E001.412", "File not found in download list.", ""},
+	"errNoServerPort":       &EC_errors{"E001.413", "Server address or port is missing in kitconfig", ""},
+	"errServiceDependency":  &EC_errors{"E001.414", "Service dependencies have a cycle", ""},
+	"errServiceRollback":    &EC_errors{"E001.415", "No recorded revision to roll back the service to", ""},
+	"errServiceNotDeployed": &EC_errors{"E001.416", "Service is not deployed", ""},
+	"errKustomizeOverlay":   &EC_errors{"E001.417", "Kustomize overlay is not found in the component archive", ""},
+	"errHelmChartVersion":   &EC_errors{"E001.418", "Chart version is required for a chart of an OCI registry", ""},
 
 	// E002: Network errors
 	"errHost":           &EC_errors{"E002.002", " provide_ip under global setings is not set", ""},
 	"errSSHPath":        &EC_errors{"E002.003", "SSH path for provision is not found.", ""},
 	"errRemoteNotAFile": &EC_errors{"E002.004", "remote copy failed with invalid file path", ""},
+	"errProxyJump":      &EC_errors{"E002.005", "proxy_jump host of the node is invalid", ""},
 
 	// E003: Kubernetes
-	"errNok8sNode": &EC_errors{"E003.001", "No k8s node", ""},
+	"errNok8sNode":        &EC_errors{"E003.001", "No k8s node", ""},
+	"errResourceNotReady": &EC_errors{"E003.002", "Kubernetes resources are not ready before the timeout", ""},
+	"errResourceFailed":   &EC_errors{"E003.003", "Kubernetes resource failed", ""},
+	"errReadyCondition":   &EC_errors{"E003.004", "Invalid readiness condition", ""},
 
 	// E004: Security errors
 	"errCertType":        &EC_errors{"E004.001", "unsupported certificate type", ""},
@@ -168,6 +190,13 @@ var ErrorGroup = map[string]error{
 	"errCertNil":         &EC_errors{"E004.009", "cert path or Key path is nil", ""},
 	"errKeyAlgo":         &EC_errors{"E004.010", "unsupported key algo", ""},
 	"errRootCert":        &EC_errors{"E004.011", "failed to parse root certificate", ""},
+	"errNoCertBundle":    &EC_errors{"E004.012", "cert bundle is not found in the runtime config", ""},
+	"errCaCertInvalid":   &EC_errors{"E004.013", "the CA cert is not a CA certificate", ""},
+	"errCaKeyMismatch":   &EC_errors{"E004.014", "the CA key does not match the CA cert", ""},
+	"errAcmeChallenge":   &EC_errors{"E004.015", "no http-01 challenge is offered by the ACME server", ""},
+	"errHostKeyUnknown":  &EC_errors{"E004.016", "the SSH host key is not known in strict host key checking", ""},
+	"errHostKeyChanged":  &EC_errors{"E004.017", "the SSH host key has changed from the one in known_hosts", ""},
+	"errHostKeyMismatch": &EC_errors{"E004.018", "the SSH host key does not match the node fingerprints", ""},
 
 	// E005: Utility errors
 	// E005.0**: Docker errors
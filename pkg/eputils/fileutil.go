@@ -36,6 +36,7 @@ type FileWrapper interface {
 	CopyFile(dstName, srcName string) (written int64, err error)
 	WriteStringToFile(content, filename string) error
 	DownloadFile(filepath string, fileurl string) error
+	DownloadFileWithProgress(filepath string, fileurl string, progress ProgressFunc) error
 	LoadJsonFromFile(filepath string, p interface{}) error
 	CreateFolderIfNotExist(path string) error
 	UncompressTgz(srctarfile, targetfolder string) error
@@ -215,8 +216,31 @@ func WriteStringToFile(content, filename string) error {
 	return nil
 }
 
+// ProgressFunc is called with the bytes done and the total bytes of a
+// transfer. The total is 0 if it is unknown.
+type ProgressFunc func(current, total int64)
+
+// progressWriter calls progress with the bytes written so far.
+type progressWriter struct {
+	current  int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.current += int64(len(p))
+	w.progress(w.current, w.total)
+	return len(p), nil
+}
+
 // Download file through url with go
 func DownloadFile(filepath string, fileurl string) error {
+	return DownloadFileWithProgress(filepath, fileurl, nil)
+}
+
+// DownloadFileWithProgress downloads a file like DownloadFile, and calls
+// progress, if not nil, with the bytes written to the file.
+func DownloadFileWithProgress(filepath string, fileurl string, progress ProgressFunc) error {
 	log.Infoln("Downloading:", fileurl, "to", filepath)
 	ufile, _ := url.Parse(fileurl)
 	if ufile.Scheme == "http" || ufile.Scheme == "https" {
@@ -238,10 +262,21 @@ func DownloadFile(filepath string, fileurl string) error {
 		defer out.Close()
 
 		// Write the body to file
-		_, err = io.Copy(out, resp.Body)
+		var body io.Reader = resp.Body
+		if progress != nil {
+			total := resp.ContentLength
+			if total < 0 {
+				total = 0
+			}
+			body = io.TeeReader(resp.Body, &progressWriter{total: total, progress: progress})
+		}
+		_, err = io.Copy(out, body)
 		return err
 	} else if ufile.Scheme == "file" {
-		_, err := CopyFile(filepath, ufile.Path)
+		written, err := CopyFile(filepath, ufile.Path)
+		if err == nil && progress != nil {
+			progress(written, written)
+		}
 		return err
 	} else {
 		return GetError("errUrlSchema")
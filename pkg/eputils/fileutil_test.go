@@ -762,6 +762,36 @@ func TestDownloadFile(t *testing.T) {
 	}
 }
 
+func TestDownloadFileWithProgress(t *testing.T) {
+	resp := &http.Response{Body: io.NopCloser(strings.NewReader("test reader")), ContentLength: 11}
+	p, err := mpatch.PatchMethod(http.Get, func(string) (*http.Response, error) { return resp, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unpatch(t, p)
+
+	var current, total int64
+	target := filepath.Join(t.TempDir(), "kind")
+	if err := DownloadFileWithProgress(target, "https://kind.sigs.k8s.io/dl/v0.12.0/kind-linux-amd64", func(c, t int64) {
+		current, total = c, t
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if current != 11 || total != 11 {
+		t.Errorf("Progress %d/%d, expected 11/11", current, total)
+	}
+
+	current, total = 0, 0
+	if err := DownloadFileWithProgress(filepath.Join(t.TempDir(), "kind"), "file://"+target, func(c, t int64) {
+		current, total = c, t
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if current != 11 || total != 11 {
+		t.Errorf("Progress %d/%d of a local file, expected 11/11", current, total)
+	}
+}
+
 func TestLoadJsonFromFile(t *testing.T) {
 	jsonTest := &JsonTest{}
 	cases := []struct {
@@ -0,0 +1,132 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package eputils
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	// HostKeyCheckingTofu trusts the host key of an unknown node on the
+	// first connection and pins it in the known_hosts store.
+	HostKeyCheckingTofu = "tofu"
+	// HostKeyCheckingStrict fails the connection to a node whose host key
+	// is not in the known_hosts store or the fingerprints of the node.
+	HostKeyCheckingStrict = "strict"
+)
+
+var (
+	// KnownHostsFile is the known_hosts store of the node host keys in the workspace.
+	KnownHostsFile = "runtime/known_hosts"
+
+	hostKeyChecking = HostKeyCheckingTofu
+	knownHostsMutex sync.Mutex
+)
+
+// SetHostKeyChecking sets the host key checking mode of the SSH connections,
+// trust-on-first-use if the mode is empty.
+func SetHostKeyChecking(mode string) {
+	if mode == "" {
+		mode = HostKeyCheckingTofu
+	}
+	hostKeyChecking = mode
+}
+
+// HostKeyCallback returns the callback verifying the host key of a node.
+// If fingerprints of the node are given, the host key must match one of them.
+// Otherwise the host key is checked against the known_hosts store, where the
+// key of an unknown host is pinned unless the host key checking is strict.
+func HostKeyCallback(fingerprints []string) ssh.HostKeyCallback {
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		return checkHostKey(fingerprints, hostname, remote, key)
+	}
+}
+
+func checkHostKey(fingerprints []string, hostname string, remote net.Addr, key ssh.PublicKey) error {
+	fingerprint := ssh.FingerprintSHA256(key)
+	if len(fingerprints) > 0 {
+		for _, fp := range fingerprints {
+			if fp == fingerprint {
+				return nil
+			}
+		}
+		log.Errorf("Host key %s of %s does not match the fingerprints of the node", fingerprint, hostname)
+		return GetError("errHostKeyMismatch")
+	}
+
+	knownHostsMutex.Lock()
+	defer knownHostsMutex.Unlock()
+
+	if err := touchKnownHosts(); err != nil {
+		return err
+	}
+	callback, err := knownhosts.New(KnownHostsFile)
+	if err != nil {
+		log.Errorf("Failed to load %s: %v", KnownHostsFile, err)
+		return err
+	}
+	err = callback(hostname, remote, key)
+	var keyErr *knownhosts.KeyError
+	if err == nil || !errors.As(err, &keyErr) {
+		return err
+	}
+	if len(keyErr.Want) > 0 {
+		log.Errorf("Host key of %s has changed to %s, remove the stale entry from %s if the change is expected",
+			hostname, fingerprint, KnownHostsFile)
+		return GetError("errHostKeyChanged")
+	}
+	if hostKeyChecking == HostKeyCheckingStrict {
+		log.Errorf("Host key %s of %s is unknown, add it to %s or the fingerprints of the node",
+			fingerprint, hostname, KnownHostsFile)
+		return GetError("errHostKeyUnknown")
+	}
+
+	log.Warnf("Permanently added the host key %s of %s to %s", fingerprint, hostname, KnownHostsFile)
+	return appendKnownHost(hostname, remote, key)
+}
+
+func touchKnownHosts() error {
+	if err := CreateFolderIfNotExist(filepath.Dir(KnownHostsFile)); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(KnownHostsFile, os.O_CREATE|os.O_RDONLY, 0600)
+	if err != nil {
+		log.Errorf("Failed to create %s: %v", KnownHostsFile, err)
+		return err
+	}
+	return f.Close()
+}
+
+func appendKnownHost(hostname string, remote net.Addr, key ssh.PublicKey) error {
+	addresses := []string{knownhosts.Normalize(hostname)}
+	if remote != nil {
+		if addr := knownhosts.Normalize(remote.String()); addr != addresses[0] {
+			addresses = append(addresses, addr)
+		}
+	}
+	f, err := os.OpenFile(KnownHostsFile, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		log.Errorf("Failed to open %s: %v", KnownHostsFile, err)
+		return err
+	}
+	defer f.Close()
+	if _, err := fmt.Fprintln(f, knownhosts.Line(addresses, key)); err != nil {
+		log.Errorf("Failed to write %s: %v", KnownHostsFile, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package eputils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func newTestHostKey(t *testing.T) ssh.PublicKey {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestHostKeyCallback(t *testing.T) {
+	savedKnownHostsFile := KnownHostsFile
+	defer func() {
+		KnownHostsFile = savedKnownHostsFile
+		SetHostKeyChecking("")
+	}()
+	KnownHostsFile = filepath.Join(t.TempDir(), "runtime", "known_hosts")
+
+	key := newTestHostKey(t)
+	otherKey := newTestHostKey(t)
+	remote := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 2222}
+	remote2 := &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 22}
+
+	cases := []struct {
+		name         string
+		mode         string
+		fingerprints []string
+		hostname     string
+		remote       net.Addr
+		key          ssh.PublicKey
+		wantError    error
+	}{
+		{
+			name:      "strict mode with unknown host",
+			mode:      HostKeyCheckingStrict,
+			hostname:  "10.0.0.1:2222",
+			remote:    remote,
+			key:       key,
+			wantError: GetError("errHostKeyUnknown"),
+		},
+		{
+			name:     "tofu pins unknown host",
+			hostname: "10.0.0.1:2222",
+			remote:   remote,
+			key:      key,
+		},
+		{
+			name:     "strict mode with pinned host",
+			mode:     HostKeyCheckingStrict,
+			hostname: "10.0.0.1:2222",
+			remote:   remote,
+			key:      key,
+		},
+		{
+			name:      "changed host key",
+			hostname:  "10.0.0.1:2222",
+			remote:    remote,
+			key:       otherKey,
+			wantError: GetError("errHostKeyChanged"),
+		},
+		{
+			name:         "matched fingerprint",
+			mode:         HostKeyCheckingStrict,
+			fingerprints: []string{"SHA256:unknown", ssh.FingerprintSHA256(otherKey)},
+			hostname:     "10.0.0.2:22",
+			remote:       remote2,
+			key:          otherKey,
+		},
+		{
+			name:         "mismatched fingerprint",
+			fingerprints: []string{ssh.FingerprintSHA256(otherKey)},
+			hostname:     "10.0.0.1:2222",
+			remote:       remote,
+			key:          key,
+			wantError:    GetError("errHostKeyMismatch"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			SetHostKeyChecking(tc.mode)
+			err := HostKeyCallback(tc.fingerprints)(tc.hostname, tc.remote, tc.key)
+			if err != tc.wantError {
+				t.Errorf("Unexpected error: %v, want %v", err, tc.wantError)
+			}
+		})
+	}
+
+	content, err := ioutil.ReadFile(KnownHostsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 1 || !strings.HasPrefix(lines[0], "[10.0.0.1]:2222 ssh-ed25519 ") {
+		t.Errorf("Unexpected known_hosts: %q", content)
+	}
+}
@@ -83,6 +83,9 @@ func (c *ConfigMap) RenewData(key string, data string) error {
 	} else {
 		log.Infoln(c.Name, "Data[", key, "] will be added.")
 	}
+	if c.ConfigMapObj.Data == nil {
+		c.ConfigMapObj.Data = map[string]string{}
+	}
 	c.ConfigMapObj.Data[key] = data
 	return c.Update()
 }
@@ -93,6 +96,9 @@ func (c *ConfigMap) RenewBinaryData(key string, data []byte) error {
 	} else {
 		log.Infoln(c.Name, "BinaryData[", key, "] will be added.")
 	}
+	if c.ConfigMapObj.BinaryData == nil {
+		c.ConfigMapObj.BinaryData = map[string][]byte{}
+	}
 	c.ConfigMapObj.BinaryData[key] = data
 	return c.Update()
 }
@@ -115,6 +115,20 @@ func (mr *MockFileWrapperMockRecorder) DownloadFile(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadFile", reflect.TypeOf((*MockFileWrapper)(nil).DownloadFile), arg0, arg1)
 }
 
+// DownloadFileWithProgress mocks base method.
+func (m *MockFileWrapper) DownloadFileWithProgress(arg0, arg1 string, arg2 eputils.ProgressFunc) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadFileWithProgress", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DownloadFileWithProgress indicates an expected call of DownloadFileWithProgress.
+func (mr *MockFileWrapperMockRecorder) DownloadFileWithProgress(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadFileWithProgress", reflect.TypeOf((*MockFileWrapper)(nil).DownloadFileWithProgress), arg0, arg1, arg2)
+}
+
 // FileExists mocks base method.
 func (m *MockFileWrapper) FileExists(arg0 string) bool {
 	m.ctrl.T.Helper()
@@ -45,82 +45,83 @@ func (m *MockSSHApiInterface) EXPECT() *MockSSHApiInterfaceMockRecorder {
 }
 
 // ContainerdCertificatePathCreateSudoNoPasswd mocks base method.
-func (m *MockSSHApiInterface) ContainerdCertificatePathCreateSudoNoPasswd(arg0 string, arg1 *ssh.ClientConfig, arg2, arg3 string) error {
+func (m *MockSSHApiInterface) ContainerdCertificatePathCreateSudoNoPasswd(arg0 string, arg1 *ssh.ClientConfig, arg2 []string, arg3, arg4 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ContainerdCertificatePathCreateSudoNoPasswd", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "ContainerdCertificatePathCreateSudoNoPasswd", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ContainerdCertificatePathCreateSudoNoPasswd indicates an expected call of ContainerdCertificatePathCreateSudoNoPasswd.
-func (mr *MockSSHApiInterfaceMockRecorder) ContainerdCertificatePathCreateSudoNoPasswd(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockSSHApiInterfaceMockRecorder) ContainerdCertificatePathCreateSudoNoPasswd(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainerdCertificatePathCreateSudoNoPasswd", reflect.TypeOf((*MockSSHApiInterface)(nil).ContainerdCertificatePathCreateSudoNoPasswd), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainerdCertificatePathCreateSudoNoPasswd", reflect.TypeOf((*MockSSHApiInterface)(nil).ContainerdCertificatePathCreateSudoNoPasswd), arg0, arg1, arg2, arg3, arg4)
 }
 
 // CopyLocalFileToRemoteFile mocks base method.
-func (m *MockSSHApiInterface) CopyLocalFileToRemoteFile(arg0 string, arg1 *ssh.ClientConfig, arg2, arg3 string) error {
+func (m *MockSSHApiInterface) CopyLocalFileToRemoteFile(arg0 string, arg1 *ssh.ClientConfig, arg2 []string, arg3, arg4 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CopyLocalFileToRemoteFile", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "CopyLocalFileToRemoteFile", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CopyLocalFileToRemoteFile indicates an expected call of CopyLocalFileToRemoteFile.
-func (mr *MockSSHApiInterfaceMockRecorder) CopyLocalFileToRemoteFile(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockSSHApiInterfaceMockRecorder) CopyLocalFileToRemoteFile(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyLocalFileToRemoteFile", reflect.TypeOf((*MockSSHApiInterface)(nil).CopyLocalFileToRemoteFile), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyLocalFileToRemoteFile", reflect.TypeOf((*MockSSHApiInterface)(nil).CopyLocalFileToRemoteFile), arg0, arg1, arg2, arg3, arg4)
 }
 
 // CopyLocalFileToRemoteRootFileSudoNoPasswd mocks base method.
-func (m *MockSSHApiInterface) CopyLocalFileToRemoteRootFileSudoNoPasswd(arg0 string, arg1 *ssh.ClientConfig, arg2, arg3 string) error {
+func (m *MockSSHApiInterface) CopyLocalFileToRemoteRootFileSudoNoPasswd(arg0 string, arg1 *ssh.ClientConfig, arg2 []string, arg3, arg4 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CopyLocalFileToRemoteRootFileSudoNoPasswd", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "CopyLocalFileToRemoteRootFileSudoNoPasswd", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CopyLocalFileToRemoteRootFileSudoNoPasswd indicates an expected call of CopyLocalFileToRemoteRootFileSudoNoPasswd.
-func (mr *MockSSHApiInterfaceMockRecorder) CopyLocalFileToRemoteRootFileSudoNoPasswd(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockSSHApiInterfaceMockRecorder) CopyLocalFileToRemoteRootFileSudoNoPasswd(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyLocalFileToRemoteRootFileSudoNoPasswd", reflect.TypeOf((*MockSSHApiInterface)(nil).CopyLocalFileToRemoteRootFileSudoNoPasswd), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyLocalFileToRemoteRootFileSudoNoPasswd", reflect.TypeOf((*MockSSHApiInterface)(nil).CopyLocalFileToRemoteRootFileSudoNoPasswd), arg0, arg1, arg2, arg3, arg4)
 }
 
 // CopyRemoteFileToLocalFile mocks base method.
-func (m *MockSSHApiInterface) CopyRemoteFileToLocalFile(arg0 string, arg1 *ssh.ClientConfig, arg2, arg3 string, arg4 fs.FileMode) error {
+func (m *MockSSHApiInterface) CopyRemoteFileToLocalFile(arg0 string, arg1 *ssh.ClientConfig, arg2 []string, arg3, arg4 string, arg5 fs.FileMode) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CopyRemoteFileToLocalFile", arg0, arg1, arg2, arg3, arg4)
+	ret := m.ctrl.Call(m, "CopyRemoteFileToLocalFile", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CopyRemoteFileToLocalFile indicates an expected call of CopyRemoteFileToLocalFile.
-func (mr *MockSSHApiInterfaceMockRecorder) CopyRemoteFileToLocalFile(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+func (mr *MockSSHApiInterfaceMockRecorder) CopyRemoteFileToLocalFile(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyRemoteFileToLocalFile", reflect.TypeOf((*MockSSHApiInterface)(nil).CopyRemoteFileToLocalFile), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyRemoteFileToLocalFile", reflect.TypeOf((*MockSSHApiInterface)(nil).CopyRemoteFileToLocalFile), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
 // CopyRemoteRootFileToLocalFileSudoNoPasswd mocks base method.
-func (m *MockSSHApiInterface) CopyRemoteRootFileToLocalFileSudoNoPasswd(arg0 string, arg1 *ssh.ClientConfig, arg2, arg3 string, arg4 fs.FileMode) error {
+func (m *MockSSHApiInterface) CopyRemoteRootFileToLocalFileSudoNoPasswd(arg0 string, arg1 *ssh.ClientConfig, arg2 []string, arg3, arg4 string, arg5 fs.FileMode) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CopyRemoteRootFileToLocalFileSudoNoPasswd", arg0, arg1, arg2, arg3, arg4)
+	ret := m.ctrl.Call(m, "CopyRemoteRootFileToLocalFileSudoNoPasswd", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CopyRemoteRootFileToLocalFileSudoNoPasswd indicates an expected call of CopyRemoteRootFileToLocalFileSudoNoPasswd.
-func (mr *MockSSHApiInterfaceMockRecorder) CopyRemoteRootFileToLocalFileSudoNoPasswd(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+func (mr *MockSSHApiInterfaceMockRecorder) CopyRemoteRootFileToLocalFileSudoNoPasswd(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyRemoteRootFileToLocalFileSudoNoPasswd", reflect.TypeOf((*MockSSHApiInterface)(nil).CopyRemoteRootFileToLocalFileSudoNoPasswd), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyRemoteRootFileToLocalFileSudoNoPasswd", reflect.TypeOf((*MockSSHApiInterface)(nil).CopyRemoteRootFileToLocalFileSudoNoPasswd), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
 // GenSSHConfig mocks base method.
-func (m *MockSSHApiInterface) GenSSHConfig(arg0 *plugins.Node) (*ssh.ClientConfig, error) {
+func (m *MockSSHApiInterface) GenSSHConfig(arg0 *plugins.Node) (*ssh.ClientConfig, []string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GenSSHConfig", arg0)
 	ret0, _ := ret[0].(*ssh.ClientConfig)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GenSSHConfig indicates an expected call of GenSSHConfig.
@@ -130,46 +131,46 @@ func (mr *MockSSHApiInterfaceMockRecorder) GenSSHConfig(arg0 interface{}) *gomoc
 }
 
 // RemoteFileExists mocks base method.
-func (m *MockSSHApiInterface) RemoteFileExists(arg0 string, arg1 *ssh.ClientConfig, arg2 string) (bool, error) {
+func (m *MockSSHApiInterface) RemoteFileExists(arg0 string, arg1 *ssh.ClientConfig, arg2 []string, arg3 string) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoteFileExists", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "RemoteFileExists", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RemoteFileExists indicates an expected call of RemoteFileExists.
-func (mr *MockSSHApiInterfaceMockRecorder) RemoteFileExists(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockSSHApiInterfaceMockRecorder) RemoteFileExists(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoteFileExists", reflect.TypeOf((*MockSSHApiInterface)(nil).RemoteFileExists), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoteFileExists", reflect.TypeOf((*MockSSHApiInterface)(nil).RemoteFileExists), arg0, arg1, arg2, arg3)
 }
 
 // RunRemoteCMD mocks base method.
-func (m *MockSSHApiInterface) RunRemoteCMD(arg0 string, arg1 *ssh.ClientConfig, arg2 string) error {
+func (m *MockSSHApiInterface) RunRemoteCMD(arg0 string, arg1 *ssh.ClientConfig, arg2 []string, arg3 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RunRemoteCMD", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "RunRemoteCMD", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RunRemoteCMD indicates an expected call of RunRemoteCMD.
-func (mr *MockSSHApiInterfaceMockRecorder) RunRemoteCMD(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockSSHApiInterfaceMockRecorder) RunRemoteCMD(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunRemoteCMD", reflect.TypeOf((*MockSSHApiInterface)(nil).RunRemoteCMD), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunRemoteCMD", reflect.TypeOf((*MockSSHApiInterface)(nil).RunRemoteCMD), arg0, arg1, arg2, arg3)
 }
 
 // RunRemoteMultiCMD mocks base method.
-func (m *MockSSHApiInterface) RunRemoteMultiCMD(arg0 string, arg1 *ssh.ClientConfig, arg2 []string) error {
+func (m *MockSSHApiInterface) RunRemoteMultiCMD(arg0 string, arg1 *ssh.ClientConfig, arg2, arg3 []string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RunRemoteMultiCMD", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "RunRemoteMultiCMD", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RunRemoteMultiCMD indicates an expected call of RunRemoteMultiCMD.
-func (mr *MockSSHApiInterfaceMockRecorder) RunRemoteMultiCMD(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockSSHApiInterfaceMockRecorder) RunRemoteMultiCMD(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunRemoteMultiCMD", reflect.TypeOf((*MockSSHApiInterface)(nil).RunRemoteMultiCMD), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunRemoteMultiCMD", reflect.TypeOf((*MockSSHApiInterface)(nil).RunRemoteMultiCMD), arg0, arg1, arg2, arg3)
 }
 
 // RunRemoteNodeMultiCMD mocks base method.
@@ -187,31 +188,31 @@ func (mr *MockSSHApiInterfaceMockRecorder) RunRemoteNodeMultiCMD(arg0, arg1 inte
 }
 
 // ServiceRestartSudoNoPasswd mocks base method.
-func (m *MockSSHApiInterface) ServiceRestartSudoNoPasswd(arg0 string, arg1 *ssh.ClientConfig, arg2 string) error {
+func (m *MockSSHApiInterface) ServiceRestartSudoNoPasswd(arg0 string, arg1 *ssh.ClientConfig, arg2 []string, arg3 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ServiceRestartSudoNoPasswd", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "ServiceRestartSudoNoPasswd", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ServiceRestartSudoNoPasswd indicates an expected call of ServiceRestartSudoNoPasswd.
-func (mr *MockSSHApiInterfaceMockRecorder) ServiceRestartSudoNoPasswd(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockSSHApiInterfaceMockRecorder) ServiceRestartSudoNoPasswd(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServiceRestartSudoNoPasswd", reflect.TypeOf((*MockSSHApiInterface)(nil).ServiceRestartSudoNoPasswd), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServiceRestartSudoNoPasswd", reflect.TypeOf((*MockSSHApiInterface)(nil).ServiceRestartSudoNoPasswd), arg0, arg1, arg2, arg3)
 }
 
 // WriteRemoteFile mocks base method.
-func (m *MockSSHApiInterface) WriteRemoteFile(arg0 string, arg1 *ssh.ClientConfig, arg2, arg3 string) error {
+func (m *MockSSHApiInterface) WriteRemoteFile(arg0 string, arg1 *ssh.ClientConfig, arg2 []string, arg3, arg4 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "WriteRemoteFile", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "WriteRemoteFile", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // WriteRemoteFile indicates an expected call of WriteRemoteFile.
-func (mr *MockSSHApiInterfaceMockRecorder) WriteRemoteFile(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockSSHApiInterfaceMockRecorder) WriteRemoteFile(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteRemoteFile", reflect.TypeOf((*MockSSHApiInterface)(nil).WriteRemoteFile), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteRemoteFile", reflect.TypeOf((*MockSSHApiInterface)(nil).WriteRemoteFile), arg0, arg1, arg2, arg3, arg4)
 }
 
 // MockSSHDialInterface is a mock of SSHDialInterface interface.
@@ -247,7 +248,7 @@ func (m *MockSSHDialInterface) Dial(arg0, arg1 string, arg2 *ssh.ClientConfig) (
 }
 
 // Dial indicates an expected call of Dial.
-func (mr *MockSSHDialInterfaceMockRecorder) Dial(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockSSHDialInterfaceMockRecorder) Dial(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dial", reflect.TypeOf((*MockSSHDialInterface)(nil).Dial), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dial", reflect.TypeOf((*MockSSHDialInterface)(nil).Dial), arg0, arg1, arg2, arg3)
 }
@@ -124,6 +124,21 @@ func (mr *MockOrasUtilInterfaceMockRecorder) OrasPullFile(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrasPullFile", reflect.TypeOf((*MockOrasUtilInterface)(nil).OrasPullFile), arg0, arg1)
 }
 
+// OrasPushChart mocks base method.
+func (m *MockOrasUtilInterface) OrasPushChart(arg0, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrasPushChart", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OrasPushChart indicates an expected call of OrasPushChart.
+func (mr *MockOrasUtilInterfaceMockRecorder) OrasPushChart(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrasPushChart", reflect.TypeOf((*MockOrasUtilInterface)(nil).OrasPushChart), arg0, arg1)
+}
+
 // OrasPushFile mocks base method.
 func (m *MockOrasUtilInterface) OrasPushFile(arg0, arg1, arg2 string) (string, error) {
 	m.ctrl.T.Helper()
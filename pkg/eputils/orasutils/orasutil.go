@@ -10,9 +10,12 @@ package orasutils
 //go:generate mockgen -destination=./mock/orasutil_mock.go -package=mock -copyright_file=../../../api/schemas/license-header.txt github.com/intel/edge-conductor/pkg/eputils/orasutils OrasInterface,OrasUtilInterface
 
 import (
+	"bytes"
 	sysctx "context"
+	"encoding/json"
 	"fmt"
 	log "github.com/sirupsen/logrus"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"path/filepath"
@@ -29,6 +32,8 @@ import (
 	"github.com/containerd/containerd/remotes/docker"
 	"github.com/docker/docker/api/types"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/registry"
 
 	"github.com/intel/edge-conductor/pkg/eputils"
 )
@@ -51,6 +56,7 @@ type (
 
 	OrasUtilInterface interface {
 		OrasPushFile(filename, subRef, rev string) (string, error)
+		OrasPushChart(filename, subRef string) (string, error)
 		OrasPullFile(targetFile string, regRef string) error
 	}
 )
@@ -110,6 +116,57 @@ func (c *OrasClient) OrasPushFile(filename, subRef, rev string) (string, error)
 	return ref, nil
 }
 
+// OrasPushChart pushes a Helm chart archive to the default registry as an OCI
+// Helm chart, tagged with the chart version, so Helm and other tools can pull
+// it from the registry.
+func (c *OrasClient) OrasPushChart(filename, subRef string) (string, error) {
+	if filename == "" {
+		return "", eputils.GetError("errFileEmpty")
+	}
+	if subRef == "" {
+		subRef = "tmp"
+	}
+	h, exists := c.hosts["default"]
+	if !exists {
+		log.Errorf("Oras default resolver %s not found", h.address)
+		return "", eputils.GetError("errOrasDefaultResolver")
+	}
+	if err := updateResolver(h.address, c); err != nil {
+		return "", err
+	}
+	h = c.hosts["default"]
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		log.Errorf("Failed to open: %s", filename)
+		return "", err
+	}
+	chart, err := loader.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		log.Errorf("Failed to load chart %s: %v", filename, err)
+		return "", err
+	}
+	config, err := json.Marshal(chart.Metadata)
+	if err != nil {
+		return "", err
+	}
+	store := content.NewMemoryStore()
+	configDesc := store.Add("", registry.ConfigMediaType, config)
+	// The title of the chart layer lets OrasPullFile pull the chart as a file.
+	chartDesc := store.Add(filepath.Base(filename), registry.ChartLayerMediaType, data)
+
+	// OCI tags do not support "+", Helm replaces it with "_".
+	tag := strings.ReplaceAll(chart.Metadata.Version, "+", "_")
+	targetRef := fmt.Sprintf("%s/%s/%s/%s:%s", h.address, RegProject, subRef, chart.Metadata.Name, tag)
+	log.Infof("Push chart %s to %s", filename, targetRef)
+	_, err = oras.Push(context.Background(), h.resolver, targetRef, store, []ocispec.Descriptor{chartDesc},
+		oras.WithConfig(configDesc))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("oci://%s", targetRef), nil
+}
+
 func (c *OrasClient) OrasPullFile(targetFile string, regRef string) error {
 	mediaType := FileMediaType
 	ctx := context.Background()
@@ -123,6 +180,7 @@ func (c *OrasClient) OrasPullFile(targetFile string, regRef string) error {
 		defer store.Close()
 		allowedMediaTypes := []string{
 			mediaType,
+			registry.ChartLayerMediaType,
 		}
 
 		log.Infof("Pulling from %s", targetRef)
@@ -131,7 +189,7 @@ func (c *OrasClient) OrasPullFile(targetFile string, regRef string) error {
 			// Remap the local path of the file to target path.
 			oras.WithPullBaseHandler(images.HandlerFunc(
 				func(ctx sysctx.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
-					if desc.MediaType == mediaType {
+					if desc.MediaType == mediaType || desc.MediaType == registry.ChartLayerMediaType {
 						name, _ := content.ResolveName(desc)
 						store.MapPath(name, targetFile)
 					}
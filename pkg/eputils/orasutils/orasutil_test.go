@@ -23,6 +23,8 @@ import (
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	log "github.com/sirupsen/logrus"
 	mpatch "github.com/undefinedlabs/go-mpatch"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
 )
 
 var testdatapath string
@@ -117,6 +119,65 @@ func TestOrasPushFile(t *testing.T) {
 	}
 }
 
+func TestOrasPushChart(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "orasutils")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	chartFile, err := chartutil.Save(&chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "mychart", Version: "1.0.0+build"},
+	}, tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name         string
+		filename     string
+		retOrasError error
+		expectRef    string
+		expectError  bool
+	}{
+		{"No_filename", "", nil, "", true},
+		{"Invalid_chart", filepath.Join(testdatapath, "orasfake.yml"), nil, "", true},
+		{"Oras_func_return_error", chartFile, eputils.GetError("errOras"), "", true},
+		{"Valid_chart", chartFile, nil, "oci://10.10.10.10/library/charts/mychart:1.0.0_build", false},
+	}
+
+	err = OrasNewClient(&types.AuthConfig{
+		Username:      "test",
+		Password:      "test123",
+		ServerAddress: "10.10.10.10",
+	}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockOrasInterface := mock_orasutils.NewMockOrasInterface(ctrl)
+			patch, err := mpatch.PatchMethod(oras.Push, mockOrasInterface.Push)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer unpatch(t, patch)
+
+			mockOrasInterface.EXPECT().Push(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(ocispec.Descriptor{}, tc.retOrasError)
+
+			ref, err := OrasCli.OrasPushChart(tc.filename, "charts")
+			if (err != nil) != tc.expectError {
+				t.Errorf("Test case %s failed: %v", tc.name, err)
+			}
+			if ref != tc.expectRef {
+				t.Errorf("Test case %s failed: unexpected ref %s", tc.name, ref)
+			}
+		})
+	}
+}
+
 func TestOrasPullFile(t *testing.T) {
 	cases := []struct {
 		name         string
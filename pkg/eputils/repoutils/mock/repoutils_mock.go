@@ -41,6 +41,20 @@ func (m *MockRepoUtilsInterface) EXPECT() *MockRepoUtilsInterfaceMockRecorder {
 	return m.recorder
 }
 
+// PullChartFromRegistry mocks base method.
+func (m *MockRepoUtilsInterface) PullChartFromRegistry(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PullChartFromRegistry", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PullChartFromRegistry indicates an expected call of PullChartFromRegistry.
+func (mr *MockRepoUtilsInterfaceMockRecorder) PullChartFromRegistry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullChartFromRegistry", reflect.TypeOf((*MockRepoUtilsInterface)(nil).PullChartFromRegistry), arg0, arg1)
+}
+
 // PullFileFromRepo mocks base method.
 func (m *MockRepoUtilsInterface) PullFileFromRepo(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -55,6 +69,21 @@ func (mr *MockRepoUtilsInterfaceMockRecorder) PullFileFromRepo(arg0, arg1 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullFileFromRepo", reflect.TypeOf((*MockRepoUtilsInterface)(nil).PullFileFromRepo), arg0, arg1)
 }
 
+// PushChartToRepo mocks base method.
+func (m *MockRepoUtilsInterface) PushChartToRepo(arg0, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PushChartToRepo", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PushChartToRepo indicates an expected call of PushChartToRepo.
+func (mr *MockRepoUtilsInterfaceMockRecorder) PushChartToRepo(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushChartToRepo", reflect.TypeOf((*MockRepoUtilsInterface)(nil).PushChartToRepo), arg0, arg1)
+}
+
 // PushFileToRepo mocks base method.
 func (m *MockRepoUtilsInterface) PushFileToRepo(arg0, arg1, arg2 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -12,15 +12,20 @@ package repoutils
 import (
 	"github.com/intel/edge-conductor/pkg/eputils"
 	orasutils "github.com/intel/edge-conductor/pkg/eputils/orasutils"
+	"io/ioutil"
 	"net/url"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/registry"
 )
 
 type (
 	RepoUtilsInterface interface {
 		PushFileToRepo(filepath, subRef, rev string) (string, error)
 		PullFileFromRepo(filepath string, targeturl string) error
+		PushChartToRepo(filepath, subRef string) (string, error)
+		PullChartFromRegistry(filepath, ref string) error
 	}
 )
 
@@ -58,3 +63,36 @@ func PullFileFromRepo(filepath string, targeturl string) error {
 	}
 	return nil
 }
+
+// PushChartToRepo pushes a Helm chart archive to the registry as an OCI Helm
+// chart. The chart is pulled back with PullFileFromRepo.
+func PushChartToRepo(filepath, subRef string) (string, error) {
+	if orasutils.OrasCli == nil {
+		return "", eputils.GetError("errNoPushClient")
+	}
+	ref, err := orasutils.OrasCli.OrasPushChart(filepath, subRef)
+	if err != nil {
+		log.Errorln("Failed to push chart", filepath, err)
+		return "", err
+	}
+	return ref, nil
+}
+
+// PullChartFromRegistry pulls a Helm chart archive from an OCI registry with
+// the Helm registry client, e.g. from "oci://ghcr.io/org/charts/name:1.0.0",
+// or from "oci://ghcr.io/org/charts/name@sha256:..." to pin the manifest
+// digest. The content of the chart is verified against the digests of the
+// manifest.
+func PullChartFromRegistry(filepath, ref string) error {
+	client, err := registry.NewClient()
+	if err != nil {
+		return err
+	}
+	result, err := client.Pull(strings.TrimPrefix(ref, registry.OCIScheme+"://"))
+	if err != nil {
+		log.Errorln("Failed to pull chart", ref, err)
+		return err
+	}
+	log.Infof("Pulled chart %s, manifest digest %s, chart digest %s", result.Ref, result.Manifest.Digest, result.Chart.Digest)
+	return ioutil.WriteFile(filepath, result.Chart.Data, 0600)
+}
@@ -8,6 +8,7 @@
 package repoutils
 
 import (
+	"encoding/json"
 	"errors"
 	"github.com/intel/edge-conductor/pkg/eputils"
 	"github.com/intel/edge-conductor/pkg/eputils/orasutils"
@@ -18,11 +19,15 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/undefinedlabs/go-mpatch"
+	"helm.sh/helm/v3/pkg/registry"
+	"io/ioutil"
+	"os"
 )
 
 var (
 	errOrasPull = errors.New("oraspullfile.error")
 	errOrasPush = errors.New("oraspushfile.error")
+	errHelmPull = errors.New("helmpull.error")
 	errParsePrt = errors.New("parse \"http://example.com:123abc/foo\": invalid port \":123abc\" after host")
 )
 
@@ -245,3 +250,133 @@ func TestPullFileFromRepo(t *testing.T) {
 	}
 
 }
+
+func patchoraspushchart(t *testing.T, ref string, err error) {
+	initorascli()
+	var patch *mpatch.Patch
+	var patchErr error
+
+	patch, patchErr = mpatch.PatchInstanceMethodByName(reflect.TypeOf(orasutils.OrasCli), "OrasPushChart", func(orasClient *orasutils.OrasClient, filename, subRef string) (string, error) {
+		unpatch(t, patch)
+		return ref, err
+	})
+
+	if patchErr != nil {
+		t.Errorf("patch error: %v", patchErr)
+	}
+}
+
+func TestPushChartToRepo(t *testing.T) {
+	cases := []struct {
+		name        string
+		expectRef   string
+		expectError error
+		beforetest  func()
+	}{
+		{
+			name:        "client is not available",
+			expectError: eputils.GetError("errNoPushClient"),
+			beforetest: func() {
+				orasutils.OrasCli = nil
+			},
+		},
+		{
+			name:        "oraspushchart fail",
+			expectError: errOrasPush,
+			beforetest: func() {
+				patchoraspushchart(t, "", errOrasPush)
+			},
+		},
+		{
+			name:      "oraspushchart ok",
+			expectRef: "oci://10.10.10.10/library/charts/mychart:1.0.0",
+			beforetest: func() {
+				patchoraspushchart(t, "oci://10.10.10.10/library/charts/mychart:1.0.0", nil)
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.beforetest()
+			ref, err := PushChartToRepo("mychart-1.0.0.tgz", "charts")
+			if !isExpectedError(err, tc.expectError) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if ref != tc.expectRef {
+				t.Errorf("Unexpected ref: %s", ref)
+			}
+		})
+	}
+}
+
+// newPullResult builds the result of a chart pull, the summary types of
+// which are not exported by helm.
+func newPullResult(t *testing.T, ref string, data []byte) *registry.PullResult {
+	result := &registry.PullResult{}
+	if err := json.Unmarshal([]byte(`{"manifest":{"digest":"sha256:0123"},"chart":{"digest":"sha256:4567"}}`), result); err != nil {
+		t.Fatal(err)
+	}
+	result.Ref = ref
+	reflect.ValueOf(result.Chart).Elem().FieldByName("Data").SetBytes(data)
+	return result
+}
+
+func TestPullChartFromRegistry(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "repoutils")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	target := filepath.Join(tmpDir, "mychart.tgz")
+
+	cases := []struct {
+		name        string
+		ref         string
+		expectRef   string
+		expectError error
+	}{
+		{
+			name:        "pull fail",
+			ref:         "oci://ghcr.io/org/charts/mychart:1.0.0",
+			expectRef:   "ghcr.io/org/charts/mychart:1.0.0",
+			expectError: errHelmPull,
+		},
+		{
+			name:      "pull ok",
+			ref:       "oci://ghcr.io/org/charts/mychart@sha256:0123",
+			expectRef: "ghcr.io/org/charts/mychart@sha256:0123",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var patch *mpatch.Patch
+			var gotRef string
+			patch, err := mpatch.PatchInstanceMethodByName(reflect.TypeOf(&registry.Client{}), "Pull", func(c *registry.Client, ref string, options ...registry.PullOption) (*registry.PullResult, error) {
+				unpatch(t, patch)
+				gotRef = ref
+				if tc.expectError != nil {
+					return nil, tc.expectError
+				}
+				return newPullResult(t, ref, []byte("chart")), nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = PullChartFromRegistry(target, tc.ref)
+			if !isExpectedError(err, tc.expectError) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if gotRef != tc.expectRef {
+				t.Errorf("Unexpected ref: %s", gotRef)
+			}
+			if tc.expectError == nil {
+				if data, err := ioutil.ReadFile(target); err != nil || string(data) != "chart" {
+					t.Errorf("Unexpected chart: %s, %v", data, err)
+				}
+			}
+		})
+	}
+}
@@ -10,8 +10,13 @@ package eputils
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"reflect"
 	"regexp"
 	"text/template"
 
@@ -51,6 +56,48 @@ func SchemaStructNew(name string) SchemaStruct {
 	return schemaStructNew[name]()
 }
 
+// SchemaStructHash returns a hash of the Go type registered for the schema
+// name. Two binaries generated from the same schema return the same hash.
+// The second return value is false if the name is not registered.
+func SchemaStructHash(name string) (string, bool) {
+	newFunc, has := schemaStructNew[name]
+	if !has {
+		return "", false
+	}
+	h := sha256.New()
+	writeTypeHash(h, reflect.TypeOf(newFunc()), map[reflect.Type]bool{})
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+func writeTypeHash(w io.Writer, t reflect.Type, seen map[reflect.Type]bool) {
+	fmt.Fprintf(w, "%s(", t.Kind())
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice:
+		writeTypeHash(w, t.Elem(), seen)
+	case reflect.Array:
+		fmt.Fprintf(w, "%d,", t.Len())
+		writeTypeHash(w, t.Elem(), seen)
+	case reflect.Map:
+		writeTypeHash(w, t.Key(), seen)
+		writeTypeHash(w, t.Elem(), seen)
+	case reflect.Struct:
+		fmt.Fprintf(w, "%s", t.Name())
+		if seen[t] {
+			break
+		}
+		seen[t] = true
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			fmt.Fprintf(w, ",%s:%s:", f.Name, f.Tag.Get("json"))
+			writeTypeHash(w, f.Type, seen)
+		}
+	}
+	fmt.Fprint(w, ")")
+}
+
 /*
  var SchemaStructNew map[string] func () SchemaStruct = map[string] func () SchemaStruct {
 	 "images": func () SchemaStruct { return &pluginapi.Images{} },
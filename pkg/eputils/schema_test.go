@@ -136,3 +136,23 @@ func TestSchema(t *testing.T) {
 	// Cleanup
 	os.RemoveAll(saveschemafile)
 }
+
+func TestSchemaStructHash(t *testing.T) {
+	AddSchemaStruct("hash-test.params", func() SchemaStruct { return &pluginapi.EpParams{} })
+	AddSchemaStruct("hash-test.params2", func() SchemaStruct { return &pluginapi.EpParams{} })
+	AddSchemaStruct("hash-test.files", func() SchemaStruct { return &pluginapi.Files{} })
+
+	h1, has := SchemaStructHash("hash-test.params")
+	if !has || len(h1) == 0 {
+		t.Error("Expect hash of registered schema")
+	}
+	if h2, _ := SchemaStructHash("hash-test.params2"); h1 != h2 {
+		t.Errorf("Expect same hash for same type, got %s and %s", h1, h2)
+	}
+	if h3, _ := SchemaStructHash("hash-test.files"); h1 == h3 {
+		t.Error("Expect different hash for different types")
+	}
+	if _, has := SchemaStructHash("hash-test.unknown"); has {
+		t.Error("Expect no hash for unknown schema")
+	}
+}
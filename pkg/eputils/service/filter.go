@@ -0,0 +1,89 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package service
+
+import (
+	"strings"
+
+	epplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	log "github.com/sirupsen/logrus"
+)
+
+// ServiceFilter selects the services of a service operation by name, from
+// the "only=<name,...>" and "exclude=<name,...>" options of the command
+// line. All the services are selected when no option is set.
+type ServiceFilter struct {
+	Only    []string
+	Exclude []string
+}
+
+func splitNames(names string) []string {
+	list := []string{}
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			list = append(list, name)
+		}
+	}
+	return list
+}
+
+// NewServiceFilter creates the service filter of a command line.
+func NewServiceFilter(cmdline string) *ServiceFilter {
+	f := &ServiceFilter{}
+	if only, ok := eputils.GetCmdlineValue(cmdline, "only"); ok {
+		f.Only = splitNames(only)
+	}
+	if exclude, ok := eputils.GetCmdlineValue(cmdline, "exclude"); ok {
+		f.Exclude = splitNames(exclude)
+	}
+	return f
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled tells whether the filter does not select all the services.
+func (f *ServiceFilter) Enabled() bool {
+	return len(f.Only) > 0 || len(f.Exclude) > 0
+}
+
+// Selected tells whether a service is selected by the filter.
+func (f *ServiceFilter) Selected(name string) bool {
+	if len(f.Only) > 0 && !contains(f.Only, name) {
+		return false
+	}
+	return !contains(f.Exclude, name)
+}
+
+// Filter returns the services selected by the filter, in the same order.
+// The names of the "only" option which are not in the services are logged.
+func (f *ServiceFilter) Filter(services []*epplugins.Component) []*epplugins.Component {
+	selected := []*epplugins.Component{}
+	names := map[string]bool{}
+	for _, service := range services {
+		names[service.Name] = true
+		if f.Selected(service.Name) {
+			selected = append(selected, service)
+		} else {
+			log.Infof("Service %s is skipped.", service.Name)
+		}
+	}
+	for _, name := range f.Only {
+		if !names[name] {
+			log.Warnf("Service %s is not in the service list.", name)
+		}
+	}
+	return selected
+}
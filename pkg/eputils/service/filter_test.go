@@ -0,0 +1,44 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package service
+
+import (
+	"testing"
+
+	epplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceFilter(t *testing.T) {
+	services := []*epplugins.Component{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	names := func(services []*epplugins.Component) []string {
+		list := []string{}
+		for _, s := range services {
+			list = append(list, s.Name)
+		}
+		return list
+	}
+
+	cases := []struct {
+		cmdline string
+		enabled bool
+		want    []string
+	}{
+		{"force-download", false, []string{"a", "b", "c"}},
+		{"only=c, a", true, []string{"a", "c"}},
+		{"exclude=b", true, []string{"a", "c"}},
+		{"only=a,b\nexclude=b", true, []string{"a"}},
+		{"only=d", true, []string{}},
+	}
+	for _, c := range cases {
+		f := NewServiceFilter(c.cmdline)
+		require.Equal(t, c.enabled, f.Enabled(), c.cmdline)
+		require.Equal(t, c.want, names(f.Filter(services)), c.cmdline)
+	}
+}
@@ -0,0 +1,195 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	epplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	log "github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ServiceObjectsName is the name of the service resource which records the
+// objects applied by a yaml service, so their status can be read from the
+// cluster without the yaml file.
+const ServiceObjectsName = "serviceObjects"
+
+var podResource = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+// SetServiceObjects records the objects applied by a service in its
+// resources, replacing the objects recorded before.
+func SetServiceObjects(service *epplugins.Component, objects []*ReadyResource) error {
+	value, err := json.Marshal(objects)
+	if err != nil {
+		return err
+	}
+	for _, r := range service.Resources {
+		if r.Name == ServiceObjectsName {
+			r.Value = string(value)
+			return nil
+		}
+	}
+	service.Resources = append(service.Resources,
+		&epplugins.ComponentResourcesItems0{Name: ServiceObjectsName, Value: string(value)})
+	return nil
+}
+
+// GetServiceObjects returns the objects recorded for a service, none if the
+// service was applied before the objects were recorded.
+func GetServiceObjects(service *epplugins.Component) ([]*ReadyResource, error) {
+	objects := []*ReadyResource{}
+	for _, r := range service.Resources {
+		if r.Name == ServiceObjectsName {
+			if err := json.Unmarshal([]byte(r.Value), &objects); err != nil {
+				log.Errorf("Failed to load the objects of service %s: %v", service.Name, err)
+				return nil, err
+			}
+		}
+	}
+	return objects, nil
+}
+
+// ImageStatus is an image of the containers of a service, with the digest
+// of the image the containers run.
+type ImageStatus struct {
+	Image  string `json:"image"`
+	Digest string `json:"digest,omitempty"`
+}
+
+// PodHealth is the health of the pods of a service.
+type PodHealth struct {
+	Ready    int           `json:"ready"`
+	Total    int           `json:"total"`
+	Restarts int64         `json:"restarts"`
+	Images   []ImageStatus `json:"images,omitempty"`
+}
+
+// imageDigest returns the digest of an image ID of a container status, like
+// "docker-pullable://nginx@sha256:..." or "sha256:...".
+func imageDigest(imageID string) string {
+	if i := strings.LastIndex(imageID, "@"); i >= 0 {
+		return imageID[i+1:]
+	}
+	if i := strings.Index(imageID, "sha256:"); i >= 0 {
+		return imageID[i:]
+	}
+	return ""
+}
+
+// podSelector returns the label selector of the pods of a workload, empty if
+// the object is not a workload.
+func podSelector(obj *unstructured.Unstructured) (string, error) {
+	m, found, err := unstructured.NestedMap(obj.Object, "spec", "selector")
+	if err != nil || !found {
+		return "", err
+	}
+	selector := &metav1.LabelSelector{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, selector); err != nil {
+		return "", err
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return "", err
+	}
+	return s.String(), nil
+}
+
+// pods returns the pods of an object, the object itself if it is a pod, and
+// the pods selected by the object if it is a workload.
+func (c *ReadinessChecker) pods(r *ReadyResource) ([]unstructured.Unstructured, error) {
+	client, err := c.resourceClient(r)
+	if meta.IsNoMatchError(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	obj, err := client.Get(context.Background(), r.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if obj.GetAPIVersion() == "v1" && obj.GetKind() == "Pod" {
+		return []unstructured.Unstructured{*obj}, nil
+	}
+	if gk := obj.GroupVersionKind().GroupKind(); gk.Group != "apps" && gk.Group != "batch" {
+		return nil, nil
+	}
+	selector, err := podSelector(obj)
+	if err != nil || len(selector) == 0 {
+		return nil, err
+	}
+	list, err := c.Client.Resource(podResource).Namespace(obj.GetNamespace()).List(
+		context.Background(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// podReady tells whether a pod is ready, or completed for the pods of jobs.
+func podReady(pod *unstructured.Unstructured) bool {
+	if phase, _, _ := unstructured.NestedString(pod.Object, "status", "phase"); phase == "Succeeded" {
+		return true
+	}
+	return conditionTrue(pod, "Ready")
+}
+
+// PodHealth reads the pods of the objects of a service from the cluster.
+// The objects which are not found are skipped.
+func (c *ReadinessChecker) PodHealth(objects []*ReadyResource) (*PodHealth, error) {
+	health := &PodHealth{}
+	seen := map[string]bool{}
+	images := map[string]string{}
+	for _, r := range objects {
+		pods, err := c.pods(r)
+		if err != nil {
+			return nil, err
+		}
+		for i := range pods {
+			pod := &pods[i]
+			key := pod.GetNamespace() + "/" + pod.GetName()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			health.Total++
+			if podReady(pod) {
+				health.Ready++
+			}
+			statuses, _, _ := unstructured.NestedSlice(pod.Object, "status", "containerStatuses")
+			for _, s := range statuses {
+				status, ok := s.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				restarts, _, _ := unstructured.NestedInt64(status, "restartCount")
+				health.Restarts += restarts
+				image, _, _ := unstructured.NestedString(status, "image")
+				imageID, _, _ := unstructured.NestedString(status, "imageID")
+				if digest := imageDigest(imageID); len(digest) > 0 || len(images[image]) == 0 {
+					images[image] = digest
+				}
+			}
+		}
+	}
+	for image, digest := range images {
+		health.Images = append(health.Images, ImageStatus{Image: image, Digest: digest})
+	}
+	sort.Slice(health.Images, func(i, j int) bool { return health.Images[i].Image < health.Images[j].Image })
+	return health, nil
+}
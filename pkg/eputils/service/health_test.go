@@ -0,0 +1,95 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package service
+
+import (
+	"testing"
+
+	epplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newPod(name, phase string, ready bool, restarts int64, image, imageID string) map[string]interface{} {
+	status := "False"
+	if ready {
+		status = "True"
+	}
+	return map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{"app": "web"},
+		},
+		"status": map[string]interface{}{
+			"phase": phase,
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": status},
+			},
+			"containerStatuses": []interface{}{
+				map[string]interface{}{"name": name, "image": image, "imageID": imageID, "restartCount": restarts},
+			},
+		},
+	}
+}
+
+func TestServiceObjects(t *testing.T) {
+	service := &epplugins.Component{Name: "web"}
+	objects, err := GetServiceObjects(service)
+	require.NoError(t, err)
+	require.Empty(t, objects)
+
+	cm := &ReadyResource{APIVersion: "v1", Kind: "ConfigMap", Name: "cm", Namespace: "ns"}
+	require.NoError(t, SetServiceObjects(service, []*ReadyResource{cm}))
+	require.NoError(t, SetServiceObjects(service, []*ReadyResource{cm, {APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Namespace: "ns"}}))
+	require.Len(t, service.Resources, 1)
+
+	objects, err = GetServiceObjects(service)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	require.Equal(t, cm, objects[0])
+
+	service.Resources[0].Value = "{"
+	_, err = GetServiceObjects(service)
+	require.Error(t, err)
+}
+
+func TestPodHealth(t *testing.T) {
+	selector := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{"app": "web"},
+			},
+		},
+	}
+	c := newChecker(
+		newObject("apps/v1", "Deployment", "ns", "web", selector),
+		newObject("v1", "Pod", "ns", "web-1", newPod("web", "Running", true, 2, "nginx:1.21", "docker-pullable://nginx@sha256:1111")),
+		newObject("v1", "Pod", "ns", "web-2", newPod("web", "Running", false, 3, "nginx:1.21", "docker-pullable://nginx@sha256:1111")),
+		newObject("v1", "Pod", "ns", "job-1", newPod("job", "Succeeded", false, 0, "busybox", "sha256:2222")),
+		newObject("v1", "Pod", "other", "web-3", newPod("web", "Running", true, 5, "nginx:1.21", "")),
+		newObject("v1", "ConfigMap", "ns", "cm", nil),
+	)
+
+	health, err := c.PodHealth([]*ReadyResource{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Namespace: "ns"},
+		{APIVersion: "v1", Kind: "Pod", Name: "web-1", Namespace: "ns"},
+		{APIVersion: "v1", Kind: "Pod", Name: "job-1", Namespace: "ns"},
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "cm", Namespace: "ns"},
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "missing", Namespace: "ns"},
+		{APIVersion: "example.com/v1", Kind: "Unknown", Name: "u", Namespace: "ns"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &PodHealth{
+		Ready:    2,
+		Total:    3,
+		Restarts: 5,
+		Images: []ImageStatus{
+			{Image: "busybox", Digest: "sha256:2222"},
+			{Image: "nginx:1.21", Digest: "sha256:1111"},
+		},
+	}, health)
+}
@@ -9,6 +9,7 @@ package service
 import (
 	"bytes"
 	"fmt"
+	epplugins "github.com/intel/edge-conductor/pkg/api/plugins"
 	log "github.com/sirupsen/logrus"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
@@ -35,8 +36,6 @@ const (
 	HELM_STATUS_UNKNOWN      = "Unknown"
 )
 
-var gActionConfig *action.Configuration
-
 // HelmDeployer: Class for Helm deployment.
 //   LocCharts:  Location of the charts. Can be a local file or a remote URL.
 //   LocValues:  Location of the value file. Can be a local file or a remote URL.
@@ -59,28 +58,26 @@ func NewHelmDeployer(name, namespace, charts, values string) HelmDeployerWrapper
 	}
 }
 
-// initHelm to initialize helm actionConfig.
-func initHelm(kubeconfig, namespace string) error {
-	gActionConfig = nil
-
+// initHelm returns a new helm action configuration for the namespace.
+func initHelm(kubeconfig, namespace string) (*action.Configuration, error) {
 	// Get kubeconfig
 	kubeconfig_abs, err := filepath.Abs(kubeconfig)
 	if err != nil {
 		log.Errorln("ERROR: Failed to find kubeconfig.", err)
-		return err
+		return nil, err
 	}
 	// Init action configuration
-	gActionConfig = new(action.Configuration)
-	if err := gActionConfig.Init(
+	actionConfig := new(action.Configuration)
+	if err := actionConfig.Init(
 		kube.GetConfig(kubeconfig_abs, "", namespace),
 		namespace,
 		os.Getenv("HELM_DRIVER"),
 		func(format string, v ...interface{}) {
 			fmt.Printf(format, v)
 		}); err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return actionConfig, nil
 }
 
 // readFile to load a remote file with a url, or a file from the local directory.
@@ -163,13 +160,14 @@ func (h *HelmDeployer) GetName() string {
 //   Revision:       int
 func (h *HelmDeployer) HelmStatus(loc_kubeconfig string) (string, int) {
 	// Init Helm Configurations
-	if err := initHelm(loc_kubeconfig, h.Namespace); err != nil {
+	actionConfig, err := initHelm(loc_kubeconfig, h.Namespace)
+	if err != nil {
 		log.Errorln("Failed to init Helm Configuration:", err)
 		return HELM_STATUS_UNKNOWN, 0
 	}
 
 	// New Client
-	helmcli := action.NewStatus(gActionConfig)
+	helmcli := action.NewStatus(actionConfig)
 	res, err := helmcli.Run(h.Name)
 	if err != nil {
 		if err.Error() == "release: not found" {
@@ -187,9 +185,27 @@ func (h *HelmDeployer) HelmStatus(loc_kubeconfig string) (string, int) {
 	}
 }
 
+// HelmRelease returns the release of the service, nil if it is not found.
+func (h *HelmDeployer) HelmRelease(loc_kubeconfig string) (*release.Release, error) {
+	actionConfig, err := initHelm(loc_kubeconfig, h.Namespace)
+	if err != nil {
+		log.Errorln("Failed to init Helm Configuration:", err)
+		return nil, err
+	}
+	rel, err := action.NewStatus(actionConfig).Run(h.Name)
+	if err != nil {
+		if err.Error() == "release: not found" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return rel, nil
+}
+
 type installConfig struct {
-	wait    bool
-	timeout int
+	wait       bool
+	timeout    int
+	conditions []*epplugins.ComponentWaitConditionsItems0
 }
 
 type InstallOpt func(*installConfig)
@@ -202,6 +218,21 @@ func WithWaitAndTimeout(wait bool, timeout int) InstallOpt {
 	}
 }
 
+// WithWaitConditions provides the custom conditions to wait for
+func WithWaitConditions(conds []*epplugins.ComponentWaitConditionsItems0) InstallOpt {
+	return func(opt *installConfig) {
+		opt.conditions = conds
+	}
+}
+
+// waitRelease waits for the resources of a release to be ready.
+func (h *HelmDeployer) waitRelease(loc_kubeconfig string, rel *release.Release, conf *installConfig) error {
+	if !conf.wait || rel == nil {
+		return nil
+	}
+	return waitManifest(loc_kubeconfig, []byte(rel.Manifest), h.Namespace, conf.conditions, int64(conf.timeout))
+}
+
 // HelmInstall: Install the helm charts described by the HelmDeployer
 //
 // Parameters:
@@ -230,13 +261,14 @@ func (h *HelmDeployer) HelmInstall(loc_kubeconfig string, opts ...InstallOpt) er
 	}
 
 	// Init Helm Configurations
-	if err := initHelm(loc_kubeconfig, h.Namespace); err != nil {
+	actionConfig, err := initHelm(loc_kubeconfig, h.Namespace)
+	if err != nil {
 		log.Errorln("Failed to init Helm Configuration:", err)
 		return err
 	}
 
 	// New Install Client
-	helmcli := action.NewInstall(gActionConfig)
+	helmcli := action.NewInstall(actionConfig)
 
 	// Load Chart
 	chartpos, err := helmcli.ChartPathOptions.LocateChart(
@@ -276,13 +308,16 @@ func (h *HelmDeployer) HelmInstall(loc_kubeconfig string, opts ...InstallOpt) er
 	helmcli.Namespace = h.Namespace
 	helmcli.ReleaseName = h.Name
 	helmcli.Timeout = time.Duration(conf.timeout) * time.Second
-	helmcli.Wait = conf.wait
 
 	rel, err := helmcli.Run(ch, values)
 	if err != nil {
 		log.Errorln("Failed to run Helm install:", err)
 		return err
 	}
+	if err := h.waitRelease(loc_kubeconfig, rel, &conf); err != nil {
+		log.Errorln("Failed to wait for Helm release:", err)
+		return err
+	}
 	log.Infoln("")
 	log.Infoln("Successfully installed release: ", rel.Name)
 	return nil
@@ -293,10 +328,15 @@ func (h *HelmDeployer) HelmInstall(loc_kubeconfig string, opts ...InstallOpt) er
 // Parameters:
 //   loc_kubeconfig:  Location of the kubeconfig file.
 //
-func (h *HelmDeployer) HelmUpgrade(loc_kubeconfig string) error {
+func (h *HelmDeployer) HelmUpgrade(loc_kubeconfig string, opts ...InstallOpt) error {
 	log.Infoln("Helm Upgrade:", h.Name)
 	log.Infoln("       Chart:", h.LocCharts)
 
+	var conf installConfig
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
 	// Get values
 	var values map[string]interface{}
 	values = nil
@@ -312,13 +352,14 @@ func (h *HelmDeployer) HelmUpgrade(loc_kubeconfig string) error {
 	}
 
 	// Init Helm Configurations
-	if err := initHelm(loc_kubeconfig, h.Namespace); err != nil {
+	actionConfig, err := initHelm(loc_kubeconfig, h.Namespace)
+	if err != nil {
 		log.Errorln("Failed to init Helm Configuration:", err)
 		return err
 	}
 
 	// New Upgrade Client
-	helmcli := action.NewUpgrade(gActionConfig)
+	helmcli := action.NewUpgrade(actionConfig)
 
 	// Load Chart
 	chartpos, err := helmcli.ChartPathOptions.LocateChart(
@@ -337,16 +378,68 @@ func (h *HelmDeployer) HelmUpgrade(loc_kubeconfig string) error {
 	}
 
 	helmcli.Namespace = h.Namespace
+	helmcli.Timeout = time.Duration(conf.timeout) * time.Second
 	rel, err := helmcli.Run(h.Name, ch, values)
 	if err != nil {
 		log.Errorln("Failed to run Helm upgrade:", err)
 		return err
 	}
+	if err := h.waitRelease(loc_kubeconfig, rel, &conf); err != nil {
+		log.Errorln("Failed to wait for Helm release:", err)
+		return err
+	}
 	log.Infoln("")
 	log.Infoln("Successfully upgraded release: ", rel.Name)
 	return nil
 }
 
+// HelmRollback: Roll back the release described by the HelmDeployer to an
+// earlier revision, which creates a new revision of the release.
+//
+// Parameters:
+//   loc_kubeconfig:  Location of the kubeconfig file.
+//   revision:        Revision to roll back to, 0 for the previous one.
+//
+func (h *HelmDeployer) HelmRollback(loc_kubeconfig string, revision int, opts ...InstallOpt) error {
+	log.Infoln("Helm Rollback:", h.Name)
+	log.Infoln("    Revision:", revision)
+
+	var conf installConfig
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	// Init Helm Configurations
+	actionConfig, err := initHelm(loc_kubeconfig, h.Namespace)
+	if err != nil {
+		log.Errorln("Failed to init Helm Configuration:", err)
+		return err
+	}
+
+	// New Rollback Client
+	helmcli := action.NewRollback(actionConfig)
+	helmcli.Version = revision
+	helmcli.Timeout = time.Duration(conf.timeout) * time.Second
+	if err := helmcli.Run(h.Name); err != nil {
+		log.Errorln("Failed to run Helm rollback:", err)
+		return err
+	}
+	if conf.wait {
+		rel, err := action.NewStatus(actionConfig).Run(h.Name)
+		if err != nil {
+			log.Errorln("Failed to get Helm release:", err)
+			return err
+		}
+		if err := h.waitRelease(loc_kubeconfig, rel, &conf); err != nil {
+			log.Errorln("Failed to wait for Helm release:", err)
+			return err
+		}
+	}
+	log.Infoln("")
+	log.Infoln("Successfully rolled back release: ", h.Name)
+	return nil
+}
+
 // HelmUninstall: Uninstall the helm charts described by the HelmDeployer
 //
 // Parameters:
@@ -356,13 +449,14 @@ func (h *HelmDeployer) HelmUninstall(loc_kubeconfig string) error {
 	log.Infoln("Helm Uninstall:", h.Name)
 
 	// Init Helm Configurations
-	if err := initHelm(loc_kubeconfig, h.Namespace); err != nil {
+	actionConfig, err := initHelm(loc_kubeconfig, h.Namespace)
+	if err != nil {
 		log.Errorln("Failed to init Helm Configuration:", err)
 		return err
 	}
 
 	// New Client
-	helmcli := action.NewUninstall(gActionConfig)
+	helmcli := action.NewUninstall(actionConfig)
 
 	res, err := helmcli.Run(h.Name)
 	if err != nil {
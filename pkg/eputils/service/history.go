@@ -0,0 +1,122 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package service
+
+import (
+	"reflect"
+	"strconv"
+
+	epplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	kubeutils "github.com/intel/edge-conductor/pkg/eputils/kubeutils"
+	log "github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+)
+
+// ServiceHistoryLimit is the number of applied revisions kept for each
+// service in the service ConfigMap.
+var ServiceHistoryLimit = 10
+
+// The service ConfigMap keeps the latest applied service in Data[<name>],
+// and the history of the applied revisions of the service, oldest first, in
+// BinaryData[<name>], so the readers of Data are not affected.
+
+// GetServiceHistory returns the applied revisions of a service recorded in
+// the service ConfigMap, oldest first. A service recorded before the history
+// was kept has only its latest revision.
+func GetServiceHistory(configMap kubeutils.ConfigMapWrapper, name string) ([]*epplugins.Component, error) {
+	history := []*epplugins.Component{}
+	if data, ok := configMap.GetBinaryData()[name]; ok {
+		if err := yaml.Unmarshal(data, &history); err != nil {
+			log.Errorf("Failed to load the history of service %s: %v", name, err)
+			return nil, err
+		}
+		return history, nil
+	}
+	if yml, ok := configMap.GetData()[name]; ok {
+		service := &epplugins.Component{}
+		if err := eputils.LoadSchemaStructFromYaml(service, yml); err != nil {
+			log.Errorln("Failed to load service ConfigMap:", err)
+			return nil, err
+		}
+		history = append(history, service)
+	}
+	return history, nil
+}
+
+// sameRevision tells whether two applied yaml services are the same, not
+// taking the revision numbers into account.
+func sameRevision(a, b *epplugins.Component) bool {
+	ac, bc := *a, *b
+	ac.Revision, bc.Revision = "", ""
+	return reflect.DeepEqual(&ac, &bc)
+}
+
+// RecordService records a service as the latest applied one in the service
+// ConfigMap and adds it to its history, keeping the last ServiceHistoryLimit
+// revisions. Helm services have the revision of their release. Other
+// services are numbered from 1, and a service applied again without change
+// keeps its revision.
+func RecordService(configMap kubeutils.ConfigMapWrapper, service *epplugins.Component) error {
+	history, err := GetServiceHistory(configMap, service.Name)
+	if err != nil {
+		return err
+	}
+	if service.Type != "helm" {
+		service.Revision = "1"
+		if len(history) > 0 {
+			last := history[len(history)-1]
+			if sameRevision(last, service) {
+				service.Revision = last.Revision
+				history = history[:len(history)-1]
+			} else if rev, err := strconv.Atoi(last.Revision); err == nil {
+				service.Revision = strconv.Itoa(rev + 1)
+			}
+		}
+	}
+	history = append(history, service)
+	if len(history) > ServiceHistoryLimit {
+		history = history[len(history)-ServiceHistoryLimit:]
+	}
+
+	data, err := eputils.SchemaStructToYaml(service)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+	historyData, err := yaml.Marshal(history)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+	if err := configMap.RenewData(service.Name, data); err != nil {
+		log.Errorln(err)
+		return err
+	}
+	if err := configMap.RenewBinaryData(service.Name, historyData); err != nil {
+		log.Errorln(err)
+		return err
+	}
+	return nil
+}
+
+// RemoveService removes a service and its history from the service
+// ConfigMap.
+func RemoveService(configMap kubeutils.ConfigMapWrapper, name string) error {
+	if err := configMap.RemoveData(name); err != nil {
+		log.Errorln(err)
+		return err
+	}
+	if _, ok := configMap.GetBinaryData()[name]; ok {
+		if err := configMap.RemoveBinaryData(name); err != nil {
+			log.Errorln(err)
+			return err
+		}
+	}
+	return nil
+}
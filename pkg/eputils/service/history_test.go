@@ -0,0 +1,80 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package service
+
+import (
+	"testing"
+
+	epplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	kubeutils "github.com/intel/edge-conductor/pkg/eputils/kubeutils"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newConfigMap(t *testing.T) *kubeutils.ConfigMap {
+	configMap := &kubeutils.ConfigMap{
+		Namespace: "ns",
+		Name:      "cm",
+		Client:    fake.NewSimpleClientset().CoreV1(),
+	}
+	require.NoError(t, configMap.New())
+	return configMap
+}
+
+func revisions(t *testing.T, configMap kubeutils.ConfigMapWrapper, name string) []string {
+	history, err := GetServiceHistory(configMap, name)
+	require.NoError(t, err)
+	revs := []string{}
+	for _, s := range history {
+		revs = append(revs, s.Revision)
+	}
+	return revs
+}
+
+func TestRecordService(t *testing.T) {
+	limit := ServiceHistoryLimit
+	ServiceHistoryLimit = 3
+	defer func() { ServiceHistoryLimit = limit }()
+
+	configMap := newConfigMap(t)
+	require.Empty(t, revisions(t, configMap, "yaml"))
+
+	for _, url := range []string{"file://v1", "file://v2", "file://v2", "file://v3", "file://v4"} {
+		require.NoError(t, RecordService(configMap, &epplugins.Component{Name: "yaml", Type: "yaml", URL: url}))
+	}
+	require.Equal(t, []string{"2", "3", "4"}, revisions(t, configMap, "yaml"), "unchanged service keeps its revision")
+	latest := &epplugins.Component{}
+	require.NoError(t, eputils.LoadSchemaStructFromYaml(latest, configMap.GetData()["yaml"]))
+	require.Equal(t, "file://v4", latest.URL)
+	require.Equal(t, "4", latest.Revision)
+
+	for _, rev := range []string{"1", "2"} {
+		require.NoError(t, RecordService(configMap, &epplugins.Component{Name: "helm", Type: "helm", Revision: rev}))
+	}
+	require.Equal(t, []string{"1", "2"}, revisions(t, configMap, "helm"))
+
+	require.NoError(t, RemoveService(configMap, "yaml"))
+	require.Empty(t, revisions(t, configMap, "yaml"))
+	require.Equal(t, []string{"1", "2"}, revisions(t, configMap, "helm"))
+}
+
+func TestGetServiceHistory(t *testing.T) {
+	configMap := newConfigMap(t)
+
+	// A service recorded before the history was kept.
+	data, err := eputils.SchemaStructToYaml(&epplugins.Component{Name: "old", Type: "helm", Revision: "5"})
+	require.NoError(t, err)
+	require.NoError(t, configMap.RenewData("old", data))
+	require.Equal(t, []string{"5"}, revisions(t, configMap, "old"))
+
+	require.NoError(t, configMap.RenewBinaryData("broken", []byte("- [a")))
+	_, err = GetServiceHistory(configMap, "broken")
+	require.Error(t, err)
+}
@@ -11,6 +11,7 @@ package service
 
 import (
 	epplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	"helm.sh/helm/v3/pkg/release"
 )
 
 type ServiceDeployer interface {
@@ -21,8 +22,10 @@ type ServiceDeployer interface {
 type HelmDeployerWrapper interface {
 	GetName() string
 	HelmStatus(loc_kubeconfig string) (string, int)
+	HelmRelease(loc_kubeconfig string) (*release.Release, error)
 	HelmInstall(loc_kubeconfig string, arg ...InstallOpt) error
-	HelmUpgrade(loc_kubeconfig string) error
+	HelmUpgrade(loc_kubeconfig string, arg ...InstallOpt) error
+	HelmRollback(loc_kubeconfig string, revision int, arg ...InstallOpt) error
 	HelmUninstall(loc_kubeconfig string) error
 }
 
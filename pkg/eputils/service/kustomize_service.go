@@ -0,0 +1,72 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package service
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	log "github.com/sirupsen/logrus"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// kustomizeRoot returns the root of an extracted kustomize archive. An
+// archive with a single top folder, like the archive of a directory, is
+// rooted in that folder.
+func kustomizeRoot(dir string) (string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(dir, entries[0].Name()), nil
+	}
+	return dir, nil
+}
+
+// KustomizeBuild renders the overlay of a tar.gz archive of kustomize bases
+// and overlays into a yaml file. The overlay is a path in the archive, the
+// root of the archive when it is empty.
+func KustomizeBuild(archive, overlay, targetFile string) error {
+	workDir, err := ioutil.TempDir(filepath.Dir(targetFile), "kustomize-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(workDir)
+
+	if err := eputils.UncompressTgz(archive, filepath.Join(workDir, "src")); err != nil {
+		log.Errorln("Failed to extract kustomize archive", archive, err)
+		return err
+	}
+	root, err := kustomizeRoot(filepath.Join(workDir, "src"))
+	if err != nil {
+		return err
+	}
+	// The overlay is cleaned as an absolute path to stay in the archive.
+	dir := filepath.Join(root, filepath.Clean("/"+overlay))
+	if !eputils.IsDirectory(dir) {
+		log.Errorf("Kustomize overlay %q is not found in %s", overlay, archive)
+		return eputils.GetError("errKustomizeOverlay")
+	}
+
+	log.Infoln("Kustomize Build:", overlay)
+	k := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+	resMap, err := k.Run(filesys.MakeFsOnDisk(), dir)
+	if err != nil {
+		log.Errorln("Failed to build kustomize overlay", overlay, err)
+		return err
+	}
+	manifest, err := resMap.AsYaml()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(targetFile, manifest, 0600)
+}
@@ -0,0 +1,62 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package service
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newKustomizeArchive creates the tar.gz archive of a directory of files.
+func newKustomizeArchive(t *testing.T, files map[string]string) string {
+	dir := filepath.Join(t.TempDir(), "site")
+	for name, content := range files {
+		file := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0700))
+		require.NoError(t, ioutil.WriteFile(file, []byte(content), 0600))
+	}
+	tarFile := filepath.Join(t.TempDir(), "site.tar")
+	require.NoError(t, eputils.CompressTar(dir, tarFile, 0600))
+	require.NoError(t, eputils.GzipCompress(tarFile, filepath.Dir(tarFile)))
+	return tarFile + ".gz"
+}
+
+func TestKustomizeBuild(t *testing.T) {
+	archive := newKustomizeArchive(t, map[string]string{
+		"base/kustomization.yaml": "resources:\n- configmap.yaml\n",
+		"base/configmap.yaml":     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  site: base\n",
+		"overlays/edge/kustomization.yaml": "resources:\n- ../../base\nnamePrefix: edge-\n" +
+			"patchesStrategicMerge:\n- configmap.yaml\n",
+		"overlays/edge/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  site: edge\n",
+	})
+	targetFile := filepath.Join(t.TempDir(), "service.yml")
+
+	require.NoError(t, KustomizeBuild(archive, "overlays/edge", targetFile))
+	manifest, err := ioutil.ReadFile(targetFile)
+	require.NoError(t, err)
+	require.Equal(t, "apiVersion: v1\ndata:\n  site: edge\nkind: ConfigMap\nmetadata:\n  name: edge-config\n", string(manifest))
+	resources, err := ResourcesFromManifest(manifest, "ns")
+	require.NoError(t, err)
+	require.Equal(t, []*ReadyResource{{APIVersion: "v1", Kind: "ConfigMap", Name: "edge-config", Namespace: "ns"}}, resources)
+
+	require.NoError(t, KustomizeBuild(archive, "base", targetFile))
+	manifest, err = ioutil.ReadFile(targetFile)
+	require.NoError(t, err)
+	require.Contains(t, string(manifest), "name: config\n")
+
+	require.Equal(t, eputils.GetError("errKustomizeOverlay"), KustomizeBuild(archive, "overlays/missing", targetFile))
+	require.Equal(t, eputils.GetError("errKustomizeOverlay"), KustomizeBuild(archive, "../../etc", targetFile), "out of the archive")
+	require.Error(t, KustomizeBuild(archive, "", targetFile), "no kustomization at the root")
+	require.Error(t, KustomizeBuild(filepath.Join(t.TempDir(), "missing.tar.gz"), "base", targetFile))
+}
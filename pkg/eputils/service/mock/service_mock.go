@@ -18,6 +18,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	plugins "github.com/intel/edge-conductor/pkg/api/plugins"
 	service "github.com/intel/edge-conductor/pkg/eputils/service"
+	release "helm.sh/helm/v3/pkg/release"
 )
 
 // MockServiceDeployer is a mock of ServiceDeployer interface.
@@ -132,6 +133,40 @@ func (mr *MockHelmDeployerWrapperMockRecorder) HelmInstall(arg0 interface{}, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HelmInstall", reflect.TypeOf((*MockHelmDeployerWrapper)(nil).HelmInstall), varargs...)
 }
 
+// HelmRelease mocks base method.
+func (m *MockHelmDeployerWrapper) HelmRelease(arg0 string) (*release.Release, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HelmRelease", arg0)
+	ret0, _ := ret[0].(*release.Release)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HelmRelease indicates an expected call of HelmRelease.
+func (mr *MockHelmDeployerWrapperMockRecorder) HelmRelease(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HelmRelease", reflect.TypeOf((*MockHelmDeployerWrapper)(nil).HelmRelease), arg0)
+}
+
+// HelmRollback mocks base method.
+func (m *MockHelmDeployerWrapper) HelmRollback(arg0 string, arg1 int, arg2 ...service.InstallOpt) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "HelmRollback", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HelmRollback indicates an expected call of HelmRollback.
+func (mr *MockHelmDeployerWrapperMockRecorder) HelmRollback(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HelmRollback", reflect.TypeOf((*MockHelmDeployerWrapper)(nil).HelmRollback), varargs...)
+}
+
 // HelmStatus mocks base method.
 func (m *MockHelmDeployerWrapper) HelmStatus(arg0 string) (string, int) {
 	m.ctrl.T.Helper()
@@ -162,17 +197,22 @@ func (mr *MockHelmDeployerWrapperMockRecorder) HelmUninstall(arg0 interface{}) *
 }
 
 // HelmUpgrade mocks base method.
-func (m *MockHelmDeployerWrapper) HelmUpgrade(arg0 string) error {
+func (m *MockHelmDeployerWrapper) HelmUpgrade(arg0 string, arg1 ...service.InstallOpt) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "HelmUpgrade", arg0)
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "HelmUpgrade", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // HelmUpgrade indicates an expected call of HelmUpgrade.
-func (mr *MockHelmDeployerWrapperMockRecorder) HelmUpgrade(arg0 interface{}) *gomock.Call {
+func (mr *MockHelmDeployerWrapperMockRecorder) HelmUpgrade(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HelmUpgrade", reflect.TypeOf((*MockHelmDeployerWrapper)(nil).HelmUpgrade), arg0)
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HelmUpgrade", reflect.TypeOf((*MockHelmDeployerWrapper)(nil).HelmUpgrade), varargs...)
 }
 
 // MockYamlDeployerWrapper is a mock of YamlDeployerWrapper interface.
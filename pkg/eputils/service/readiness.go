@@ -0,0 +1,375 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	epplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	kubeutils "github.com/intel/edge-conductor/pkg/eputils/kubeutils"
+	log "github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+)
+
+// ReadinessInterval is the time between two checks of the resources.
+var ReadinessInterval = 2 * time.Second
+
+// ReadyResource is a Kubernetes resource to wait for.
+//   Condition:  Go template expression evaluated against the resource,
+//               e.g. `eq .status.phase "Running"`. When it is set it is
+//               used instead of the built-in check of the kind.
+type ReadyResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+	Condition  string `json:"condition,omitempty"`
+}
+
+func (r *ReadyResource) String() string {
+	if len(r.Namespace) > 0 {
+		return fmt.Sprintf("%s %s/%s", r.Kind, r.Namespace, r.Name)
+	}
+	return fmt.Sprintf("%s %s", r.Kind, r.Name)
+}
+
+// ReadinessChecker waits for Kubernetes resources to be ready, with the
+// dynamic client, so any kind known by the cluster can be checked.
+type ReadinessChecker struct {
+	Client dynamic.Interface
+	Mapper meta.RESTMapper
+}
+
+func NewReadinessChecker(kubeconfig string) (*ReadinessChecker, error) {
+	restconfig, err := kubeutils.RestConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	client, err := dynamic.NewForConfig(restconfig)
+	if err != nil {
+		return nil, err
+	}
+	dc, err := discovery.NewDiscoveryClientForConfig(restconfig)
+	if err != nil {
+		return nil, err
+	}
+	return &ReadinessChecker{
+		Client: client,
+		// The deferred mapper refreshes the discovery information when a
+		// kind is not found, so resources of CRDs applied in the same
+		// service can be found once the CRDs are established.
+		Mapper: restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc)),
+	}, nil
+}
+
+// ResourcesFromManifest returns the resources of a YAML manifest with one or
+// more documents. Resources without a namespace get the namespace given,
+// which is ignored later for cluster scoped kinds.
+func ResourcesFromManifest(manifest []byte, namespace string) ([]*ReadyResource, error) {
+	resources := []*ReadyResource{}
+	dec := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifest), 4096)
+	for {
+		obj := unstructured.Unstructured{}
+		if err := dec.Decode(&obj.Object); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		if len(obj.Object) == 0 || len(obj.GetKind()) == 0 {
+			continue
+		}
+		ns := obj.GetNamespace()
+		if len(ns) == 0 {
+			ns = namespace
+		}
+		resources = append(resources, &ReadyResource{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Name:       obj.GetName(),
+			Namespace:  ns,
+		})
+	}
+	return resources, nil
+}
+
+// AddConditions sets the wait conditions of a service on the resources of
+// the same kind and name, an empty name matching all the resources of the
+// kind. A condition on a resource which is not in the manifest, like one
+// created by an operator, is added as another resource to wait for, and
+// needs the apiVersion of the resource.
+func AddConditions(resources []*ReadyResource, conds []*epplugins.ComponentWaitConditionsItems0, namespace string) ([]*ReadyResource, error) {
+	for _, c := range conds {
+		if c == nil {
+			continue
+		}
+		if _, err := parseCondition(c.Condition); err != nil {
+			log.Errorf("Invalid condition %q of %s %s: %v", c.Condition, c.Kind, c.Name, err)
+			return nil, eputils.GetError("errReadyCondition")
+		}
+		found := false
+		for _, r := range resources {
+			if r.Kind == c.Kind && (len(c.Name) == 0 || r.Name == c.Name) &&
+				(len(c.Namespace) == 0 || r.Namespace == c.Namespace) {
+				r.Condition = c.Condition
+				found = true
+			}
+		}
+		if found {
+			continue
+		}
+		if len(c.APIVersion) == 0 || len(c.Kind) == 0 || len(c.Name) == 0 {
+			log.Errorf("Condition on %s %s is not in the manifest, it needs apiVersion, kind and name", c.Kind, c.Name)
+			return nil, eputils.GetError("errReadyCondition")
+		}
+		ns := c.Namespace
+		if len(ns) == 0 {
+			ns = namespace
+		}
+		resources = append(resources, &ReadyResource{
+			APIVersion: c.APIVersion,
+			Kind:       c.Kind,
+			Name:       c.Name,
+			Namespace:  ns,
+			Condition:  c.Condition,
+		})
+	}
+	return resources, nil
+}
+
+// WaitReady checks the resources until all of them are ready. It fails when
+// a resource failed, like a failed Job, or when some resources are still not
+// ready after the timeout, logging each of them with the reason.
+func (c *ReadinessChecker) WaitReady(resources []*ReadyResource, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	pending := resources
+	for {
+		notReady := []*ReadyResource{}
+		reasons := []string{}
+		for _, r := range pending {
+			ready, reason, err := c.checkReady(r)
+			if err != nil {
+				return err
+			}
+			if !ready {
+				notReady = append(notReady, r)
+				reasons = append(reasons, reason)
+			}
+		}
+		if len(notReady) == 0 {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			for k, r := range notReady {
+				log.Errorf("%s is not ready: %s", r, reasons[k])
+			}
+			return eputils.GetError("errResourceNotReady")
+		}
+		log.Debugf("Waiting for %d resources, %s: %s", len(notReady), notReady[0], reasons[0])
+		pending = notReady
+		time.Sleep(ReadinessInterval)
+	}
+}
+
+// resourceClient returns the client of the resource. The namespace of a
+// cluster scoped resource is cleared.
+func (c *ReadinessChecker) resourceClient(r *ReadyResource) (dynamic.ResourceInterface, error) {
+	gv, err := schema.ParseGroupVersion(r.APIVersion)
+	if err != nil {
+		log.Errorf("Invalid apiVersion %q of %s", r.APIVersion, r)
+		return nil, eputils.GetError("errReadyCondition")
+	}
+	mapping, err := c.Mapper.RESTMapping(gv.WithKind(r.Kind).GroupKind(), gv.Version)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		r.Namespace = ""
+		return c.Client.Resource(mapping.Resource), nil
+	}
+	return c.Client.Resource(mapping.Resource).Namespace(r.Namespace), nil
+}
+
+// checkReady gets the resource and tells whether it is ready, and if not,
+// why.
+func (c *ReadinessChecker) checkReady(r *ReadyResource) (bool, string, error) {
+	client, err := c.resourceClient(r)
+	if err == eputils.GetError("errReadyCondition") {
+		return false, "", err
+	} else if err != nil {
+		return false, err.Error(), nil
+	}
+	obj, err := client.Get(context.Background(), r.Name, metav1.GetOptions{})
+	if err != nil {
+		return false, err.Error(), nil
+	}
+	if len(r.Condition) > 0 {
+		return evalCondition(r, obj)
+	}
+	return isReady(obj)
+}
+
+// Exists tells whether the resource is on the cluster. A resource of a kind
+// which the cluster does not know, like one of a removed CRD, does not exist.
+func (c *ReadinessChecker) Exists(r *ReadyResource) (bool, error) {
+	client, err := c.resourceClient(r)
+	if meta.IsNoMatchError(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if _, err := client.Get(context.Background(), r.Name, metav1.GetOptions{}); apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func parseCondition(cond string) (*template.Template, error) {
+	return template.New("condition").Parse("{{ " + cond + " }}")
+}
+
+// evalCondition evaluates the condition of a resource. A condition which
+// cannot be evaluated yet, like one on a status which is not set, is taken
+// as not ready.
+func evalCondition(r *ReadyResource, obj *unstructured.Unstructured) (bool, string, error) {
+	tpl, err := parseCondition(r.Condition)
+	if err != nil {
+		log.Errorf("Invalid condition %q of %s: %v", r.Condition, r, err)
+		return false, "", eputils.GetError("errReadyCondition")
+	}
+	var b bytes.Buffer
+	if err := tpl.Execute(&b, obj.Object); err != nil {
+		return false, err.Error(), nil
+	}
+	ok, err := strconv.ParseBool(strings.TrimSpace(b.String()))
+	if err != nil {
+		log.Errorf("Condition %q of %s is %q, not true or false", r.Condition, r, b.String())
+		return false, "", eputils.GetError("errReadyCondition")
+	}
+	if !ok {
+		return false, fmt.Sprintf("condition %q is false", r.Condition), nil
+	}
+	return true, "", nil
+}
+
+// isReady is the built-in check of a resource. Kinds without status to
+// check are ready as soon as they exist.
+func isReady(obj *unstructured.Unstructured) (bool, string, error) {
+	switch obj.GroupVersionKind().GroupKind() {
+	case schema.GroupKind{Group: "apps", Kind: "Deployment"}:
+		return deploymentReady(obj)
+	case schema.GroupKind{Group: "apps", Kind: "StatefulSet"}:
+		return statefulSetReady(obj)
+	case schema.GroupKind{Group: "apps", Kind: "DaemonSet"}:
+		return daemonSetReady(obj)
+	case schema.GroupKind{Group: "batch", Kind: "Job"}:
+		if conditionTrue(obj, "Failed") {
+			log.Errorf("Job %s/%s failed", obj.GetNamespace(), obj.GetName())
+			return false, "", eputils.GetError("errResourceFailed")
+		}
+		if !conditionTrue(obj, "Complete") {
+			return false, "not complete", nil
+		}
+	case schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:
+		if !conditionTrue(obj, "Established") {
+			return false, "not established", nil
+		}
+	}
+	return true, "", nil
+}
+
+func statusInt(obj *unstructured.Unstructured, field string) int64 {
+	v, _, _ := unstructured.NestedInt64(obj.Object, "status", field)
+	return v
+}
+
+func specReplicas(obj *unstructured.Unstructured) int64 {
+	v, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if !found {
+		return 1
+	}
+	return v
+}
+
+func conditionTrue(obj *unstructured.Unstructured, condType string) bool {
+	conds, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conds {
+		cond, ok := c.(map[string]interface{})
+		if ok && cond["type"] == condType && cond["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
+
+func generationObserved(obj *unstructured.Unstructured) bool {
+	return obj.GetGeneration() <= statusInt(obj, "observedGeneration")
+}
+
+func deploymentReady(obj *unstructured.Unstructured) (bool, string, error) {
+	replicas := specReplicas(obj)
+	switch updated := statusInt(obj, "updatedReplicas"); {
+	case !generationObserved(obj):
+		return false, "spec update not observed", nil
+	case updated < replicas:
+		return false, fmt.Sprintf("%d of %d replicas updated", updated, replicas), nil
+	case statusInt(obj, "replicas") > updated:
+		return false, fmt.Sprintf("%d old replicas pending termination", statusInt(obj, "replicas")-updated), nil
+	case statusInt(obj, "availableReplicas") < updated:
+		return false, fmt.Sprintf("%d of %d updated replicas available", statusInt(obj, "availableReplicas"), updated), nil
+	}
+	return true, "", nil
+}
+
+func statefulSetReady(obj *unstructured.Unstructured) (bool, string, error) {
+	replicas := specReplicas(obj)
+	partition, _, _ := unstructured.NestedInt64(obj.Object, "spec", "updateStrategy", "rollingUpdate", "partition")
+	current, _, _ := unstructured.NestedString(obj.Object, "status", "currentRevision")
+	update, _, _ := unstructured.NestedString(obj.Object, "status", "updateRevision")
+	switch ready := statusInt(obj, "readyReplicas"); {
+	case !generationObserved(obj):
+		return false, "spec update not observed", nil
+	case ready < replicas:
+		return false, fmt.Sprintf("%d of %d replicas ready", ready, replicas), nil
+	case partition == 0 && current != update:
+		return false, fmt.Sprintf("revision %s not rolled out", update), nil
+	}
+	return true, "", nil
+}
+
+func daemonSetReady(obj *unstructured.Unstructured) (bool, string, error) {
+	desired := statusInt(obj, "desiredNumberScheduled")
+	switch {
+	case !generationObserved(obj):
+		return false, "spec update not observed", nil
+	case statusInt(obj, "updatedNumberScheduled") < desired:
+		return false, fmt.Sprintf("%d of %d pods updated", statusInt(obj, "updatedNumberScheduled"), desired), nil
+	case statusInt(obj, "numberAvailable") < desired:
+		return false, fmt.Sprintf("%d of %d pods available", statusInt(obj, "numberAvailable"), desired), nil
+	}
+	return true, "", nil
+}
@@ -0,0 +1,207 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package service
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	epplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newObject(apiVersion, kind, namespace, name string, fields map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: fields}
+	if obj.Object == nil {
+		obj.Object = map[string]interface{}{}
+	}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func newChecker(objs ...runtime.Object) *ReadinessChecker {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, gvk := range []schema.GroupVersionKind{
+		{Group: "apps", Version: "v1", Kind: "Deployment"},
+		{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+		{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+		{Group: "batch", Version: "v1", Kind: "Job"},
+		{Group: "", Version: "v1", Kind: "ConfigMap"},
+		{Group: "", Version: "v1", Kind: "Pod"},
+	} {
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+	mapper.Add(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}, meta.RESTScopeRoot)
+	return &ReadinessChecker{
+		Client: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), objs...),
+		Mapper: mapper,
+	}
+}
+
+func conditions(condType, status string) map[string]interface{} {
+	return map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": condType, "status": status},
+			},
+		},
+	}
+}
+
+func TestIsReady(t *testing.T) {
+	cases := []struct {
+		name  string
+		obj   *unstructured.Unstructured
+		ready bool
+		err   error
+	}{
+		{"deployment ready", newObject("apps/v1", "Deployment", "ns", "d", map[string]interface{}{
+			"spec":   map[string]interface{}{"replicas": int64(2)},
+			"status": map[string]interface{}{"replicas": int64(2), "updatedReplicas": int64(2), "availableReplicas": int64(2)},
+		}), true, nil},
+		{"deployment not available", newObject("apps/v1", "Deployment", "ns", "d", map[string]interface{}{
+			"status": map[string]interface{}{"replicas": int64(1), "updatedReplicas": int64(1)},
+		}), false, nil},
+		{"deployment old replicas", newObject("apps/v1", "Deployment", "ns", "d", map[string]interface{}{
+			"status": map[string]interface{}{"replicas": int64(2), "updatedReplicas": int64(1), "availableReplicas": int64(2)},
+		}), false, nil},
+		{"statefulset ready", newObject("apps/v1", "StatefulSet", "ns", "s", map[string]interface{}{
+			"spec":   map[string]interface{}{"replicas": int64(3)},
+			"status": map[string]interface{}{"readyReplicas": int64(3), "currentRevision": "r1", "updateRevision": "r1"},
+		}), true, nil},
+		{"statefulset rolling out", newObject("apps/v1", "StatefulSet", "ns", "s", map[string]interface{}{
+			"status": map[string]interface{}{"readyReplicas": int64(1), "currentRevision": "r1", "updateRevision": "r2"},
+		}), false, nil},
+		{"daemonset ready", newObject("apps/v1", "DaemonSet", "ns", "ds", map[string]interface{}{
+			"status": map[string]interface{}{"desiredNumberScheduled": int64(2), "updatedNumberScheduled": int64(2), "numberAvailable": int64(2)},
+		}), true, nil},
+		{"daemonset not available", newObject("apps/v1", "DaemonSet", "ns", "ds", map[string]interface{}{
+			"status": map[string]interface{}{"desiredNumberScheduled": int64(2), "updatedNumberScheduled": int64(2), "numberAvailable": int64(1)},
+		}), false, nil},
+		{"job complete", newObject("batch/v1", "Job", "ns", "j", conditions("Complete", "True")), true, nil},
+		{"job running", newObject("batch/v1", "Job", "ns", "j", nil), false, nil},
+		{"job failed", newObject("batch/v1", "Job", "ns", "j", conditions("Failed", "True")), false, eputils.GetError("errResourceFailed")},
+		{"crd established", newObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "", "c", conditions("Established", "True")), true, nil},
+		{"crd not established", newObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "", "c", conditions("Established", "False")), false, nil},
+		{"other kinds", newObject("v1", "ConfigMap", "ns", "cm", nil), true, nil},
+	}
+	for _, c := range cases {
+		ready, reason, err := isReady(c.obj)
+		require.Equal(t, c.err, err, c.name)
+		require.Equal(t, c.ready, ready, c.name)
+		if !ready && err == nil {
+			require.NotEmpty(t, reason, c.name)
+		}
+	}
+}
+
+func TestResourcesFromManifest(t *testing.T) {
+	resources, err := ResourcesFromManifest([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: d
+---
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: j
+  namespace: jobs
+`), "ns")
+	require.NoError(t, err)
+	require.Equal(t, []*ReadyResource{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "d", Namespace: "ns"},
+		{APIVersion: "batch/v1", Kind: "Job", Name: "j", Namespace: "jobs"},
+	}, resources)
+
+	_, err = ResourcesFromManifest([]byte("kind: [a"), "ns")
+	require.Error(t, err)
+}
+
+func TestAddConditions(t *testing.T) {
+	resources := []*ReadyResource{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "a", Namespace: "ns"},
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "b", Namespace: "ns"},
+	}
+	resources, err := AddConditions(resources, []*epplugins.ComponentWaitConditionsItems0{
+		{Kind: "Deployment", Condition: `eq .metadata.name "a"`},
+		{APIVersion: "cert-manager.io/v1", Kind: "Certificate", Name: "c", Condition: `eq .status.ready true`},
+	}, "ns")
+	require.NoError(t, err)
+	require.Len(t, resources, 3)
+	require.Equal(t, `eq .metadata.name "a"`, resources[1].Condition)
+	require.Equal(t, "Certificate ns/c", resources[2].String())
+
+	_, err = AddConditions(nil, []*epplugins.ComponentWaitConditionsItems0{{Kind: "Certificate", Name: "c", Condition: "true"}}, "ns")
+	require.Equal(t, eputils.GetError("errReadyCondition"), err, "no apiVersion")
+	_, err = AddConditions(resources, []*epplugins.ComponentWaitConditionsItems0{{Kind: "Deployment", Condition: "eq ("}}, "ns")
+	require.Equal(t, eputils.GetError("errReadyCondition"), err, "invalid expression")
+}
+
+func TestWaitReady(t *testing.T) {
+	interval := ReadinessInterval
+	ReadinessInterval = 10 * time.Millisecond
+	defer func() { ReadinessInterval = interval }()
+
+	c := newChecker(
+		newObject("batch/v1", "Job", "ns", "done", conditions("Complete", "True")),
+		newObject("batch/v1", "Job", "ns", "failed", conditions("Failed", "True")),
+		newObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "", "crd", conditions("Established", "True")),
+		newObject("v1", "ConfigMap", "ns", "cm", map[string]interface{}{"data": map[string]interface{}{"key": "value"}}),
+	)
+
+	crd := &ReadyResource{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "crd", Namespace: "ns"}
+	require.NoError(t, c.WaitReady([]*ReadyResource{
+		{APIVersion: "batch/v1", Kind: "Job", Name: "done", Namespace: "ns"},
+		crd,
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "cm", Namespace: "ns", Condition: `eq .data.key "value"`},
+	}, time.Second))
+	require.Empty(t, crd.Namespace, "cluster scoped")
+
+	exists, err := c.Exists(&ReadyResource{APIVersion: "v1", Kind: "ConfigMap", Name: "cm", Namespace: "ns"})
+	require.NoError(t, err)
+	require.True(t, exists)
+	exists, err = c.Exists(&ReadyResource{APIVersion: "v1", Kind: "ConfigMap", Name: "cm", Namespace: "other"})
+	require.NoError(t, err)
+	require.False(t, exists)
+	exists, err = c.Exists(&ReadyResource{APIVersion: "example.com/v1", Kind: "Unknown", Name: "u"})
+	require.NoError(t, err)
+	require.False(t, exists, "unknown kind")
+
+	require.Equal(t, eputils.GetError("errResourceFailed"), c.WaitReady([]*ReadyResource{
+		{APIVersion: "batch/v1", Kind: "Job", Name: "failed", Namespace: "ns"},
+	}, time.Second))
+
+	logs := &bytes.Buffer{}
+	log.SetOutput(logs)
+	defer log.SetOutput(os.Stdout)
+	require.Equal(t, eputils.GetError("errResourceNotReady"), c.WaitReady([]*ReadyResource{
+		{APIVersion: "batch/v1", Kind: "Job", Name: "done", Namespace: "ns"},
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "cm", Namespace: "ns", Condition: `eq .data.key "other"`},
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "missing", Namespace: "ns"},
+		{APIVersion: "example.com/v1", Kind: "Unknown", Name: "u", Namespace: "ns"},
+	}, 50*time.Millisecond))
+	require.NotContains(t, logs.String(), "Job ns/done")
+	require.Contains(t, logs.String(), "ConfigMap ns/cm is not ready")
+	require.Contains(t, logs.String(), "Deployment ns/missing is not ready")
+	require.Contains(t, logs.String(), "Unknown ns/u is not ready")
+}
@@ -102,6 +102,26 @@ func GenSvcTLSCertFromTLSExtension(exts []*epplugins.EpParamsExtensionsItems0, t
 	return nil
 }
 
+// SvcTLSCertNames returns the names of the cert bundles of the service-tls
+// extension for a service.
+func SvcTLSCertNames(exts []*epplugins.EpParamsExtensionsItems0, tgtSvc string) []string {
+	names := []string{}
+	for _, ext := range exts {
+		if ext.Name != SVCTLSEXT || ext.Extension == nil {
+			continue
+		}
+		for _, ext_svc := range ext.Extension.Extension {
+			for _, cfg_instance := range ext_svc.Config {
+				if cfg_instance.Name == SVCNAME && cfg_instance.Value == tgtSvc {
+					names = append(names, ext_svc.Name)
+					break
+				}
+			}
+		}
+	}
+	return names
+}
+
 func GenSvcSecretFromTLSExtension(exts []*epplugins.EpParamsExtensionsItems0, tgtSvc, ns, kubeconfig string) error {
 	for _, ext := range exts {
 		if ext.Name == SVCTLSEXT {
@@ -25,6 +25,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	mpatch "github.com/undefinedlabs/go-mpatch"
@@ -73,6 +74,21 @@ func TestYamlDeployer(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer unpatch(t, p)
+	waited := []*ReadyResource{}
+	p1, err := mpatch.PatchMethod(NewReadinessChecker, func(string) (*ReadinessChecker, error) { return &ReadinessChecker{}, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unpatch(t, p1)
+	p2, err := mpatch.PatchInstanceMethodByName(reflect.TypeOf(&ReadinessChecker{}), "WaitReady",
+		func(c *ReadinessChecker, resources []*ReadyResource, timeout time.Duration) error {
+			waited = resources
+			return nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unpatch(t, p2)
 	wait := &YamlWait{Timeout: 100}
 	yamlDeployer := NewYamlDeployer("test", "ns", "testdata/kind-nginx-ingress.yml", wait)
 	if "test" != yamlDeployer.GetName() {
@@ -81,6 +97,9 @@ func TestYamlDeployer(t *testing.T) {
 	if err := yamlDeployer.YamlInstall("kubeconfig"); err != nil {
 		t.Error("Unexpected error found.")
 	}
+	if len(waited) != 3 || waited[1].String() != "Job ns/fakeName" || waited[2].Kind != "Deployment" {
+		t.Error("Unexpected resources waited:", waited)
+	}
 	if err := yamlDeployer.YamlUninstall("kubeconfig"); err != nil {
 		t.Error("Unexpected error found.")
 	}
@@ -161,7 +180,7 @@ func TestHelmDeployer(t *testing.T) {
 
 	// General functions to run before test
 	func_err_initHelm := func() []*mpatch.Patch {
-		p1, err := mpatch.PatchMethod(initHelm, func(string, string) error { return kubeerr })
+		p1, err := mpatch.PatchMethod(initHelm, func(string, string) (*action.Configuration, error) { return nil, kubeerr })
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -544,7 +563,7 @@ func TestInitHelmError(t *testing.T) {
 	}
 	defer unpatch(t, p1)
 
-	if err := initHelm("testconfig", "testspace"); errors.Is(err, testerr) {
+	if _, err := initHelm("testconfig", "testspace"); errors.Is(err, testerr) {
 		t.Log("Error Expected")
 	} else {
 		t.Error("Expect", testerr, "but found", err)
@@ -939,3 +958,37 @@ func TestGenSvcSecretFromTLSExtension(t *testing.T) {
 		}
 	}
 }
+
+func TestSvcTLSCertNames(t *testing.T) {
+	svcExt := func(name, svc string) *epplugins.ExtensionItems0 {
+		return &epplugins.ExtensionItems0{
+			Name: name,
+			Config: []*epplugins.ExtensionItems0ConfigItems0{
+				{Name: SVCNAME, Value: svc},
+			},
+		}
+	}
+	exts := []*epplugins.EpParamsExtensionsItems0{
+		{Name: "other", Extension: &epplugins.Extension{Extension: []*epplugins.ExtensionItems0{svcExt("other-cert", "test_svc")}}},
+		{Name: SVCTLSEXT, Extension: nil},
+		{Name: SVCTLSEXT, Extension: &epplugins.Extension{Extension: []*epplugins.ExtensionItems0{
+			svcExt("test-cert", "test_svc"),
+			svcExt("another-cert", "another_svc"),
+			{Name: "nocfg-cert"},
+		}}},
+	}
+
+	cases := []struct {
+		svc  string
+		want []string
+	}{
+		{"test_svc", []string{"test-cert"}},
+		{"another_svc", []string{"another-cert"}},
+		{"unknown_svc", []string{}},
+	}
+	for _, c := range cases {
+		if got := SvcTLSCertNames(exts, c.svc); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("SvcTLSCertNames(%s) = %v, want %v", c.svc, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package eputils
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// agentSigner signs with a key of the SSH agent, connecting to the agent for
+// each signature so that no connection is left open after the handshake.
+type agentSigner struct {
+	socket string
+	key    ssh.PublicKey
+}
+
+func (s *agentSigner) PublicKey() ssh.PublicKey {
+	return s.key
+}
+
+func (s *agentSigner) Sign(rand io.Reader, data []byte) (*ssh.Signature, error) {
+	return s.SignWithAlgorithm(rand, data, "")
+}
+
+func (s *agentSigner) SignWithAlgorithm(rand io.Reader, data []byte, algorithm string) (*ssh.Signature, error) {
+	conn, err := net.Dial("unix", s.socket)
+	if err != nil {
+		log.Errorf("Failed to connect to the SSH agent %s: %v", s.socket, err)
+		return nil, err
+	}
+	defer conn.Close()
+
+	var flags agent.SignatureFlags
+	switch algorithm {
+	case ssh.KeyAlgoRSASHA256:
+		flags = agent.SignatureFlagRsaSha256
+	case ssh.KeyAlgoRSASHA512:
+		flags = agent.SignatureFlagRsaSha512
+	}
+	return agent.NewClient(conn).SignWithFlags(s.key, data, flags)
+}
+
+func agentSigners(socket string) ([]ssh.Signer, error) {
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		log.Errorf("Failed to connect to the SSH agent %s: %v", socket, err)
+		return nil, err
+	}
+	defer conn.Close()
+
+	keys, err := agent.NewClient(conn).List()
+	if err != nil {
+		log.Errorf("Failed to list the keys of the SSH agent %s: %v", socket, err)
+		return nil, err
+	}
+	signers := []ssh.Signer{}
+	for _, key := range keys {
+		signers = append(signers, &agentSigner{socket: socket, key: key})
+	}
+	return signers, nil
+}
+
+// ReadSSHFile returns the content if set, or reads the file at path, where
+// a leading "~" is the home directory.
+func ReadSSHFile(content, path string) (string, error) {
+	if content != "" || path == "" {
+		return content, nil
+	}
+	if strings.HasPrefix(path, "~") {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = strings.Replace(path, "~", homeDir, 1)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Errorf("Failed to read %s: %v", path, err)
+		return "", err
+	}
+	return string(data), nil
+}
+
+// SSHPublicKeyAuth returns the public key auth method with the private key,
+// presented with the OpenSSH user certificate first if the certificate is set,
+// and the keys of the SSH agent listening on agentSocket, or nil if there is
+// no key. The agent socket path may refer to environment variables, e.g.
+// "$SSH_AUTH_SOCK".
+func SSHPublicKeyAuth(key, cert, agentSocket string) (ssh.AuthMethod, error) {
+	signers := []ssh.Signer{}
+	if key != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(key))
+		if err != nil {
+			log.Errorf("Parse Key err: %v", err)
+			return nil, err
+		}
+		if cert != "" {
+			pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(cert))
+			if err != nil {
+				log.Errorf("Parse Cert err: %v", err)
+				return nil, err
+			}
+			sshCert, ok := pub.(*ssh.Certificate)
+			if !ok {
+				log.Errorf("%s is not an SSH certificate", pub.Type())
+				return nil, GetError("errCertType")
+			}
+			certSigner, err := ssh.NewCertSigner(sshCert, signer)
+			if err != nil {
+				log.Errorf("Failed to sign with the SSH certificate: %v", err)
+				return nil, err
+			}
+			signers = append(signers, certSigner)
+		}
+		signers = append(signers, signer)
+	}
+	if agentSocket == "" {
+		if len(signers) == 0 {
+			return nil, nil
+		}
+		return ssh.PublicKeys(signers...), nil
+	}
+	socket := os.ExpandEnv(agentSocket)
+	return ssh.PublicKeysCallback(func() ([]ssh.Signer, error) {
+		keys, err := agentSigners(socket)
+		if err != nil {
+			if len(signers) == 0 {
+				return nil, err
+			}
+			log.Warnf("Authenticate without the SSH agent %s", socket)
+		}
+		return append(append([]ssh.Signer{}, signers...), keys...), nil
+	}), nil
+}
+
+func parseProxyJump(jump, defaultUser string) (string, string, error) {
+	user := defaultUser
+	if i := strings.LastIndex(jump, "@"); i >= 0 {
+		user, jump = jump[:i], jump[i+1:]
+	}
+	host, port, err := net.SplitHostPort(jump)
+	if err != nil {
+		host, port = strings.Trim(jump, "[]"), "22"
+	}
+	if user == "" || host == "" || port == "" {
+		log.Errorf("Invalid proxy_jump host %q, [user@]host[:port] is expected", jump)
+		return "", "", GetError("errProxyJump")
+	}
+	return user, net.JoinHostPort(host, port), nil
+}
+
+func dialSSHVia(via *ssh.Client, addr string, cfg *ssh.ClientConfig) (*ssh.Client, error) {
+	if via == nil {
+		return ssh.Dial("tcp", addr, cfg)
+	}
+	conn, err := via.Dial("tcp", addr)
+	if err != nil {
+		via.Close()
+		return nil, err
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, cfg)
+	if err != nil {
+		conn.Close()
+		via.Close()
+		return nil, err
+	}
+	client := ssh.NewClient(c, chans, reqs)
+	go func() {
+		_ = client.Wait()
+		via.Close()
+	}()
+	return client, nil
+}
+
+// DialSSHVia connects to addr through the chain of jump hosts, each of which
+// is "[user@]host[:port]" and is authenticated with the user and the auth
+// methods of cfg if not set. The connections to the jump hosts are closed with
+// the returned client.
+func DialSSHVia(addr string, cfg *ssh.ClientConfig, proxyJump []string) (*ssh.Client, error) {
+	var client *ssh.Client
+	for _, jump := range proxyJump {
+		user, jumpAddr, err := parseProxyJump(jump, cfg.User)
+		if err != nil {
+			if client != nil {
+				client.Close()
+			}
+			return nil, err
+		}
+		jumpCfg := *cfg
+		jumpCfg.User = user
+		jumpCfg.HostKeyCallback = HostKeyCallback(nil)
+		client, err = dialSSHVia(client, jumpAddr, &jumpCfg)
+		if err != nil {
+			log.Errorf("Unable to connect to the jump host %s: %v", jumpAddr, err)
+			return nil, err
+		}
+	}
+	return dialSSHVia(client, addr, cfg)
+}
@@ -0,0 +1,236 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package eputils
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	sshd "github.com/gliderlabs/ssh"
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+func startTestSSHServer(t *testing.T, srv *sshd.Server) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Handler = func(s sshd.Session) {}
+	go func() {
+		_ = srv.Serve(l)
+	}()
+	t.Cleanup(func() { srv.Close() })
+	return l.Addr().String()
+}
+
+func startTestSSHAgent(t *testing.T, key ed25519.PrivateKey) string {
+	keyring := agent.NewKeyring()
+	if err := keyring.Add(agent.AddedKey{PrivateKey: key}); err != nil {
+		t.Fatal(err)
+	}
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_ = agent.ServeAgent(keyring, conn)
+			}()
+		}
+	}()
+	t.Cleanup(func() { l.Close() })
+	return socket
+}
+
+func TestSSHAuth(t *testing.T) {
+	_, caKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caSigner, err := ssh.NewSignerFromKey(caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	userSigner, err := ssh.ParsePrivateKey([]byte(nodeWithKey.SSHKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := &ssh.Certificate{
+		Key:             userSigner.PublicKey(),
+		CertType:        ssh.UserCert,
+		ValidPrincipals: []string{"test"},
+		ValidBefore:     ssh.CertTimeInfinity,
+	}
+	if err := cert.SignCert(rand.Reader, caSigner); err != nil {
+		t.Fatal(err)
+	}
+	certString := string(ssh.MarshalAuthorizedKey(cert))
+	keyPath := filepath.Join(t.TempDir(), "id_rsa")
+	if err := WriteStringToFile(nodeWithKey.SSHKey, keyPath); err != nil {
+		t.Fatal(err)
+	}
+	certPath := keyPath + "-cert.pub"
+	if err := WriteStringToFile(certString, certPath); err != nil {
+		t.Fatal(err)
+	}
+
+	agentPub, agentKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agentSSHPub, err := ssh.NewPublicKey(agentPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	socket := startTestSSHAgent(t, agentKey)
+	t.Setenv("TEST_SSH_AUTH_SOCK", socket)
+
+	// The node only accepts the user certificates signed by the CA and the
+	// key of the agent.
+	nodeAddr := startTestSSHServer(t, &sshd.Server{
+		PublicKeyHandler: func(ctx sshd.Context, key sshd.PublicKey) bool {
+			if c, ok := key.(*ssh.Certificate); ok {
+				return bytes.Equal(c.SignatureKey.Marshal(), caSigner.PublicKey().Marshal())
+			}
+			return bytes.Equal(key.Marshal(), agentSSHPub.Marshal())
+		},
+	})
+	var forwards int32
+	jumpAddr := startTestSSHServer(t, &sshd.Server{
+		LocalPortForwardingCallback: func(ctx sshd.Context, host string, port uint32) bool {
+			atomic.AddInt32(&forwards, 1)
+			return true
+		},
+		ChannelHandlers: map[string]sshd.ChannelHandler{
+			"session":      sshd.DefaultSessionHandler,
+			"direct-tcpip": sshd.DirectTCPIPHandler,
+		},
+	})
+
+	cases := []struct {
+		name         string
+		node         pluginapi.Node
+		wantForwards int32
+		wantGenErr   error
+		wantRunErr   bool
+	}{
+		{
+			name:       "key without cert",
+			node:       pluginapi.Node{SSHKey: nodeWithKey.SSHKey},
+			wantRunErr: true,
+		},
+		{
+			name: "key with cert",
+			node: pluginapi.Node{SSHKey: nodeWithKey.SSHKey, SSHCert: certString},
+		},
+		{
+			name: "key with cert path",
+			node: pluginapi.Node{SSHKeyPath: keyPath, SSHCertPath: certPath},
+		},
+		{
+			name:       "key path not found",
+			node:       pluginapi.Node{SSHKeyPath: keyPath + ".notfound", SSHCertPath: certPath},
+			wantGenErr: os.ErrNotExist,
+		},
+		{
+			name:       "not a cert",
+			node:       pluginapi.Node{SSHKey: nodeWithKey.SSHKey, SSHCert: string(ssh.MarshalAuthorizedKey(agentSSHPub))},
+			wantGenErr: GetError("errCertType"),
+		},
+		{
+			name: "agent socket",
+			node: pluginapi.Node{SSHAgentSocket: socket},
+		},
+		{
+			name: "agent socket from env",
+			node: pluginapi.Node{SSHAgentSocket: "$TEST_SSH_AUTH_SOCK"},
+		},
+		{
+			name:       "agent socket not found",
+			node:       pluginapi.Node{SSHAgentSocket: socket + ".notfound"},
+			wantRunErr: true,
+		},
+		{
+			name:         "proxy jump",
+			node:         pluginapi.Node{SSHKey: nodeWithKey.SSHKey, SSHCert: certString, ProxyJump: []string{"jump@" + jumpAddr}},
+			wantForwards: 1,
+		},
+		{
+			name:         "proxy jump chain",
+			node:         pluginapi.Node{SSHAgentSocket: socket, ProxyJump: []string{jumpAddr, "jump@" + jumpAddr}},
+			wantForwards: 2,
+		},
+		{
+			name:       "invalid proxy jump",
+			node:       pluginapi.Node{SSHAgentSocket: socket, ProxyJump: []string{"@" + jumpAddr}},
+			wantRunErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			atomic.StoreInt32(&forwards, 0)
+			tc.node.User = "test"
+			cfg, proxyJump, err := GenSSHConfig(&tc.node)
+			if !errors.Is(err, tc.wantGenErr) {
+				t.Fatalf("Unexpected error: %v, want %v", err, tc.wantGenErr)
+			}
+			if err != nil {
+				return
+			}
+			err = RunRemoteCMD(nodeAddr, cfg, proxyJump, "true")
+			if (err != nil) != tc.wantRunErr {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if n := atomic.LoadInt32(&forwards); n != tc.wantForwards {
+				t.Errorf("Unexpected forwards through the jump host: %d, want %d", n, tc.wantForwards)
+			}
+		})
+	}
+}
+
+func TestParseProxyJump(t *testing.T) {
+	cases := []struct {
+		jump     string
+		wantUser string
+		wantAddr string
+		wantErr  error
+	}{
+		{"bastion", "sysadmin", "bastion:22", nil},
+		{"admin@bastion:2222", "admin", "bastion:2222", nil},
+		{"admin@10.10.10.10", "admin", "10.10.10.10:22", nil},
+		{"[fd00::1]:2222", "sysadmin", "[fd00::1]:2222", nil},
+		{"admin@", "", "", GetError("errProxyJump")},
+		{"@bastion", "", "", GetError("errProxyJump")},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.jump, func(t *testing.T) {
+			user, addr, err := parseProxyJump(tc.jump, "sysadmin")
+			if err != tc.wantErr || user != tc.wantUser || addr != tc.wantAddr {
+				t.Errorf("Unexpected result: %s, %s, %v", user, addr, err)
+			}
+		})
+	}
+}
@@ -27,17 +27,17 @@ const (
 //go:generate mockgen -destination=./mock/sshutil_mock/sshutil_mock.go -package=mock -copyright_file=../../api/schemas/license-header.txt github.com/intel/edge-conductor/pkg/eputils SSHApiInterface,SSHDialInterface
 type (
 	SSHApiInterface interface {
-		GenSSHConfig(server *pluginapi.Node) (*ssh.ClientConfig, error)
-		RunRemoteCMD(addr string, cfg *ssh.ClientConfig, cmd string) error
-		RunRemoteMultiCMD(addr string, cfg *ssh.ClientConfig, commands []string) error
-		WriteRemoteFile(addr string, cfg *ssh.ClientConfig, content, path string) error
-		CopyLocalFileToRemoteRootFileSudoNoPasswd(addr string, cfg *ssh.ClientConfig, localPath, remotePath string) error
-		CopyLocalFileToRemoteFile(addr string, cfg *ssh.ClientConfig, localPath, remotePath string) error
-		CopyRemoteRootFileToLocalFileSudoNoPasswd(addr string, cfg *ssh.ClientConfig, remotePath, localPath string, perm os.FileMode) error
-		CopyRemoteFileToLocalFile(addr string, cfg *ssh.ClientConfig, remotePath, localPath string, perm os.FileMode) error
-		ContainerdCertificatePathCreateSudoNoPasswd(addr string, cfg *ssh.ClientConfig, containerdcertpath, registry string) error
-		ServiceRestartSudoNoPasswd(addr string, cfg *ssh.ClientConfig, serviceName string) error
-		RemoteFileExists(addr string, cfg *ssh.ClientConfig, remotePath string) (bool, error)
+		GenSSHConfig(server *pluginapi.Node) (*ssh.ClientConfig, []string, error)
+		RunRemoteCMD(addr string, cfg *ssh.ClientConfig, proxyJump []string, cmd string) error
+		RunRemoteMultiCMD(addr string, cfg *ssh.ClientConfig, proxyJump, commands []string) error
+		WriteRemoteFile(addr string, cfg *ssh.ClientConfig, proxyJump []string, content, path string) error
+		CopyLocalFileToRemoteRootFileSudoNoPasswd(addr string, cfg *ssh.ClientConfig, proxyJump []string, localPath, remotePath string) error
+		CopyLocalFileToRemoteFile(addr string, cfg *ssh.ClientConfig, proxyJump []string, localPath, remotePath string) error
+		CopyRemoteRootFileToLocalFileSudoNoPasswd(addr string, cfg *ssh.ClientConfig, proxyJump []string, remotePath, localPath string, perm os.FileMode) error
+		CopyRemoteFileToLocalFile(addr string, cfg *ssh.ClientConfig, proxyJump []string, remotePath, localPath string, perm os.FileMode) error
+		ContainerdCertificatePathCreateSudoNoPasswd(addr string, cfg *ssh.ClientConfig, proxyJump []string, containerdcertpath, registry string) error
+		ServiceRestartSudoNoPasswd(addr string, cfg *ssh.ClientConfig, proxyJump []string, serviceName string) error
+		RemoteFileExists(addr string, cfg *ssh.ClientConfig, proxyJump []string, remotePath string) (bool, error)
 		RunRemoteNodeMultiCMD(server *pluginapi.Node, commands []string) error
 	}
 
@@ -48,49 +48,42 @@ type (
 
 var errRemoteNotAFile = errors.New("CopyRemoteFileToLocalFile: not a file")
 
-func GenSSHConfig(server *pluginapi.Node) (*ssh.ClientConfig, error) {
-	var config *ssh.ClientConfig
-	if server.SSHKey != "" {
-		signer, err := ssh.ParsePrivateKey([]byte(server.SSHKey))
-		if err != nil {
-			log.Errorf("Parse Key err: %v", err)
-			return nil, err
-		}
-		config = &ssh.ClientConfig{
-			Config: ssh.Config{
-				Ciphers: []string{
-					"aes256-gcm@openssh.com",
-					"chacha20-poly1305@openssh.com",
-					"aes256-ctr", "aes256-cbc"},
-			},
-			User: server.User,
-			Auth: []ssh.AuthMethod{
-				ssh.PublicKeys(signer),
-			},
-			// #nosec G106
-			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-		}
-	} else {
-		config = &ssh.ClientConfig{
-			Config: ssh.Config{
-				Ciphers: []string{
-					"aes256-gcm@openssh.com",
-					"chacha20-poly1305@openssh.com",
-					"aes256-ctr", "aes256-cbc"},
-			},
-			User: server.User,
-			Auth: []ssh.AuthMethod{
-				ssh.Password(server.SSHPasswd),
-			},
-			// #nosec G106
-			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-		}
+// GenSSHConfig generates the SSH client config of the node, authenticated
+// with the node's keys, user certificate and SSH agent if any, or the password.
+// It also returns the proxy_jump hosts of the node, which the SSH helpers
+// connect to the node through.
+func GenSSHConfig(server *pluginapi.Node) (*ssh.ClientConfig, []string, error) {
+	key, err := ReadSSHFile(server.SSHKey, server.SSHKeyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := ReadSSHFile(server.SSHCert, server.SSHCertPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	auth, err := SSHPublicKeyAuth(key, cert, server.SSHAgentSocket)
+	if err != nil {
+		return nil, nil, err
+	}
+	if auth == nil {
+		auth = ssh.Password(server.SSHPasswd)
+	}
+	config := &ssh.ClientConfig{
+		Config: ssh.Config{
+			Ciphers: []string{
+				"aes256-gcm@openssh.com",
+				"chacha20-poly1305@openssh.com",
+				"aes256-ctr", "aes256-cbc"},
+		},
+		User:            server.User,
+		Auth:            []ssh.AuthMethod{auth},
+		HostKeyCallback: HostKeyCallback(server.SSHHostKeyFingerprints),
 	}
-	return config, nil
+	return config, server.ProxyJump, nil
 }
 
-func RunRemoteCMD(addr string, cfg *ssh.ClientConfig, cmd string) error {
-	client, err := ssh.Dial("tcp", addr, cfg)
+func RunRemoteCMD(addr string, cfg *ssh.ClientConfig, proxyJump []string, cmd string) error {
+	client, err := DialSSHVia(addr, cfg, proxyJump)
 	if err != nil {
 		log.Errorf("Unable to connect:%s %v", addr, err)
 		return err
@@ -111,8 +104,8 @@ func RunRemoteCMD(addr string, cfg *ssh.ClientConfig, cmd string) error {
 	return nil
 }
 
-func RunRemoteMultiCMD(addr string, cfg *ssh.ClientConfig, commands []string) error {
-	client, err := ssh.Dial("tcp", addr, cfg)
+func RunRemoteMultiCMD(addr string, cfg *ssh.ClientConfig, proxyJump, commands []string) error {
+	client, err := DialSSHVia(addr, cfg, proxyJump)
 	if err != nil {
 		log.Errorf("Unable to connect:%s %v", addr, err)
 		return err
@@ -166,14 +159,14 @@ func RunRemoteNodeMultiCMD(server *pluginapi.Node, commands []string) error {
 		return nil
 	}
 
-	cfg, err := GenSSHConfig(server)
+	cfg, proxyJump, err := GenSSHConfig(server)
 	if err != nil {
 		log.Errorf("Fail to gen config %v", err)
 		return err
 	}
 
 	addr := fmt.Sprintf("%s:%d", server.IP, server.SSHPort)
-	err = RunRemoteMultiCMD(addr, cfg, commands)
+	err = RunRemoteMultiCMD(addr, cfg, proxyJump, commands)
 	log.Infof("sudo ssh command used!")
 	if err != nil {
 		return err
@@ -181,9 +174,9 @@ func RunRemoteNodeMultiCMD(server *pluginapi.Node, commands []string) error {
 	return nil
 }
 
-func WriteRemoteFile(addr string, cfg *ssh.ClientConfig, content, path string) error {
+func WriteRemoteFile(addr string, cfg *ssh.ClientConfig, proxyJump []string, content, path string) error {
 
-	client, err := ssh.Dial("tcp", addr, cfg)
+	client, err := DialSSHVia(addr, cfg, proxyJump)
 	if err != nil {
 		log.Errorf("Unable to connect:%s %v", addr, err)
 		return err
@@ -222,8 +215,8 @@ func WriteRemoteFile(addr string, cfg *ssh.ClientConfig, content, path string) e
 	return nil
 }
 
-func CopyLocalFileToRemoteRootFileSudoNoPasswd(addr string, cfg *ssh.ClientConfig, localPath, remotePath string) error {
-	_, err := ssh.Dial("tcp", addr, cfg)
+func CopyLocalFileToRemoteRootFileSudoNoPasswd(addr string, cfg *ssh.ClientConfig, proxyJump []string, localPath, remotePath string) error {
+	_, err := DialSSHVia(addr, cfg, proxyJump)
 	if err != nil {
 		log.Errorf("Unable to connect:%s %v", addr, err)
 		return err
@@ -231,7 +224,7 @@ func CopyLocalFileToRemoteRootFileSudoNoPasswd(addr string, cfg *ssh.ClientConfi
 
 	fileNameTemp := filepath.Base(localPath)
 	remotePathTemp := "/tmp/" + fileNameTemp
-	err = CopyLocalFileToRemoteFile(addr, cfg, localPath, remotePathTemp)
+	err = CopyLocalFileToRemoteFile(addr, cfg, proxyJump, localPath, remotePathTemp)
 	if err != nil {
 		log.Errorf("Failed to scp %s", localPath)
 		return err
@@ -239,7 +232,7 @@ func CopyLocalFileToRemoteRootFileSudoNoPasswd(addr string, cfg *ssh.ClientConfi
 
 	moveCmd := sudoPrefix + "mv " + remotePathTemp + " " + remotePath
 	cmd := moveCmd
-	err = RunRemoteCMD(addr, cfg, cmd)
+	err = RunRemoteCMD(addr, cfg, proxyJump, cmd)
 	log.Infof("sudo ssh command used!")
 	if err != nil {
 		log.Errorf("Failed to scp %s", remotePath)
@@ -249,9 +242,9 @@ func CopyLocalFileToRemoteRootFileSudoNoPasswd(addr string, cfg *ssh.ClientConfi
 	return nil
 }
 
-func CopyLocalFileToRemoteFile(addr string, cfg *ssh.ClientConfig, localPath, remotePath string) error {
+func CopyLocalFileToRemoteFile(addr string, cfg *ssh.ClientConfig, proxyJump []string, localPath, remotePath string) error {
 
-	client, err := ssh.Dial("tcp", addr, cfg)
+	client, err := DialSSHVia(addr, cfg, proxyJump)
 	if err != nil {
 		log.Errorf("Unable to connect:%s %v", addr, err)
 		return err
@@ -298,8 +291,8 @@ func CopyLocalFileToRemoteFile(addr string, cfg *ssh.ClientConfig, localPath, re
 	return nil
 }
 
-func CopyRemoteRootFileToLocalFileSudoNoPasswd(addr string, cfg *ssh.ClientConfig, remotePath, localPath string, perm os.FileMode) error {
-	_, err := ssh.Dial("tcp", addr, cfg)
+func CopyRemoteRootFileToLocalFileSudoNoPasswd(addr string, cfg *ssh.ClientConfig, proxyJump []string, remotePath, localPath string, perm os.FileMode) error {
+	_, err := DialSSHVia(addr, cfg, proxyJump)
 	if err != nil {
 		log.Errorf("Unable to connect:%s %v", addr, err)
 		return err
@@ -312,14 +305,14 @@ func CopyRemoteRootFileToLocalFileSudoNoPasswd(addr string, cfg *ssh.ClientConfi
 	chownCmd := sudoPrefix + "chown $(id -u " + cfg.User + "):$(id -g " + cfg.User + ")" + " " + remotePathTemp
 
 	cmd := cpCmd + " && " + chownCmd
-	err = RunRemoteCMD(addr, cfg, cmd)
+	err = RunRemoteCMD(addr, cfg, proxyJump, cmd)
 	log.Infof("sudo ssh command used!")
 	if err != nil {
 		log.Errorf("Failed to copy file %v ", err)
 		return err
 	}
 
-	err = CopyRemoteFileToLocalFile(addr, cfg, remotePathTemp, localPath, perm)
+	err = CopyRemoteFileToLocalFile(addr, cfg, proxyJump, remotePathTemp, localPath, perm)
 	if err != nil {
 		log.Errorf("Failed to scp %s", remotePathTemp)
 		return err
@@ -327,7 +320,7 @@ func CopyRemoteRootFileToLocalFileSudoNoPasswd(addr string, cfg *ssh.ClientConfi
 
 	rmCmd := sudoPrefix + "rm " + " " + remotePathTemp
 	cmd = rmCmd
-	err = RunRemoteCMD(addr, cfg, cmd)
+	err = RunRemoteCMD(addr, cfg, proxyJump, cmd)
 	log.Infof("sudo ssh command used!")
 	if err != nil {
 		log.Errorf("Failed to remove temp file %v ", err)
@@ -337,9 +330,9 @@ func CopyRemoteRootFileToLocalFileSudoNoPasswd(addr string, cfg *ssh.ClientConfi
 	return nil
 }
 
-func CopyRemoteFileToLocalFile(addr string, cfg *ssh.ClientConfig, remotePath, localPath string, perm os.FileMode) error {
+func CopyRemoteFileToLocalFile(addr string, cfg *ssh.ClientConfig, proxyJump []string, remotePath, localPath string, perm os.FileMode) error {
 
-	client, err := ssh.Dial("tcp", addr, cfg)
+	client, err := DialSSHVia(addr, cfg, proxyJump)
 	if err != nil {
 		log.Errorf("Unable to connect:%s %v", addr, err)
 		return err
@@ -391,9 +384,9 @@ func CopyRemoteFileToLocalFile(addr string, cfg *ssh.ClientConfig, remotePath, l
 	return nil
 }
 
-func ContainerdCertificatePathCreateSudoNoPasswd(addr string, cfg *ssh.ClientConfig, containerdcertpath, registry string) error {
+func ContainerdCertificatePathCreateSudoNoPasswd(addr string, cfg *ssh.ClientConfig, proxyJump []string, containerdcertpath, registry string) error {
 
-	_, err := ssh.Dial("tcp", addr, cfg)
+	_, err := DialSSHVia(addr, cfg, proxyJump)
 	if err != nil {
 		log.Errorf("Unable to connect:%s %v", addr, err)
 		return err
@@ -403,7 +396,7 @@ func ContainerdCertificatePathCreateSudoNoPasswd(addr string, cfg *ssh.ClientCon
 	mkdirCertsdRegistryCmd := mkdirCertsdCmd + registry
 	chownCmd := sudoPrefix + "chown $(id -u " + cfg.User + "):$(id -g " + cfg.User + ")" + " " + "-R" + " " + containerdcertpath
 	cmd := mkdirCertsdCmd + " && " + mkdirCertsdRegistryCmd + " && " + chownCmd
-	err = RunRemoteCMD(addr, cfg, cmd)
+	err = RunRemoteCMD(addr, cfg, proxyJump, cmd)
 	log.Infof("sudo ssh command used!")
 	if err != nil {
 		log.Errorf("Failed to create CA root certificate path %v ", err)
@@ -413,15 +406,15 @@ func ContainerdCertificatePathCreateSudoNoPasswd(addr string, cfg *ssh.ClientCon
 	return nil
 }
 
-func ServiceRestartSudoNoPasswd(addr string, cfg *ssh.ClientConfig, serviceName string) error {
-	_, err := ssh.Dial("tcp", addr, cfg)
+func ServiceRestartSudoNoPasswd(addr string, cfg *ssh.ClientConfig, proxyJump []string, serviceName string) error {
+	_, err := DialSSHVia(addr, cfg, proxyJump)
 	if err != nil {
 		log.Errorf("Unable to connect:%s %v", addr, err)
 		return err
 	}
 	ServiceRestartCmd := sudoPrefix + "systemctl daemon-reload" + " && " + sudoPrefix + "systemctl restart " + serviceName
 	cmd := ServiceRestartCmd
-	err = RunRemoteCMD(addr, cfg, cmd)
+	err = RunRemoteCMD(addr, cfg, proxyJump, cmd)
 	log.Infof("sudo ssh command used!")
 	if err != nil {
 		log.Errorf("Failed to restart service %v ", err)
@@ -431,9 +424,9 @@ func ServiceRestartSudoNoPasswd(addr string, cfg *ssh.ClientConfig, serviceName
 	return nil
 }
 
-func RemoteFileExists(addr string, cfg *ssh.ClientConfig, remotePath string) (bool, error) {
+func RemoteFileExists(addr string, cfg *ssh.ClientConfig, proxyJump []string, remotePath string) (bool, error) {
 
-	client, err := ssh.Dial("tcp", addr, cfg)
+	client, err := DialSSHVia(addr, cfg, proxyJump)
 	if err != nil {
 		log.Errorf("Unable to connect:%s %v", addr, err)
 		return false, err
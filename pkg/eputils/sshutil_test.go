@@ -90,7 +90,7 @@ func unpatch(t *testing.T, m *mpatch.Patch) {
 func patchCopyLocalFileToRemoteFile(t *testing.T, retErr error) {
 	var patch *mpatch.Patch
 	var err error
-	patch, err = mpatch.PatchMethod(CopyLocalFileToRemoteFile, func(addr string, cfg *ssh.ClientConfig, localPath, remotePath string) error {
+	patch, err = mpatch.PatchMethod(CopyLocalFileToRemoteFile, func(addr string, cfg *ssh.ClientConfig, proxyJump []string, localPath, remotePath string) error {
 		unpatch(t, patch)
 		return retErr
 	})
@@ -102,7 +102,7 @@ func patchCopyLocalFileToRemoteFile(t *testing.T, retErr error) {
 func patchRunRemoteCMD(t *testing.T, retErr error) {
 	var patch *mpatch.Patch
 	var err error
-	patch, err = mpatch.PatchMethod(RunRemoteCMD, func(addr string, cfg *ssh.ClientConfig, cmd string) error {
+	patch, err = mpatch.PatchMethod(RunRemoteCMD, func(addr string, cfg *ssh.ClientConfig, proxyJump []string, cmd string) error {
 		unpatch(t, patch)
 		return retErr
 	})
@@ -143,7 +143,7 @@ func TestGenSSHConfig(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Logf("Run Test Case %s", tc.name)
-			_, err := GenSSHConfig(tc.node)
+			_, _, err := GenSSHConfig(tc.node)
 			if tc.expectError == false && err != nil {
 				t.Error(err)
 			} else {
@@ -202,7 +202,7 @@ func TestRunRemoteCMD(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Logf("Run Test Case %s", tc.name)
-			cfg, _ := GenSSHConfig(tc.node)
+			cfg, proxyJump, _ := GenSSHConfig(tc.node)
 			addr := fmt.Sprintf("%s:%d", tc.node.IP, tc.node.SSHPort)
 			var c *ssh.Client
 			if tc.sessionerr != nil {
@@ -228,7 +228,7 @@ func TestRunRemoteCMD(t *testing.T) {
 				t.Fatal(err)
 			}
 			defer unpatch(t, patch2)
-			err = RunRemoteCMD(addr, cfg, tc.cmd)
+			err = RunRemoteCMD(addr, cfg, proxyJump, tc.cmd)
 			if err != nil {
 				if tc.clienterr != nil || tc.sessionerr != nil || tc.cmderr != nil {
 					t.Logf("Test Case %s Pass", tc.name)
@@ -341,7 +341,7 @@ func TestRunRemoteMultiCMD(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			t.Logf("Run Test Case %s", tc.name)
-			cfg, _ := GenSSHConfig(tc.node)
+			cfg, proxyJump, _ := GenSSHConfig(tc.node)
 			addr := fmt.Sprintf("%s:%d", tc.node.IP, tc.node.SSHPort)
 			var c *ssh.Client
 			if tc.sessionerr != nil {
@@ -401,7 +401,7 @@ func TestRunRemoteMultiCMD(t *testing.T) {
 				t.Fatal(err)
 			}
 			defer unpatch(t, patch5)
-			err = RunRemoteMultiCMD(addr, cfg, tc.cmd)
+			err = RunRemoteMultiCMD(addr, cfg, proxyJump, tc.cmd)
 			if err != nil {
 				if item > 1 {
 					t.Logf("Test Case %s Pass", tc.name)
@@ -697,7 +697,7 @@ func TestWriteRemoteFile(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Logf("Run Test Case %s", tc.name)
-			cfg, _ := GenSSHConfig(tc.node)
+			cfg, proxyJump, _ := GenSSHConfig(tc.node)
 			addr := fmt.Sprintf("%s:%d", tc.node.IP, tc.node.SSHPort)
 			var c *sftp.Client
 			var f *sftp.File
@@ -760,7 +760,7 @@ func TestWriteRemoteFile(t *testing.T) {
 			}
 			defer unpatch(t, patch5)
 
-			err = WriteRemoteFile(addr, cfg, tc.content, tc.path)
+			err = WriteRemoteFile(addr, cfg, proxyJump, tc.content, tc.path)
 			if err != nil {
 				t.Logf("Test Case %s Fail", tc.name)
 			} else {
@@ -883,7 +883,7 @@ func TestCopyLocalFileToRemoteFile(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			t.Logf("Run Test Case %s", tc.name)
-			cfg, _ := GenSSHConfig(tc.node)
+			cfg, proxyJump, _ := GenSSHConfig(tc.node)
 			addr := fmt.Sprintf("%s:%d", tc.node.IP, tc.node.SSHPort)
 			mocksftpInterface := fakesftp_mock.NewMockSftpClientInterface(ctrl)
 			patchc, err := mpatch.PatchMethod(sftp.NewClient, mocksftpInterface.NewClient)
@@ -952,7 +952,7 @@ func TestCopyLocalFileToRemoteFile(t *testing.T) {
 				t.Fatal(err)
 			}
 			defer unpatch(t, patch6)
-			err = CopyLocalFileToRemoteFile(addr, cfg, tc.local, tc.remote)
+			err = CopyLocalFileToRemoteFile(addr, cfg, proxyJump, tc.local, tc.remote)
 			if !isExpectedError(err, tc.returnErr) {
 				t.Errorf("expected error %v, but function returned error: %v", tc.returnErr, err)
 			}
@@ -1107,7 +1107,10 @@ func TestCopyRemoteFileToLocalFile(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			t.Logf("Run Test Case %s", tc.name)
-			cfg, _ := GenSSHConfig(tc.node)
+			cfg, proxyJump, _ := GenSSHConfig(tc.node)
+			// os.OpenFile is patched below, so skip the known_hosts store.
+			// #nosec G106
+			cfg.HostKeyCallback = ssh.InsecureIgnoreHostKey()
 			addr := fmt.Sprintf("%s:%d", tc.node.IP, tc.node.SSHPort)
 			mocksftpInterface := fakesftp_mock.NewMockSftpClientInterface(ctrl)
 			patchc, err := mpatch.PatchMethod(sftp.NewClient, mocksftpInterface.NewClient)
@@ -1177,7 +1180,7 @@ func TestCopyRemoteFileToLocalFile(t *testing.T) {
 				t.Fatal(err)
 			}
 			defer unpatch(t, patch6)
-			err = CopyRemoteFileToLocalFile(addr, cfg, tc.remote, tc.local, 0700)
+			err = CopyRemoteFileToLocalFile(addr, cfg, proxyJump, tc.remote, tc.local, 0700)
 			if !isExpectedError(err, tc.returnErr) {
 				t.Errorf("expected error %v, but function returned error: %v", tc.returnErr, err)
 			}
@@ -1239,12 +1242,12 @@ func TestCopyLocalFileToRemoteRootFileSudoNoPasswd(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			t.Logf("Run Test Case %s", tc.name)
-			cfg, _ := GenSSHConfig(tc.node)
+			cfg, proxyJump, _ := GenSSHConfig(tc.node)
 			addr := fmt.Sprintf("%s:%d", tc.node.IP, tc.node.SSHPort)
 			if tc.funcBeforeTest != nil {
 				tc.funcBeforeTest(ctrl)
 			}
-			err := CopyLocalFileToRemoteRootFileSudoNoPasswd(addr, cfg, tc.local, tc.remote)
+			err := CopyLocalFileToRemoteRootFileSudoNoPasswd(addr, cfg, proxyJump, tc.local, tc.remote)
 			if !isExpectedError(err, tc.expectedError) {
 				t.Errorf("expected error %v, but function returned error: %v", tc.expectedError, err)
 			}
@@ -1315,9 +1318,9 @@ func TestCopyRemoteRootFileToLocalFileSudoNoPasswd(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Logf("Run Test Case %s", tc.name)
-			cfg, _ := GenSSHConfig(tc.node)
+			cfg, proxyJump, _ := GenSSHConfig(tc.node)
 			addr := fmt.Sprintf("%s:%d", tc.node.IP, tc.node.SSHPort)
-			patchd, err := mpatch.PatchMethod(CopyRemoteFileToLocalFile, func(addr string, cfg *ssh.ClientConfig, remotePath string, localPath string, perm fs.FileMode) error {
+			patchd, err := mpatch.PatchMethod(CopyRemoteFileToLocalFile, func(addr string, cfg *ssh.ClientConfig, proxyJump []string, remotePath string, localPath string, perm fs.FileMode) error {
 				return tc.copyErr
 			})
 			if err != nil {
@@ -1325,9 +1328,9 @@ func TestCopyRemoteRootFileToLocalFileSudoNoPasswd(t *testing.T) {
 			}
 			defer unpatch(t, patchd)
 			var patch *mpatch.Patch
-			patch, err = mpatch.PatchMethod(RunRemoteCMD, func(addr string, cfg *ssh.ClientConfig, cmd string) error {
+			patch, err = mpatch.PatchMethod(RunRemoteCMD, func(addr string, cfg *ssh.ClientConfig, proxyJump []string, cmd string) error {
 				unpatch(t, patch)
-				patch, err = mpatch.PatchMethod(RunRemoteCMD, func(addr string, cfg *ssh.ClientConfig, cmd string) error {
+				patch, err = mpatch.PatchMethod(RunRemoteCMD, func(addr string, cfg *ssh.ClientConfig, proxyJump []string, cmd string) error {
 					return tc.cmd2Err
 				})
 				return tc.cmd1Err
@@ -1337,7 +1340,7 @@ func TestCopyRemoteRootFileToLocalFileSudoNoPasswd(t *testing.T) {
 			}
 			defer unpatch(t, patch)
 
-			err = CopyRemoteRootFileToLocalFileSudoNoPasswd(addr, cfg, tc.remote, tc.local, 0700)
+			err = CopyRemoteRootFileToLocalFileSudoNoPasswd(addr, cfg, proxyJump, tc.remote, tc.local, 0700)
 			if !isExpectedError(err, tc.returnError) {
 				t.Errorf("expected error %v, but function returned error: %v", tc.returnError, err)
 			}
@@ -1386,7 +1389,7 @@ func TestContainerdCertificatePathCreateSudoNoPasswd(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Logf("Run Test Case %s", tc.name)
-			cfg, _ := GenSSHConfig(tc.node)
+			cfg, proxyJump, _ := GenSSHConfig(tc.node)
 			addr := fmt.Sprintf("%s:%d", tc.node.IP, tc.node.SSHPort)
 			var c *ssh.Client
 			if tc.sessionerr != nil {
@@ -1401,7 +1404,7 @@ func TestContainerdCertificatePathCreateSudoNoPasswd(t *testing.T) {
 				}
 				defer unpatch(t, patch)
 			}
-			err := ContainerdCertificatePathCreateSudoNoPasswd(addr, cfg, "", "")
+			err := ContainerdCertificatePathCreateSudoNoPasswd(addr, cfg, proxyJump, "", "")
 			if err != nil {
 				if tc.clienterr != nil || tc.sessionerr != nil || tc.cmderr != nil {
 					t.Logf("Test Case %s Pass", tc.name)
@@ -1457,7 +1460,7 @@ func TestServiceRestartSudoNoPasswd(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Logf("Run Test Case %s", tc.name)
-			cfg, _ := GenSSHConfig(tc.node)
+			cfg, proxyJump, _ := GenSSHConfig(tc.node)
 			addr := fmt.Sprintf("%s:%d", tc.node.IP, tc.node.SSHPort)
 			var c *ssh.Client
 			if tc.sessionerr != nil {
@@ -1469,7 +1472,7 @@ func TestServiceRestartSudoNoPasswd(t *testing.T) {
 					})
 				defer unpatch(t, guard)
 			}
-			err := ServiceRestartSudoNoPasswd(addr, cfg, "")
+			err := ServiceRestartSudoNoPasswd(addr, cfg, proxyJump, "")
 			if err != nil {
 				if tc.clienterr != nil || tc.sessionerr != nil || tc.cmderr != nil {
 					t.Logf("Test Case %s Pass", tc.name)
@@ -1517,7 +1520,7 @@ func TestRemoteFileExists(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Logf("Run Test Case %s", tc.name)
-			cfg, _ := GenSSHConfig(tc.node)
+			cfg, proxyJump, _ := GenSSHConfig(tc.node)
 			addr := fmt.Sprintf("%s:%d", tc.node.IP, tc.node.SSHPort)
 			var c *ssh.Client
 			if tc.sessionerr != nil {
@@ -1529,7 +1532,7 @@ func TestRemoteFileExists(t *testing.T) {
 					})
 				defer unpatch(t, guard)
 			}
-			_, err := RemoteFileExists(addr, cfg, "")
+			_, err := RemoteFileExists(addr, cfg, proxyJump, "")
 			if err != nil {
 				if tc.clienterr != nil || tc.sessionerr != nil || tc.cmderr != nil {
 					t.Logf("Test Case %s Pass", tc.name)
@@ -1565,6 +1568,13 @@ func TestMain(m *testing.M) {
 		log.Error(err)
 	}
 	nodeWithKey.SSHKey = keystring
+	knownHostsDir, err := ioutil.TempDir("", "known_hosts")
+	if err != nil {
+		log.Fatal(err)
+	}
+	KnownHostsFile = filepath.Join(knownHostsDir, "known_hosts")
 	go runSSHServer()
-	os.Exit(m.Run())
+	code := m.Run()
+	os.RemoveAll(knownHostsDir)
+	os.Exit(code)
 }
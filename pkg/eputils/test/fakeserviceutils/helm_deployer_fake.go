@@ -8,6 +8,7 @@ package fakeserviceutils
 
 import (
 	serviceutil "github.com/intel/edge-conductor/pkg/eputils/service"
+	"helm.sh/helm/v3/pkg/release"
 )
 
 type FakeHelmDeployer struct {
@@ -21,11 +22,19 @@ func (h *FakeHelmDeployer) HelmStatus(loc_kubeconfig string) (string, int) {
 	return "", 0
 }
 
+func (h *FakeHelmDeployer) HelmRelease(loc_kubeconfig string) (*release.Release, error) {
+	return nil, nil
+}
+
 func (h *FakeHelmDeployer) HelmInstall(loc_kubeconfig string, arg ...serviceutil.InstallOpt) error {
 	return nil
 }
 
-func (h *FakeHelmDeployer) HelmUpgrade(loc_kubeconfig string) error {
+func (h *FakeHelmDeployer) HelmUpgrade(loc_kubeconfig string, arg ...serviceutil.InstallOpt) error {
+	return nil
+}
+
+func (h *FakeHelmDeployer) HelmRollback(loc_kubeconfig string, revision int, arg ...serviceutil.InstallOpt) error {
 	return nil
 }
 
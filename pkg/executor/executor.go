@@ -38,8 +38,9 @@ type nodeInfo struct {
 
 type tempParameter struct {
 	pluginapi.EpParams
-	Value interface{}
-	Node  interface{}
+	Value      interface{}
+	Node       interface{}
+	Registered map[string]*RegisteredResult
 }
 
 type Executor struct {
@@ -47,6 +48,15 @@ type Executor struct {
 	tempParams  tempParameter
 	nodesByIP   map[string]*nodeInfo
 	nodesByRole map[string][]*nodeInfo
+
+	// step and parallel of the step being run
+	step     string
+	parallel int
+
+	results *nodeResults
+	// summary is set when a step runs in parallel, continues on error or
+	// targets more than one node, for the node summary to be printed.
+	summary bool
 }
 
 func New() *Executor {
@@ -56,26 +66,22 @@ func New() *Executor {
 	}
 }
 
-func (e *Executor) StringOverrideWithNode(s string, ni *nodeInfo) (string, error) {
+// nodeParams returns the template parameters with the node set to ni.
+func (e *Executor) nodeParams(ni *nodeInfo) (tempParameter, error) {
 	p := e.tempParams
-	if !strings.Contains(s, `\{\{`) {
-		return s, nil
-	}
-	c := s
-	c = strings.ReplaceAll(c, `\{\{`, `{{`)
-	c = strings.ReplaceAll(c, `\}\}`, `}}`)
-
 	user, err := user.Current()
 	if err != nil {
-		return "", err
+		return p, err
 	}
-	for _, n := range e.tempParams.Kitconfig.Parameters.Nodes {
-		if n.IP == ni.ip {
-			if n.User == "" {
-				n.User = user.Username
+	if p.Kitconfig != nil && p.Kitconfig.Parameters != nil {
+		for _, n := range p.Kitconfig.Parameters.Nodes {
+			if n.IP == ni.ip {
+				if n.User == "" {
+					n.User = user.Username
+				}
+				p.Node = n
+				break
 			}
-			p.Node = n
-			break
 		}
 	}
 	if p.Node == nil && ni.ip == "127.0.0.1" {
@@ -84,6 +90,22 @@ func (e *Executor) StringOverrideWithNode(s string, ni *nodeInfo) (string, error
 			IP:   "127.0.0.1",
 		}
 	}
+	p.Registered = e.registered(ni)
+	return p, nil
+}
+
+func (e *Executor) StringOverrideWithNode(s string, ni *nodeInfo) (string, error) {
+	if !strings.Contains(s, `\{\{`) {
+		return s, nil
+	}
+	c := s
+	c = strings.ReplaceAll(c, `\{\{`, `{{`)
+	c = strings.ReplaceAll(c, `\}\}`, `}}`)
+
+	p, err := e.nodeParams(ni)
+	if err != nil {
+		return "", err
+	}
 	c, err = eputils.StringTemplateConvertWithParams(c, p)
 	if err != nil {
 		log.Warningf("StringOverrideWithNode error, node: %v, s: %v, err: %v", ni.ip, s, err)
@@ -102,6 +124,9 @@ func (e *Executor) NodeListUpdate(params *pluginapi.KitconfigParameters) error {
 			client: &day0Client{},
 		})
 	}
+	if params.GlobalSettings != nil {
+		eputils.SetHostKeyChecking(params.GlobalSettings.SSHHostKeyChecking)
+	}
 	for _, n := range params.Nodes {
 		if n.IP == "" {
 			log.Infof("node [%v] has no IP, ignore\n", n.Name)
@@ -123,11 +148,16 @@ func (e *Executor) NodeListUpdate(params *pluginapi.KitconfigParameters) error {
 					}
 				}
 			}
+			cert, err := eputils.ReadSSHFile(n.SSHCert, n.SSHCertPath)
+			if err != nil {
+				log.Errorf("Node [%v] ssh cert is not readable\n", n.IP)
+				return err
+			}
 			if n.User == "" {
 				log.Warningf("Node [%v] login username missing\n", n.IP)
 				return eputils.GetError("errNodeLogin")
 			}
-			if key == "" && n.SSHPasswd == "" {
+			if key == "" && n.SSHPasswd == "" && n.SSHAgentSocket == "" {
 				log.Warningf("Node [%v] login password and ssh key missing\n", n.IP)
 				return eputils.GetError("errNodeLoginPassword")
 			}
@@ -139,11 +169,15 @@ func (e *Executor) NodeListUpdate(params *pluginapi.KitconfigParameters) error {
 				name: n.Name,
 				ip:   n.IP,
 				client: &sshClient{
-					host:     n.IP,
-					port:     port,
-					user:     n.User,
-					password: n.SSHPasswd,
-					key:      key,
+					host:         n.IP,
+					port:         port,
+					user:         n.User,
+					password:     n.SSHPasswd,
+					key:          key,
+					cert:         cert,
+					agentSocket:  n.SSHAgentSocket,
+					proxyJump:    n.ProxyJump,
+					fingerprints: n.SSHHostKeyFingerprints,
 				},
 			}
 			e.nodesByIP[n.IP] = node
@@ -209,8 +243,17 @@ func (e *Executor) LoadSpecFromFile(specFile string) error {
 }
 
 func (e *Executor) RunWithAttachIO(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer) error {
+	e.summary = false
+	defer func() {
+		if e.summary {
+			e.printSummary(stderr)
+		}
+	}()
+	failed := false
 	for _, step := range e.Spec.Steps {
 		log.Debugf("step: %v\n", step.Name)
+		e.step = step.Name
+		e.parallel = int(step.Parallel)
 		nodes := map[string]*nodeInfo{}
 		for _, r := range step.Nodes.AnyOf {
 			if nn, has := e.nodesByRole[r]; has {
@@ -244,6 +287,15 @@ func (e *Executor) RunWithAttachIO(ctx context.Context, stdin io.Reader, stdout,
 				}
 			}
 		}
+		// The nodes failed in the steps continuing on error are skipped.
+		for k, n := range nodes {
+			if e.nodeFailed(n) {
+				delete(nodes, k)
+			}
+		}
+		if step.Parallel > 0 || step.ContinueOnError || len(nodes) > 1 {
+			e.summary = true
+		}
 		for _, command := range step.Commands {
 			err := error(nil)
 			cnodes := map[string]*nodeInfo{}
@@ -272,7 +324,12 @@ func (e *Executor) RunWithAttachIO(ctx context.Context, stdin io.Reader, stdout,
 				continue
 			}
 
-			if command.Type == "shell" {
+			if command.Register != "" && (command.Type != "shell" || !registerRegexp.MatchString(command.Register)) {
+				log.Errorf("Invalid register %q of %v command, only shell commands can be registered with a name of letters, digits and \"_\"\n", command.Register, command.Type)
+				err = eputils.GetError("errExecRegister")
+			} else if command.Register != "" {
+				err = e.helperShellRegister(ctx, cnodes, command.Cmd, command.Register)
+			} else if command.Type == "shell" {
 				err = e.helperShell(ctx, cnodes, command.Cmd)
 			} else if command.Type == "copyFromDay0" {
 				err = e.helperCopyFromDay0(ctx, cnodes, command.Cmd)
@@ -286,15 +343,42 @@ func (e *Executor) RunWithAttachIO(ctx context.Context, stdin io.Reader, stdout,
 				err = e.helperPullFile(ctx, cnodes, command.Cmd)
 			} else if command.Type == "createHarborProject" {
 				err = e.helperCreateProjectOnHarbor(ctx, cnodes, command.Cmd)
+			} else if command.Type == "template" {
+				err = e.helperTemplate(ctx, cnodes, command.Cmd)
+			} else if command.Type == "waitFor" {
+				err = e.helperWaitFor(ctx, cnodes, command.Cmd)
+			} else if command.Type == "fileCheck" {
+				err = e.helperFileCheck(ctx, cnodes, command.Cmd)
 			} else {
 				log.Errorf("Unknown command type: %v\n", command.Type)
 				err = eputils.GetError("errUnknownCmdType")
 			}
 			if err != nil {
-				return err
+				if !step.ContinueOnError {
+					return err
+				}
+				log.Warningf("Step %v continues on the other nodes, err: %v\n", step.Name, err)
+				failed = true
+				failedNodes := 0
+				for k, n := range cnodes {
+					if e.nodeFailed(n) {
+						delete(nodes, k)
+						failedNodes++
+					}
+				}
+				// The error is not of a node, so all the nodes failed.
+				if failedNodes == 0 {
+					for k, n := range cnodes {
+						e.recordResult(n, 0, err)
+						delete(nodes, k)
+					}
+				}
 			}
 		}
 	}
+	if failed {
+		return eputils.GetError("errExecNodeFailed")
+	}
 	return nil
 }
 
@@ -15,6 +15,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os/user"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -232,6 +233,35 @@ func TestNodeListUpdate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:        "Has agent socket/cert/proxy jump",
+			expectError: true,
+			kitconfigparameter: &pluginapi.KitconfigParameters{
+				Nodes: []*pluginapi.Node{{
+					IP:             "192.168.1.1",
+					SSHAgentSocket: "$SSH_AUTH_SOCK",
+					SSHCertPath:    "~/.ssh/id_rsa-cert.pub",
+					ProxyJump:      []string{"admin@10.10.10.1"},
+					User:           "sysadmin",
+				},
+				},
+			},
+		},
+		{
+			name:        "Has host key fingerprints",
+			expectError: true,
+			kitconfigparameter: &pluginapi.KitconfigParameters{
+				GlobalSettings: &pluginapi.KitconfigParametersGlobalSettings{
+					SSHHostKeyChecking: pluginapi.KitconfigParametersGlobalSettingsSSHHostKeyCheckingStrict,
+				},
+				Nodes: []*pluginapi.Node{{
+					IP:                     "192.168.1.1",
+					SSHHostKeyFingerprints: []string{"SHA256:xxxxxxxxxxxxxxxx"},
+					User:                   "sysadmin",
+				},
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -268,6 +298,24 @@ func TestNodeListUpdate(t *testing.T) {
 	}
 }
 
+func TestNodeListUpdateCertNotReadable(t *testing.T) {
+	executor := New()
+	err := executor.NodeListUpdate(&pluginapi.KitconfigParameters{
+		Nodes: []*pluginapi.Node{{
+			IP:          "192.168.1.1",
+			SSHPasswd:   "password",
+			SSHCertPath: filepath.Join(t.TempDir(), "id_rsa-cert.pub"),
+			User:        "sysadmin",
+		}},
+	})
+	if err == nil {
+		t.Error("Expected an error for an unreadable ssh cert")
+	}
+	if _, has := executor.nodesByIP["192.168.1.1"]; has {
+		t.Error("The node with an unreadable ssh cert is added")
+	}
+}
+
 func patchReadFile(t *testing.T, fail bool) *mpatch.Patch {
 	patch, patchErr := mpatch.PatchMethod(ioutil.ReadFile, func(filename string) ([]byte, error) {
 		if fail {
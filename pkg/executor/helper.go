@@ -8,6 +8,7 @@
 package executor
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"github.com/intel/edge-conductor/pkg/eputils"
@@ -16,16 +17,34 @@ import (
 	repoutils "github.com/intel/edge-conductor/pkg/eputils/repoutils"
 	restfulcli "github.com/intel/edge-conductor/pkg/eputils/restfulcli"
 	"io"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/url"
 	"os"
 	"path"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 )
 
 var (
 	DayZeroCertFilePath = "cert/pki/ca.pem"
+
+	// waitForInterval is the interval between the checks of waitFor.
+	waitForInterval       = 5 * time.Second
+	defaultWaitForTimeout = 5 * time.Minute
+
+	ownerRegexp    = regexp.MustCompile(`^[a-zA-Z0-9_.-]+(:[a-zA-Z0-9_.-]+)?$`)
+	registerRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	checksumCmds   = map[string]string{
+		"sha256": "sha256sum",
+		"sha512": "sha512sum",
+	}
 )
 
 func (e *Executor) CmdOverrideWithNode(cmd []string, ni *nodeInfo) ([]string, error) {
@@ -42,123 +61,161 @@ func (e *Executor) CmdOverrideWithNode(cmd []string, ni *nodeInfo) ([]string, er
 	return newCmd, nil
 }
 
-func (e *Executor) runPipeFrom(ctx context.Context, nodes map[string]*nodeInfo, cmd []string, from *nodeInfo, fromCmd []string) error {
-	finalErr := error(nil)
-	wg := sync.WaitGroup{}
-	wg.Add(len(nodes))
-	for _, n := range nodes {
-		n := n
-		cmd, err := e.CmdOverrideWithNode(cmd, n)
+// nodeArgs returns the arguments of a command built by a helper for the node.
+// The ssh nodes run the command line with the remote shell, so each argument
+// is shell-quoted to reach the command as it is. The day-0 node runs the
+// arguments without a shell.
+func nodeArgs(n *nodeInfo, args ...string) []string {
+	if _, ok := n.client.(*sshClient); !ok {
+		return args
+	}
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = shellQuote(a)
+	}
+	return quoted
+}
+
+func (e *Executor) cmdsOverrideWithNodes(nodes map[string]*nodeInfo, cmd []string) (map[string][]string, error) {
+	cmds := map[string][]string{}
+	for k, n := range nodes {
+		c, err := e.CmdOverrideWithNode(cmd, n)
 		if err != nil {
+			return nil, err
+		}
+		cmds[k] = c
+	}
+	return cmds, nil
+}
+
+func (e *Executor) runPipeFrom(ctx context.Context, nodes map[string]*nodeInfo, cmd []string, from *nodeInfo, fromCmd []string) error {
+	cmds, err := e.cmdsOverrideWithNodes(nodes, cmd)
+	if err != nil {
+		return err
+	}
+	return e.runOnNodes(nodes, func(k string, n *nodeInfo, tail io.Writer) error {
+		stderr := io.MultiWriter(os.Stderr, tail)
+		if err := n.client.Connect(); err != nil {
 			return err
 		}
+		fromErr := error(nil)
+		r, w := io.Pipe()
+		wg := sync.WaitGroup{}
+		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			if err := n.client.Connect(); err != nil {
-				finalErr = err
-				return
+			if err := from.client.CmdWithAttachIO(ctx, fromCmd,
+				nil, w, stderr, false); err != nil {
+				fromErr = err
 			}
-			r, w := io.Pipe()
-			wg.Add(1)
-			go func() {
-				defer wg.Done()
-				if err := from.client.CmdWithAttachIO(ctx, fromCmd,
-					nil, w, os.Stderr, false); err != nil {
-					finalErr = err
-				}
-				if err := w.Close(); err != nil {
-					finalErr = err
-				}
-			}()
-			if err := n.client.CmdWithAttachIO(ctx, cmd,
-				r, os.Stdout, os.Stderr, false); err != nil {
-				finalErr = err
-				return
-			}
-			if err := n.client.Disconnect(); err != nil {
-				finalErr = err
-				return
+			if err := w.Close(); err != nil {
+				fromErr = err
 			}
 		}()
-	}
-	wg.Wait()
-	return finalErr
+		err := n.client.CmdWithAttachIO(ctx, cmds[k],
+			r, os.Stdout, stderr, false)
+		if err := r.Close(); err != nil {
+			log.Errorf("Failed to close io pipe.")
+		}
+		wg.Wait()
+		if err != nil {
+			return err
+		}
+		if fromErr != nil {
+			return fromErr
+		}
+		return n.client.Disconnect()
+	})
 }
 
 func (e *Executor) runPipeTo(ctx context.Context, nodes map[string]*nodeInfo, cmd []string, to *nodeInfo, toCmd []string) error {
-	finalErr := error(nil)
-	wg := sync.WaitGroup{}
-	wg.Add(len(nodes))
-	for _, n := range nodes {
-		n := n
-		cmd, err := e.CmdOverrideWithNode(cmd, n)
-		if err != nil {
+	cmds, err := e.cmdsOverrideWithNodes(nodes, cmd)
+	if err != nil {
+		return err
+	}
+	return e.runOnNodes(nodes, func(k string, n *nodeInfo, tail io.Writer) error {
+		stderr := io.MultiWriter(os.Stderr, tail)
+		if err := n.client.Connect(); err != nil {
 			return err
 		}
+		toErr := error(nil)
+		r, w := io.Pipe()
+		wg := sync.WaitGroup{}
+		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			if err := n.client.Connect(); err != nil {
-				finalErr = err
-				return
+			if err := to.client.CmdWithAttachIO(ctx, toCmd,
+				r, os.Stdout, stderr, false); err != nil {
+				toErr = err
 			}
-			r, w := io.Pipe()
-			defer func() {
-				if err := w.Close(); err != nil {
-					log.Errorf("Failed to close io pipe.")
-				}
-			}()
-			wg.Add(1)
-			go func() {
-				defer wg.Done()
-				if err := to.client.CmdWithAttachIO(ctx, toCmd,
-					r, os.Stdout, os.Stderr, false); err != nil {
-					finalErr = err
-				}
-			}()
-			if err := n.client.CmdWithAttachIO(ctx, cmd,
-				nil, w, os.Stderr, false); err != nil {
-				finalErr = err
-				return
-			}
-			if err := n.client.Disconnect(); err != nil {
-				finalErr = err
-				return
+			if err := r.Close(); err != nil {
+				log.Errorf("Failed to close io pipe.")
 			}
 		}()
-	}
-	wg.Wait()
-	return finalErr
+		err := n.client.CmdWithAttachIO(ctx, cmds[k],
+			nil, w, stderr, false)
+		if err := w.Close(); err != nil {
+			log.Errorf("Failed to close io pipe.")
+		}
+		wg.Wait()
+		if err != nil {
+			return err
+		}
+		if toErr != nil {
+			return toErr
+		}
+		return n.client.Disconnect()
+	})
 }
 
 func (e *Executor) helperShell(ctx context.Context, nodes map[string]*nodeInfo, cmd []string) error {
 	log.Debugf("cmd: %v", strings.Join(cmd, "@"))
-	finalErr := error(nil)
-	wg := sync.WaitGroup{}
-	wg.Add(len(nodes))
-	for _, n := range nodes {
-		n := n
-		cmd, err := e.CmdOverrideWithNode(cmd, n)
-		if err != nil {
+	cmds, err := e.cmdsOverrideWithNodes(nodes, cmd)
+	if err != nil {
+		return err
+	}
+	return e.runOnNodes(nodes, func(k string, n *nodeInfo, tail io.Writer) error {
+		if err := n.client.Connect(); err != nil {
 			return err
 		}
-		go func() {
-			defer wg.Done()
-			if err := n.client.Connect(); err != nil {
-				finalErr = err
-				return
-			}
-			if err := n.client.CmdWithAttachIO(ctx, cmd, nil, os.Stdout, os.Stderr, true); err != nil {
-				finalErr = err
-				return
-			}
-			if err := n.client.Disconnect(); err != nil {
-				finalErr = err
-				return
-			}
-		}()
+		// The output of the command is on a tty, where stderr is merged
+		// into stdout on the ssh nodes, so keep the tail of both.
+		if err := n.client.CmdWithAttachIO(ctx, cmds[k], nil,
+			io.MultiWriter(os.Stdout, tail), io.MultiWriter(os.Stderr, tail), true); err != nil {
+			return err
+		}
+		return n.client.Disconnect()
+	})
+}
+
+// helperShellRegister runs the shell command as helperShell does, and
+// registers its output and exit code on each node as name. The command runs
+// without a tty to keep stdout and stderr apart, and a failed exit code is
+// registered instead of failing the command.
+func (e *Executor) helperShellRegister(ctx context.Context, nodes map[string]*nodeInfo, cmd []string, name string) error {
+	log.Debugf("cmd: %v, register: %v", strings.Join(cmd, "@"), name)
+	cmds, err := e.cmdsOverrideWithNodes(nodes, cmd)
+	if err != nil {
+		return err
 	}
-	wg.Wait()
-	return finalErr
+	return e.runOnNodes(nodes, func(k string, n *nodeInfo, tail io.Writer) error {
+		if err := n.client.Connect(); err != nil {
+			return err
+		}
+		stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+		err := n.client.CmdWithAttachIO(ctx, cmds[k], nil,
+			io.MultiWriter(os.Stdout, &stdout, tail), io.MultiWriter(os.Stderr, &stderr, tail), false)
+		code := exitCode(err)
+		if code < 0 {
+			return err
+		}
+		e.register(n, name, &RegisteredResult{
+			Stdout:   strings.TrimRight(stdout.String(), "\r\n"),
+			Stderr:   strings.TrimRight(stderr.String(), "\r\n"),
+			ExitCode: code,
+		})
+		return n.client.Disconnect()
+	})
 }
 
 func (e *Executor) helperCopyFromDay0(ctx context.Context, nodes map[string]*nodeInfo, cmd []string) error {
@@ -335,3 +392,224 @@ func (e *Executor) helperPullFile(ctx context.Context, nodes map[string]*nodeInf
 
 	return nil
 }
+
+// helperTemplate renders the local template cmd[0] with the parameters of
+// each node, and writes it to cmd[1] on the node with the optional mode
+// cmd[2] and owner cmd[3]. The file is written as root if the owner is set.
+func (e *Executor) helperTemplate(ctx context.Context, nodes map[string]*nodeInfo, cmd []string) error {
+	if len(cmd) < 2 || len(cmd) > 4 {
+		log.Errorf("template: <template> <path> [mode] [owner] is expected, got %v", cmd)
+		return eputils.GetError("errExecTemplate")
+	}
+	cmds, err := e.cmdsOverrideWithNodes(nodes, cmd)
+	if err != nil {
+		return err
+	}
+	return e.runOnNodes(nodes, func(k string, n *nodeInfo, tail io.Writer) error {
+		c := cmds[k]
+		mode, owner := "", ""
+		if len(c) > 2 {
+			mode = c[2]
+		}
+		if len(c) > 3 {
+			owner = c[3]
+		}
+		if _, err := strconv.ParseUint(mode, 8, 32); mode != "" && err != nil {
+			log.Errorf("template: invalid mode %q", mode)
+			return eputils.GetError("errExecTemplate")
+		}
+		if owner != "" && !ownerRegexp.MatchString(owner) {
+			log.Errorf("template: invalid owner %q", owner)
+			return eputils.GetError("errExecTemplate")
+		}
+
+		data, err := ioutil.ReadFile(c[0])
+		if err != nil {
+			log.Errorf("template: failed to read %s: %v", c[0], err)
+			return err
+		}
+		err = eputils.CheckHashForContent(data, c[0], "")
+		if err != nil {
+			if err.Error() == eputils.ERRORCODE_CHECK_HASH_FAIL {
+				log.Errorln("ERROR: Failed to check executor template hash:", c[0])
+			}
+			return err
+		}
+		p, err := e.nodeParams(n)
+		if err != nil {
+			return err
+		}
+		content, err := eputils.StringTemplateConvertWithParams(string(data), p)
+		if err != nil {
+			log.Errorf("template: failed to render %s for node %s: %v", c[0], n.ip, err)
+			return err
+		}
+
+		if err := n.client.Connect(); err != nil {
+			return err
+		}
+		stderr := io.MultiWriter(os.Stderr, tail)
+		run := func(stdin io.Reader, args ...string) error {
+			if owner != "" {
+				args = append([]string{"sudo"}, args...)
+			}
+			return n.client.CmdWithAttachIO(ctx, nodeArgs(n, args...), stdin, ioutil.Discard, stderr, false)
+		}
+		if err := run(strings.NewReader(content), "tee", c[1]); err != nil {
+			return err
+		}
+		if mode != "" {
+			if err := run(nil, "chmod", mode, c[1]); err != nil {
+				return err
+			}
+		}
+		if owner != "" {
+			if err := run(nil, "chown", owner, c[1]); err != nil {
+				return err
+			}
+		}
+		return n.client.Disconnect()
+	})
+}
+
+// helperWaitFor checks a condition on each node until it is met or the
+// timeout cmd[1] expires. With cmd[0] "tcp" the node connects to the address
+// cmd[2] with nc, with "http" the node gets the URL cmd[2] with curl, and with
+// "shell" the command cmd[2:] runs on the node.
+func (e *Executor) helperWaitFor(ctx context.Context, nodes map[string]*nodeInfo, cmd []string) error {
+	if len(cmd) < 3 {
+		log.Errorf("waitFor: <tcp|http|shell> <timeout> <target> is expected, got %v", cmd)
+		return eputils.GetError("errWaitFor")
+	}
+	timeout := defaultWaitForTimeout
+	if cmd[1] != "" {
+		d, err := time.ParseDuration(cmd[1])
+		if err != nil || d <= 0 {
+			log.Errorf("waitFor: invalid timeout %q", cmd[1])
+			return eputils.GetError("errWaitFor")
+		}
+		timeout = d
+	}
+	switch cmd[0] {
+	case "tcp", "http", "shell":
+	default:
+		log.Errorf("waitFor: unknown condition type %q", cmd[0])
+		return eputils.GetError("errWaitFor")
+	}
+	cmds, err := e.cmdsOverrideWithNodes(nodes, cmd)
+	if err != nil {
+		return err
+	}
+	// Each check of the node gives up after the interval.
+	checkTimeout := strconv.Itoa(int(math.Ceil(waitForInterval.Seconds())))
+
+	return e.runOnNodes(nodes, func(k string, n *nodeInfo, tail io.Writer) error {
+		c := cmds[k]
+		var probe []string
+		switch c[0] {
+		case "tcp":
+			host, port, err := net.SplitHostPort(c[2])
+			if err != nil {
+				log.Errorf("waitFor: invalid address %q", c[2])
+				return eputils.GetError("errWaitFor")
+			}
+			probe = nodeArgs(n, "nc", "-z", "-w", checkTimeout, host, port)
+		case "http":
+			if u, err := url.Parse(c[2]); err != nil || u.Host == "" {
+				log.Errorf("waitFor: invalid URL %q", c[2])
+				return eputils.GetError("errWaitFor")
+			}
+			probe = nodeArgs(n, "curl", "-fsS", "-o", "/dev/null", "--max-time", checkTimeout, c[2])
+		case "shell":
+			probe = c[2:]
+		}
+		if err := n.client.Connect(); err != nil {
+			return err
+		}
+		defer func() {
+			if err := n.client.Disconnect(); err != nil {
+				log.Warnf("Failed to disconnect from node %s: %v", n.ip, err)
+			}
+		}()
+		check := func() error {
+			return n.client.CmdWithAttachIO(ctx, probe, nil, tail, tail, false)
+		}
+
+		deadline := time.Now().Add(timeout)
+		for {
+			err := check()
+			if err == nil {
+				log.Infof("waitFor %v is met on node %s(%s)", c, n.name, n.ip)
+				return nil
+			}
+			log.Debugf("waitFor %v on node %s: %v", c, n.ip, err)
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				fmt.Fprintf(tail, "%v\n", err)
+				log.Errorf("waitFor %v timed out after %v on node %s(%s)", c, timeout, n.name, n.ip)
+				return eputils.GetError("errWaitForTimeout")
+			}
+			if remaining > waitForInterval {
+				remaining = waitForInterval
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(remaining):
+			}
+		}
+	})
+}
+
+// helperFileCheck checks that the path cmd[0] exists on each node and, if
+// cmd[1] is set, that its checksum matches. The checksum is the hex digest,
+// optionally prefixed with "sha256:" (default) or "sha512:".
+func (e *Executor) helperFileCheck(ctx context.Context, nodes map[string]*nodeInfo, cmd []string) error {
+	if len(cmd) < 1 || len(cmd) > 2 {
+		log.Errorf("fileCheck: <path> [checksum] is expected, got %v", cmd)
+		return eputils.GetError("errFileCheck")
+	}
+	cmds, err := e.cmdsOverrideWithNodes(nodes, cmd)
+	if err != nil {
+		return err
+	}
+	return e.runOnNodes(nodes, func(k string, n *nodeInfo, tail io.Writer) error {
+		c := cmds[k]
+		sumCmd, want := "", ""
+		if len(c) == 2 && c[1] != "" {
+			algo, sum := "sha256", c[1]
+			if i := strings.Index(sum, ":"); i >= 0 {
+				algo, sum = sum[:i], sum[i+1:]
+			}
+			has := false
+			if sumCmd, has = checksumCmds[algo]; !has {
+				log.Errorf("fileCheck: unsupported checksum %q", c[1])
+				return eputils.GetError("errFileCheck")
+			}
+			want = strings.ToLower(sum)
+		}
+
+		if err := n.client.Connect(); err != nil {
+			return err
+		}
+		stderr := io.MultiWriter(os.Stderr, tail)
+		if want == "" {
+			if err := n.client.CmdWithAttachIO(ctx, nodeArgs(n, "test", "-e", c[0]), nil, tail, stderr, false); err != nil {
+				fmt.Fprintf(tail, "%s is not found\n", c[0])
+				log.Errorf("fileCheck: %s is not found on node %s(%s)", c[0], n.name, n.ip)
+				return eputils.GetError("errFileCheck")
+			}
+		} else {
+			out := bytes.Buffer{}
+			if err := n.client.CmdWithAttachIO(ctx, nodeArgs(n, sumCmd, c[0]), nil, &out, stderr, false); err != nil {
+				return err
+			}
+			if got := strings.Fields(out.String()); len(got) == 0 || got[0] != want {
+				fmt.Fprintf(tail, "%s checksum mismatch\n", c[0])
+				log.Errorf("fileCheck: %s checksum on node %s(%s) is %q, want %q", c[0], n.name, n.ip, out.String(), want)
+				return eputils.GetError("errFileCheck")
+			}
+		}
+		return n.client.Disconnect()
+	})
+}
@@ -9,15 +9,25 @@ package executor
 
 import (
 	"context"
+	"crypto/sha256"
 	"errors"
+	"fmt"
 	"github.com/intel/edge-conductor/pkg/api/ep"
 	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	"github.com/intel/edge-conductor/pkg/eputils"
 	"github.com/intel/edge-conductor/pkg/eputils/docker"
 	repoutils "github.com/intel/edge-conductor/pkg/eputils/repoutils"
 	"github.com/intel/edge-conductor/pkg/eputils/restfulcli"
 	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/undefinedlabs/go-mpatch"
@@ -925,3 +935,266 @@ func TestHelperCopyFromDay0(t *testing.T) {
 		})
 	}
 }
+
+var local_nodes = map[string]*nodeInfo{
+	"127.0.0.1": {
+		name:   "day-0",
+		ip:     "127.0.0.1",
+		client: &day0Client{},
+	},
+}
+
+func TestHelperTemplate(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := filepath.Join(dir, "kubelet.service.tmpl")
+	if err := ioutil.WriteFile(tmpl, []byte("IP={{ .Node.IP }}\nName={{ .Value }}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	dest := filepath.Join(dir, "kubelet.service")
+
+	var cases = []struct {
+		name        string
+		cmd         []string
+		expectError error
+		wantContent string
+		wantMode    os.FileMode
+	}{
+		{
+			name:        "render with mode",
+			cmd:         []string{tmpl, dest, "0640"},
+			wantContent: "IP=127.0.0.1\nName=kubelet\n",
+			wantMode:    0640,
+		},
+		{
+			name:        "missing path",
+			cmd:         []string{tmpl},
+			expectError: eputils.GetError("errExecTemplate"),
+		},
+		{
+			name:        "invalid mode",
+			cmd:         []string{tmpl, dest, "rwx"},
+			expectError: eputils.GetError("errExecTemplate"),
+		},
+		{
+			name:        "invalid owner",
+			cmd:         []string{tmpl, dest, "0644", "root;reboot"},
+			expectError: eputils.GetError("errExecTemplate"),
+		},
+		{
+			name:        "template not found",
+			cmd:         []string{tmpl + ".notfound", dest},
+			expectError: os.ErrNotExist,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			executor := &Executor{tempParams: tempParameter{Value: "kubelet"}}
+			err := executor.helperTemplate(context.TODO(), local_nodes, tc.cmd)
+			if !errors.Is(err, tc.expectError) {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+			content, err := ioutil.ReadFile(dest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tc.wantContent {
+				t.Errorf("Unexpected content: %q", content)
+			}
+			if fi, err := os.Stat(dest); err != nil || fi.Mode().Perm() != tc.wantMode {
+				t.Errorf("Unexpected mode: %v, %v", fi.Mode(), err)
+			}
+		})
+	}
+}
+
+func TestHelperWaitFor(t *testing.T) {
+	savedInterval := waitForInterval
+	waitForInterval = 10 * time.Millisecond
+	defer func() { waitForInterval = savedInterval }()
+
+	ready := time.Now().Add(50 * time.Millisecond)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/notready" {
+			w.WriteHeader(http.StatusNotFound)
+		} else if time.Now().Before(ready) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	var cases = []struct {
+		name        string
+		cmd         []string
+		expectError error
+	}{
+		{"tcp invalid address", []string{"tcp", "1s", "localhost"}, eputils.GetError("errWaitFor")},
+		{"http ready later", []string{"http", "", srv.URL}, nil},
+		{"http url with query", []string{"http", "1s", srv.URL + "/?a=1&b=2"}, nil},
+		{"http timeout", []string{"http", "50ms", srv.URL + "/notready"}, eputils.GetError("errWaitForTimeout")},
+		{"http invalid url", []string{"http", "1s", "localhost"}, eputils.GetError("errWaitFor")},
+		{"shell", []string{"shell", "1s", "test", "-d", "/"}, nil},
+		{"shell timeout", []string{"shell", "50ms", "false"}, eputils.GetError("errWaitForTimeout")},
+		{"invalid timeout", []string{"shell", "soon", "true"}, eputils.GetError("errWaitFor")},
+		{"unknown type", []string{"udp", "1s", "localhost:53"}, eputils.GetError("errWaitFor")},
+		{"missing target", []string{"tcp", "1s"}, eputils.GetError("errWaitFor")},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			executor := &Executor{}
+			err := executor.helperWaitFor(context.TODO(), local_nodes, tc.cmd)
+			if err != tc.expectError {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestHelperWaitForProbe(t *testing.T) {
+	savedInterval := waitForInterval
+	waitForInterval = 10 * time.Millisecond
+	defer func() { waitForInterval = savedInterval }()
+
+	var cases = []struct {
+		name        string
+		cmd         []string
+		fail        string
+		wantProbe   string
+		expectError error
+	}{
+		{"tcp", []string{"tcp", "1s", "10.0.0.100:6443"}, "", "nc -z -w 1 10.0.0.100 6443", nil},
+		{"tcp timeout", []string{"tcp", "50ms", "10.0.0.100:6443"}, "nc", "nc -z -w 1 10.0.0.100 6443", eputils.GetError("errWaitForTimeout")},
+		{"http", []string{"http", "1s", "https://10.0.0.100:9000/healthz"}, "", "curl -fsS -o /dev/null --max-time 1 https://10.0.0.100:9000/healthz", nil},
+		{"http timeout", []string{"http", "50ms", "https://10.0.0.100:9000/healthz"}, "curl", "curl -fsS -o /dev/null --max-time 1 https://10.0.0.100:9000/healthz", eputils.GetError("errWaitForTimeout")},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			nodesByRole, _, cmds := newFakeNodes(1, map[string]map[string]bool{"10.0.0.1": {tc.fail: true}})
+			executor := &Executor{}
+			nodes := map[string]*nodeInfo{"10.0.0.1": nodesByRole["worker"][0]}
+			err := executor.helperWaitFor(context.TODO(), nodes, tc.cmd)
+			if err != tc.expectError {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if len(*cmds) == 0 || (*cmds)[0] != tc.wantProbe {
+				t.Errorf("Unexpected probes on the node: %v, want %s", *cmds, tc.wantProbe)
+			}
+		})
+	}
+}
+
+func TestHelperFileCheck(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "kubelet")
+	if err := ioutil.WriteFile(file, []byte("kubelet"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	spaced := filepath.Join(t.TempDir(), "my kubelet")
+	if err := ioutil.WriteFile(spaced, []byte("kubelet"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	sum := fmt.Sprintf("%x", sha256.Sum256([]byte("kubelet")))
+
+	var cases = []struct {
+		name        string
+		cmd         []string
+		expectError error
+	}{
+		{"exists", []string{file}, nil},
+		{"not found", []string{file + ".notfound"}, eputils.GetError("errFileCheck")},
+		{"path with space", []string{spaced}, nil},
+		{"checksum", []string{file, sum}, nil},
+		{"checksum of path with space", []string{spaced, sum}, nil},
+		{"prefixed checksum", []string{file, "sha256:" + sum}, nil},
+		{"checksum mismatch", []string{file, "sha256:" + sum[1:]}, eputils.GetError("errFileCheck")},
+		{"unsupported checksum", []string{file, "crc32:" + sum}, eputils.GetError("errFileCheck")},
+		{"missing path", []string{}, eputils.GetError("errFileCheck")},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			executor := &Executor{}
+			err := executor.helperFileCheck(context.TODO(), local_nodes, tc.cmd)
+			if err != tc.expectError {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestHelperSSHQuoting(t *testing.T) {
+	savedInterval := waitForInterval
+	waitForInterval = 10 * time.Millisecond
+	defer func() { waitForInterval = savedInterval }()
+
+	var cmds []string
+	patch, err := mpatch.PatchInstanceMethodByName(reflect.TypeOf(&sshClient{}), "CmdWithAttachIO", func(s *sshClient, ctx context.Context, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error {
+		cmds = append(cmds, strings.Join(cmd, " "))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pList := []*mpatch.Patch{patch, patchConnect(t, false), patchDisconnect(t, false)}
+	defer unpatchAll(t, pList)
+
+	tmpl := filepath.Join(t.TempDir(), "kubelet.service.tmpl")
+	if err := ioutil.WriteFile(tmpl, []byte("Name={{ .Value }}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	nodes := map[string]*nodeInfo{"10.0.0.1": {name: "node1", ip: "10.0.0.1", client: &sshClient{}}}
+
+	var cases = []struct {
+		name     string
+		run      func(e *Executor) error
+		wantCmds []string
+	}{
+		{
+			name: "waitFor http",
+			run: func(e *Executor) error {
+				return e.helperWaitFor(context.TODO(), nodes, []string{"http", "1s", "http://10.0.0.100/healthz?a=1&b=2"})
+			},
+			wantCmds: []string{"'curl' '-fsS' '-o' '/dev/null' '--max-time' '1' 'http://10.0.0.100/healthz?a=1&b=2'"},
+		},
+		{
+			name: "waitFor shell",
+			run: func(e *Executor) error {
+				return e.helperWaitFor(context.TODO(), nodes, []string{"shell", "1s", "test", "-d", "/"})
+			},
+			wantCmds: []string{"test -d /"},
+		},
+		{
+			name: "template",
+			run: func(e *Executor) error {
+				return e.helperTemplate(context.TODO(), nodes, []string{tmpl, "/etc/my dir/kubelet's.service", "0640"})
+			},
+			wantCmds: []string{
+				"'tee' '/etc/my dir/kubelet'\\''s.service'",
+				"'chmod' '0640' '/etc/my dir/kubelet'\\''s.service'",
+			},
+		},
+		{
+			name: "fileCheck",
+			run: func(e *Executor) error {
+				return e.helperFileCheck(context.TODO(), nodes, []string{"/opt/my dir/kubelet"})
+			},
+			wantCmds: []string{"'test' '-e' '/opt/my dir/kubelet'"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmds = nil
+			if err := tc.run(&Executor{}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(cmds, tc.wantCmds) {
+				t.Errorf("Unexpected commands on the ssh node: %q, want %q", cmds, tc.wantCmds)
+			}
+		})
+	}
+}
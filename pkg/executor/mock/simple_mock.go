@@ -17,6 +17,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	plugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	executor "github.com/intel/edge-conductor/pkg/executor"
 )
 
 // MockExecutorWrapper is a mock of ExecutorWrapper interface.
@@ -56,6 +57,21 @@ func (mr *MockExecutorWrapperMockRecorder) Run(arg0, arg1, arg2 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockExecutorWrapper)(nil).Run), arg0, arg1, arg2)
 }
 
+// RunWithRegistered mocks base method.
+func (m *MockExecutorWrapper) RunWithRegistered(arg0 string, arg1 *plugins.EpParams, arg2 interface{}) (executor.Registered, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunWithRegistered", arg0, arg1, arg2)
+	ret0, _ := ret[0].(executor.Registered)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunWithRegistered indicates an expected call of RunWithRegistered.
+func (mr *MockExecutorWrapperMockRecorder) RunWithRegistered(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunWithRegistered", reflect.TypeOf((*MockExecutorWrapper)(nil).RunWithRegistered), arg0, arg1, arg2)
+}
+
 // SimpleShell mocks base method.
 func (m *MockExecutorWrapper) SimpleShell(arg0 *plugins.ExecSimpleShell, arg1 *plugins.EpParams) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,300 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// tailSize is the size of the output kept for each node.
+	tailSize = 1024
+	// tailLines is the number of output lines reported for a failed node.
+	tailLines = 5
+)
+
+// tailBuffer keeps the last bytes written to it.
+type tailBuffer struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > tailSize {
+		b.buf = b.buf[len(b.buf)-tailSize:]
+	}
+	return len(p), nil
+}
+
+// lines returns the last n non-empty lines written.
+func (b *tailBuffer) lines(n int) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	lines := []string{}
+	for _, l := range strings.Split(strings.ReplaceAll(string(b.buf), "\r", ""), "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// RegisteredResult is the output of a shell command registered on a node.
+type RegisteredResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// Registered are the registered results by node IP and register name.
+type Registered map[string]map[string]*RegisteredResult
+
+// nodeResult is the result of the commands run on a node.
+type nodeResult struct {
+	name       string
+	ip         string
+	step       string
+	exitCode   int
+	duration   time.Duration
+	err        error
+	tail       tailBuffer
+	registered map[string]*RegisteredResult
+}
+
+func (r *nodeResult) status() string {
+	if r.err != nil {
+		return "Failed"
+	}
+	return "Succeeded"
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var sshErr *ssh.ExitError
+	if errors.As(err, &sshErr) {
+		return sshErr.ExitStatus()
+	}
+	var execErr *exec.ExitError
+	if errors.As(err, &execErr) {
+		return execErr.ExitCode()
+	}
+	return -1
+}
+
+// nodeResults are the results of the nodes by IP.
+type nodeResults struct {
+	sync.Mutex
+	m map[string]*nodeResult
+}
+
+// initResults initializes the results before the commands run on the nodes.
+func (e *Executor) initResults() {
+	if e.results == nil {
+		e.results = &nodeResults{m: map[string]*nodeResult{}}
+	}
+}
+
+func (e *Executor) nodeResult(n *nodeInfo) *nodeResult {
+	e.results.Lock()
+	defer e.results.Unlock()
+	r, has := e.results.m[n.ip]
+	if !has {
+		r = &nodeResult{name: n.name, ip: n.ip}
+		e.results.m[n.ip] = r
+	}
+	return r
+}
+
+// recordResult adds the duration of a command to the result of the node,
+// which keeps the step and the exit code of the first failed command.
+func (e *Executor) recordResult(n *nodeInfo, duration time.Duration, err error) {
+	r := e.nodeResult(n)
+	e.results.Lock()
+	defer e.results.Unlock()
+	r.duration += duration
+	if err != nil && r.err == nil {
+		r.err = err
+		r.step = e.step
+		r.exitCode = exitCode(err)
+	}
+}
+
+// register keeps the result of the command registered as name on the node.
+func (e *Executor) register(n *nodeInfo, name string, rr *RegisteredResult) {
+	e.initResults()
+	r := e.nodeResult(n)
+	e.results.Lock()
+	defer e.results.Unlock()
+	if r.registered == nil {
+		r.registered = map[string]*RegisteredResult{}
+	}
+	r.registered[name] = rr
+}
+
+// registered returns the results registered on the node by name.
+func (e *Executor) registered(n *nodeInfo) map[string]*RegisteredResult {
+	e.initResults()
+	e.results.Lock()
+	defer e.results.Unlock()
+	registered := map[string]*RegisteredResult{}
+	if r, has := e.results.m[n.ip]; has {
+		for name, rr := range r.registered {
+			registered[name] = rr
+		}
+	}
+	return registered
+}
+
+// Registered returns the results of the registered commands of all the nodes.
+func (e *Executor) Registered() Registered {
+	e.initResults()
+	e.results.Lock()
+	defer e.results.Unlock()
+	registered := Registered{}
+	for ip, r := range e.results.m {
+		if len(r.registered) == 0 {
+			continue
+		}
+		registered[ip] = map[string]*RegisteredResult{}
+		for name, rr := range r.registered {
+			registered[ip][name] = rr
+		}
+	}
+	return registered
+}
+
+func (e *Executor) nodeFailed(n *nodeInfo) bool {
+	e.initResults()
+	e.results.Lock()
+	defer e.results.Unlock()
+	r, has := e.results.m[n.ip]
+	return has && r.err != nil
+}
+
+// runOnNodes runs f on the nodes, at most e.parallel nodes at the same time
+// or one after the other if it is not set, and returns the first error. f
+// gets the writer keeping the tail of the node output.
+func (e *Executor) runOnNodes(nodes map[string]*nodeInfo, f func(k string, n *nodeInfo, tail io.Writer) error) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	e.initResults()
+	parallel := e.parallel
+	if parallel <= 0 {
+		parallel = 1
+	}
+	if parallel > len(nodes) {
+		parallel = len(nodes)
+	}
+
+	finalErr := error(nil)
+	mu := sync.Mutex{}
+	sem := make(chan struct{}, parallel)
+	wg := sync.WaitGroup{}
+	wg.Add(len(nodes))
+	for k, n := range nodes {
+		k, n := k, n
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			start := time.Now()
+			err := f(k, n, &e.nodeResult(n).tail)
+			e.recordResult(n, time.Since(start), err)
+			if err != nil {
+				log.Errorf("Node %s(%s) failed: %v", n.name, n.ip, err)
+				mu.Lock()
+				if finalErr == nil {
+					finalErr = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return finalErr
+}
+
+// printSummary prints the result of each node the commands ran on to w, or
+// stderr if w is nil, so that stdout is kept for the output of the workflow.
+func (e *Executor) printSummary(w io.Writer) {
+	if e.results == nil {
+		return
+	}
+	e.results.Lock()
+	defer e.results.Unlock()
+	if len(e.results.m) == 0 {
+		return
+	}
+	if w == nil {
+		w = os.Stderr
+	}
+	results := []*nodeResult{}
+	for _, r := range e.results.m {
+		results = append(results, r)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].ip < results[j].ip
+	})
+
+	const padding = 3
+	tw := tabwriter.NewWriter(w, 0, 0, padding, ' ', 0)
+	fmt.Fprintln(tw, "")
+	fmt.Fprintln(tw, "\tNODE\tIP\tSTATUS\tSTEP\tEXIT CODE\tDURATION\tOUTPUT\t")
+	fmt.Fprintln(tw, "\t====\t==\t======\t====\t=========\t========\t======\t")
+	for _, r := range results {
+		output := ""
+		if r.err != nil {
+			output = strings.Join(r.tail.lines(1), "")
+		}
+		fmt.Fprintf(tw, "\t%s\t%s\t%s\t%s\t%d\t%s\t%s\t\n",
+			r.name,
+			r.ip,
+			r.status(),
+			r.step,
+			r.exitCode,
+			r.duration.Round(time.Millisecond),
+			output)
+	}
+	fmt.Fprintln(tw, "")
+	if err := tw.Flush(); err != nil {
+		log.Warnf("Failed to print the node summary: %v", err)
+	}
+
+	for _, r := range results {
+		if r.err == nil {
+			continue
+		}
+		log.Errorf("Node %s(%s) failed at step %q with exit code %d: %v", r.name, r.ip, r.step, r.exitCode, r.err)
+		for _, l := range r.tail.lines(tailLines) {
+			log.Errorf("  %s", l)
+		}
+	}
+}
@@ -0,0 +1,365 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/intel/edge-conductor/pkg/api/ep"
+	"github.com/intel/edge-conductor/pkg/eputils"
+)
+
+// fakeClient fails the commands listed in fail, and counts the commands
+// running at the same time.
+type fakeClient struct {
+	fail    map[string]bool
+	mu      *sync.Mutex
+	running *int
+	maxRun  *int
+	cmds    *[]string
+}
+
+func (c *fakeClient) Connect() error {
+	return nil
+}
+
+func (c *fakeClient) Disconnect() error {
+	return nil
+}
+
+func (c *fakeClient) CmdWithAttachIO(ctx context.Context, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error {
+	c.mu.Lock()
+	*c.running++
+	if *c.running > *c.maxRun {
+		*c.maxRun = *c.running
+	}
+	*c.cmds = append(*c.cmds, strings.Join(cmd, " "))
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		*c.running--
+		c.mu.Unlock()
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	if c.fail[cmd[0]] {
+		fmt.Fprintf(stderr, "error: %s failed\n", cmd[0])
+		return exec.Command("false").Run()
+	}
+	return nil
+}
+
+func newFakeNodes(n int, fail map[string]map[string]bool) (map[string][]*nodeInfo, *int, *[]string) {
+	mu := &sync.Mutex{}
+	running, maxRun := 0, 0
+	cmds := []string{}
+	nodes := []*nodeInfo{}
+	for i := 0; i < n; i++ {
+		ip := fmt.Sprintf("10.0.0.%d", i+1)
+		nodes = append(nodes, &nodeInfo{
+			name:   fmt.Sprintf("node%d", i+1),
+			ip:     ip,
+			client: &fakeClient{fail: fail[ip], mu: mu, running: &running, maxRun: &maxRun, cmds: &cmds},
+		})
+	}
+	return map[string][]*nodeInfo{"worker": nodes}, &maxRun, &cmds
+}
+
+func TestRunOnNodes(t *testing.T) {
+	cases := []struct {
+		name        string
+		parallel    int64
+		nodes       int
+		wantMaxRun  int
+		expectError bool
+	}{
+		{"one node at a time", 0, 6, 1, false},
+		{"limited", 2, 6, 2, false},
+		{"more than nodes", 10, 3, 3, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			nodesByRole, maxRun, _ := newFakeNodes(tc.nodes, nil)
+			executor := &Executor{
+				Execspec: ep.Execspec{
+					Spec: &ep.ExecspecSpec{
+						Steps: []*ep.ExecspecSpecStepsItems0{{
+							Name:     "check",
+							Parallel: tc.parallel,
+							Commands: []*ep.ExecspecSpecStepsItems0CommandsItems0{
+								{Type: "shell", Cmd: []string{"true"}},
+							},
+							Nodes: &ep.ExecspecSpecStepsItems0Nodes{AllOf: []string{"worker"}},
+						}},
+					},
+				},
+				nodesByRole: nodesByRole,
+			}
+			err := executor.RunWithAttachIO(context.TODO(), nil, &bytes.Buffer{}, nil)
+			if (err != nil) != tc.expectError {
+				t.Error(err)
+			}
+			if *maxRun != tc.wantMaxRun {
+				t.Errorf("Unexpected nodes running at the same time: %d, want %d", *maxRun, tc.wantMaxRun)
+			}
+		})
+	}
+}
+
+func TestContinueOnError(t *testing.T) {
+	fail := map[string]map[string]bool{
+		"10.0.0.2": {"check": true},
+	}
+	cases := []struct {
+		name            string
+		continueOnError bool
+		wantErr         error
+		wantCmds        []string
+		wantSummary     [][]string
+	}{
+		{
+			name:     "abort",
+			wantErr:  &exec.ExitError{},
+			wantCmds: []string{"check", "check", "check"},
+			wantSummary: [][]string{
+				{"node1", "10.0.0.1", "Succeeded", "0"},
+				{"node2", "10.0.0.2", "Failed", "preflight", "1"},
+				{"node3", "10.0.0.3", "Succeeded", "0"},
+			},
+		},
+		{
+			name:            "continue on error",
+			continueOnError: true,
+			wantErr:         eputils.GetError("errExecNodeFailed"),
+			wantCmds:        []string{"check", "check", "check", "setup", "setup", "deploy", "deploy"},
+			wantSummary: [][]string{
+				{"node1", "10.0.0.1", "Succeeded", "0"},
+				{"node2", "10.0.0.2", "Failed", "preflight", "1"},
+				{"node3", "10.0.0.3", "Succeeded", "0"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			nodesByRole, _, cmds := newFakeNodes(3, fail)
+			nodes := &ep.ExecspecSpecStepsItems0Nodes{AllOf: []string{"worker"}}
+			executor := &Executor{
+				Execspec: ep.Execspec{
+					Spec: &ep.ExecspecSpec{
+						Steps: []*ep.ExecspecSpecStepsItems0{
+							{
+								Name:            "preflight",
+								ContinueOnError: tc.continueOnError,
+								Commands: []*ep.ExecspecSpecStepsItems0CommandsItems0{
+									{Type: "shell", Cmd: []string{"check"}},
+									{Type: "shell", Cmd: []string{"setup"}},
+								},
+								Nodes: nodes,
+							},
+							{
+								Name:     "deploy",
+								Commands: []*ep.ExecspecSpecStepsItems0CommandsItems0{{Type: "shell", Cmd: []string{"deploy"}}},
+								Nodes:    nodes,
+							},
+						},
+					},
+				},
+				nodesByRole: nodesByRole,
+			}
+			stdout, out := &bytes.Buffer{}, &bytes.Buffer{}
+			err := executor.RunWithAttachIO(context.TODO(), nil, stdout, out)
+			if reflect.TypeOf(err) != reflect.TypeOf(tc.wantErr) || (tc.continueOnError && err != tc.wantErr) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			sort.Strings(*cmds)
+			sort.Strings(tc.wantCmds)
+			if strings.Join(*cmds, ",") != strings.Join(tc.wantCmds, ",") {
+				t.Errorf("Unexpected commands: %v, want %v", *cmds, tc.wantCmds)
+			}
+
+			if strings.Contains(stdout.String(), "NODE") {
+				t.Errorf("Unexpected summary on stdout: %s", stdout.String())
+			}
+			lines := strings.Split(out.String(), "\n")
+			for i, want := range tc.wantSummary {
+				got := strings.Fields(lines[3+i])
+				if strings.Join(got[:len(want)], " ") != strings.Join(want, " ") {
+					t.Errorf("Unexpected summary %v, want %v", got, want)
+				}
+			}
+			if !strings.Contains(lines[4], "error: check failed") {
+				t.Errorf("Unexpected output tail: %s", lines[4])
+			}
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	cases := []struct {
+		name            string
+		nodes           int
+		parallel        int64
+		continueOnError bool
+		wantSummary     bool
+	}{
+		{"single node", 1, 0, false, false},
+		{"parallel", 1, 1, false, true},
+		{"continue on error", 1, 0, true, true},
+		{"more than one node", 2, 0, false, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			nodesByRole, _, _ := newFakeNodes(tc.nodes, nil)
+			executor := &Executor{
+				Execspec: ep.Execspec{
+					Spec: &ep.ExecspecSpec{
+						Steps: []*ep.ExecspecSpecStepsItems0{{
+							Name:            "check",
+							Parallel:        tc.parallel,
+							ContinueOnError: tc.continueOnError,
+							Commands: []*ep.ExecspecSpecStepsItems0CommandsItems0{
+								{Type: "shell", Cmd: []string{"true"}},
+							},
+							Nodes: &ep.ExecspecSpecStepsItems0Nodes{AllOf: []string{"worker"}},
+						}},
+					},
+				},
+				nodesByRole: nodesByRole,
+			}
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			if err := executor.RunWithAttachIO(context.TODO(), nil, stdout, stderr); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if strings.Contains(stdout.String(), "NODE") {
+				t.Errorf("Unexpected summary on stdout: %s", stdout.String())
+			}
+			if got := strings.Contains(stderr.String(), "NODE"); got != tc.wantSummary {
+				t.Errorf("Unexpected summary printed: %v, want %v", got, tc.wantSummary)
+			}
+		})
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	b := &tailBuffer{}
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(b, "line %d\r\n\n", i)
+	}
+	if len(b.buf) != tailSize {
+		t.Errorf("Unexpected tail size: %d", len(b.buf))
+	}
+	if got := strings.Join(b.lines(2), ","); got != "line 198,line 199" {
+		t.Errorf("Unexpected lines: %s", got)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "kernel")
+	day0 := &nodeInfo{name: "day-0", ip: "127.0.0.1", client: &day0Client{}}
+	nodes := &ep.ExecspecSpecStepsItems0Nodes{AllOf: []string{"day-0"}}
+	register := &ep.ExecspecSpecStepsItems0CommandsItems0{
+		Type:     "shell",
+		Register: "kernel",
+		Cmd:      []string{"sh", "-c", "echo 5.15.0-rt; echo warning >&2; exit 3"},
+	}
+
+	cases := []struct {
+		name           string
+		commands       []*ep.ExecspecSpecStepsItems0CommandsItems0
+		wantErr        error
+		wantRegistered Registered
+		wantOut        string
+	}{
+		{
+			name: "use registered output",
+			commands: []*ep.ExecspecSpecStepsItems0CommandsItems0{
+				register,
+				{
+					Type: "shell",
+					When: `\{\{ eq .Registered.kernel.ExitCode 3 \}\}`,
+					Cmd:  []string{"sh", "-c", `echo \{\{ .Registered.kernel.Stdout \}\} > ` + out},
+				},
+				{
+					Type: "shell",
+					When: `\{\{ eq .Registered.kernel.Stderr "" \}\}`,
+					Cmd:  []string{"false"},
+				},
+			},
+			wantRegistered: Registered{
+				"127.0.0.1": {"kernel": {Stdout: "5.15.0-rt", Stderr: "warning", ExitCode: 3}},
+			},
+			wantOut: "5.15.0-rt\n",
+		},
+		{
+			name: "not a shell command",
+			commands: []*ep.ExecspecSpecStepsItems0CommandsItems0{
+				{Type: "fileCheck", Register: "kernel", Cmd: []string{"/"}},
+			},
+			wantErr:        eputils.GetError("errExecRegister"),
+			wantRegistered: Registered{},
+		},
+		{
+			name: "invalid name",
+			commands: []*ep.ExecspecSpecStepsItems0CommandsItems0{
+				{Type: "shell", Register: "kernel-version", Cmd: []string{"true"}},
+			},
+			wantErr:        eputils.GetError("errExecRegister"),
+			wantRegistered: Registered{},
+		},
+		{
+			name: "command not found",
+			commands: []*ep.ExecspecSpecStepsItems0CommandsItems0{
+				{Type: "shell", Register: "kernel", Cmd: []string{"/notfound"}},
+			},
+			wantErr:        &os.PathError{},
+			wantRegistered: Registered{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			executor := &Executor{
+				Execspec: ep.Execspec{
+					Spec: &ep.ExecspecSpec{
+						Steps: []*ep.ExecspecSpecStepsItems0{{Name: "register", Commands: tc.commands, Nodes: nodes}},
+					},
+				},
+				nodesByRole: map[string][]*nodeInfo{"day-0": {day0}},
+			}
+			err := executor.RunWithAttachIO(context.TODO(), nil, &bytes.Buffer{}, nil)
+			if _, isPathErr := tc.wantErr.(*os.PathError); reflect.TypeOf(err) != reflect.TypeOf(tc.wantErr) || (!isPathErr && err != tc.wantErr) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if got := executor.Registered(); !reflect.DeepEqual(got, tc.wantRegistered) {
+				t.Errorf("Unexpected registered: %v, want %v", got, tc.wantRegistered)
+			}
+			if tc.wantOut != "" {
+				content, err := ioutil.ReadFile(out)
+				if err != nil || string(content) != tc.wantOut {
+					t.Errorf("Unexpected output: %q, %v", content, err)
+				}
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ import (
 type ExecutorWrapper interface {
 	SimpleShell(s *pluginapi.ExecSimpleShell, epparams *pluginapi.EpParams) error
 	Run(specFile string, epparams *pluginapi.EpParams, value interface{}) error
+	RunWithRegistered(specFile string, epparams *pluginapi.EpParams, value interface{}) (Registered, error)
 }
 
 func SimpleShell(s *pluginapi.ExecSimpleShell, epparams *pluginapi.EpParams) error {
@@ -38,19 +39,27 @@ func SimpleShell(s *pluginapi.ExecSimpleShell, epparams *pluginapi.EpParams) err
 }
 
 func Run(specFile string, epparams *pluginapi.EpParams, value interface{}) error {
+	_, err := RunWithRegistered(specFile, epparams, value)
+	return err
+}
+
+// RunWithRegistered runs the spec as Run does, and returns the results of the
+// registered commands by node IP and register name, including the ones
+// registered before a failure.
+func RunWithRegistered(specFile string, epparams *pluginapi.EpParams, value interface{}) (Registered, error) {
 	e := New()
 	err := e.SetECParams(epparams)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	err = e.SetTempValue(value)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	err = e.LoadSpecFromFile(specFile)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	err = e.Run(context.Background())
-	return err
+	return e.Registered(), err
 }
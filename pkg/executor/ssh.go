@@ -13,18 +13,30 @@ import (
 	"strings"
 	"time"
 
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/crypto/ssh"
 )
 
 type sshClient struct {
-	host     string
-	user     string
-	password string
-	key      string
-	port     int
-	config   *ssh.ClientConfig
-	client   *ssh.Client
+	host         string
+	user         string
+	password     string
+	key          string
+	port         int
+	cert         string
+	agentSocket  string
+	proxyJump    []string
+	fingerprints []string
+	config       *ssh.ClientConfig
+	client       *ssh.Client
+}
+
+// shellQuote quotes s as a single word for the remote shell, which runs the
+// command line of an ssh session.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
 }
 
 func (c *sshClient) Connect() error {
@@ -35,22 +47,22 @@ func (c *sshClient) Connect() error {
 				"chacha20-poly1305@openssh.com",
 				"aes256-ctr", "aes256-cbc"},
 		},
-		Timeout: time.Second * 5,
-		User:    c.user,
-		// #nosec G106
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         time.Second * 5,
+		User:            c.user,
+		HostKeyCallback: eputils.HostKeyCallback(c.fingerprints),
 		Auth:            []ssh.AuthMethod{ssh.Password(c.password)},
 	}
 
-	if c.key != "" {
-		signer, err := ssh.ParsePrivateKey([]byte(c.key))
-		if err == nil {
-			c.config.Auth = append(c.config.Auth, ssh.PublicKeys(signer))
-		}
+	auth, err := eputils.SSHPublicKeyAuth(c.key, c.cert, c.agentSocket)
+	if err != nil {
+		return err
+	}
+	if auth != nil {
+		c.config.Auth = append(c.config.Auth, auth)
 	}
 
 	addr := fmt.Sprintf("%s:%d", c.host, c.port)
-	client, err := ssh.Dial("tcp", addr, c.config)
+	client, err := eputils.DialSSHVia(addr, c.config, c.proxyJump)
 	c.client = client
 	return err
 }
@@ -42,6 +42,13 @@ func TestConnect(t *testing.T) {
 				return []*mpatch.Patch{patch1, patch2}
 			},
 		},
+		{
+			name: "invalid ssh key",
+			sshclient: &sshClient{
+				key: "invalid key",
+			},
+			expectError: true,
+		},
 	}
 
 	for _, tc := range cases {
@@ -25,14 +25,22 @@ type Plugin struct {
 	client      wfapi.WorkflowClient
 	data        eputils.SchemaStruct
 	plugin_data eputils.SchemaStruct
+	schemas     []string
 	finished    bool
 }
 
 const (
 	CONNECT_TIMEOUT = 3600
 	DEFAULT_TIMEOUT = 5
+	// PROTOCOL_VERSION is increased on every incompatible change of the
+	// workflow protocol.
+	PROTOCOL_VERSION = 1
 )
 
+// Version is the build version of the plugin, it can be set with
+// -ldflags "-X github.com/intel/edge-conductor/pkg/plugin.Version=<version>".
+var Version = "dev"
+
 func New(name string, data eputils.SchemaStruct, plugin_data eputils.SchemaStruct) *Plugin {
 	return &Plugin{name: name, data: data, plugin_data: plugin_data, finished: false}
 }
@@ -56,10 +64,14 @@ func (p *Plugin) Connect(address string) error {
 
 	ctx, cancel = context.WithTimeout(context.Background(), CONNECT_TIMEOUT*time.Second)
 	defer cancel()
-	r, err := p.client.PluginConnect(ctx, &wfapi.PluginConnectRequest{Plugin: &wfapi.Plugin{Name: p.name}})
+	r, err := p.client.PluginConnect(ctx, p.connectRequest())
 	if err != nil {
 		return err
 	}
+	if r.Result.Return == wfapi.ConnectResult_Error {
+		log.Errorf("Plugin %v (version %v, protocol %v) is rejected by the workflow server", p.name, Version, PROTOCOL_VERSION)
+		return eputils.GetError("errPluginVersion")
+	}
 	if r.Result.Return == wfapi.ConnectResult_Completed {
 		p.finished = true
 		return nil
@@ -82,6 +94,20 @@ func (p *Plugin) Connect(address string) error {
 
 }
 
+func (p *Plugin) connectRequest() *wfapi.PluginConnectRequest {
+	req := &wfapi.PluginConnectRequest{
+		Plugin:          &wfapi.Plugin{Name: p.name},
+		ProtocolVersion: PROTOCOL_VERSION,
+		Version:         Version,
+	}
+	for _, n := range p.schemas {
+		if h, has := eputils.SchemaStructHash(n); has {
+			req.Schemas = append(req.Schemas, &wfapi.Schema{Name: n, Hash: h})
+		}
+	}
+	return req
+}
+
 func (p *Plugin) Complete(err error) error {
 	defer p.conn.Close()
 	req := &wfapi.PluginCompleteRequest{
@@ -50,6 +50,7 @@ func __name(n string) string {
 
 func test_plugin_main(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 	count++
+	plugin.ReportProgress(Name, "test", int64(count), 3)
 	if count > 3 {
 		return eputils.GetError("errEmpty")
 	} else {
@@ -10,6 +10,7 @@ import (
 	"context"
 	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	"sort"
 	"sync"
 
 	log "github.com/sirupsen/logrus"
@@ -28,6 +29,8 @@ type PluginMainFuncs struct {
 	remoteLog   bool
 	started     bool
 	err         error
+	lock        sync.Mutex
+	progress    wfapi.Workflow_PluginReportProgressClient
 }
 
 var (
@@ -35,7 +38,7 @@ var (
 )
 
 type LogHook struct {
-	writer func(s string)
+	writer func(entry *log.Entry, s string)
 }
 
 func (hook *LogHook) Fire(entry *log.Entry) error {
@@ -43,7 +46,7 @@ func (hook *LogHook) Fire(entry *log.Entry) error {
 	if err != nil {
 		return err
 	}
-	hook.writer(string(line))
+	hook.writer(entry, string(line))
 	return err
 }
 
@@ -71,6 +74,13 @@ func run(m *PluginMainFuncs) error {
 	defer logcancel()
 	log.Infof("Start Plugin %v\n", m.name)
 	p := New(m.name, m.data, m.plugin_data)
+	for k := range *m.in {
+		p.schemas = append(p.schemas, k)
+	}
+	for k := range *m.out {
+		p.schemas = append(p.schemas, k)
+	}
+	sort.Strings(p.schemas)
 
 	for {
 		log.Infof("Connecting Plugin %v\n", m.name)
@@ -92,16 +102,23 @@ func run(m *PluginMainFuncs) error {
 			}
 			logexit := false
 			log.AddHook(&LogHook{
-				writer: func(s string) {
+				writer: func(entry *log.Entry, s string) {
 					if logexit {
 						return
 					}
-					if err := logstream.Send(&wfapi.Log{Log: s}); err != nil {
+					l := &wfapi.Log{
+						Log:     s,
+						Plugin:  m.name,
+						Level:   entry.Level.String(),
+						Message: entry.Message,
+					}
+					if err := logstream.Send(l); err != nil {
 						logexit = true
 					}
 				},
 			})
 		}
+		m.openProgress(logctx, p)
 		log.Infof("Exec Plugin %v\n", m.name)
 		for k := range *m.in {
 			if _, has := (*m.plugin_data)[k]; !has {
@@ -121,6 +138,7 @@ func run(m *PluginMainFuncs) error {
 		} else {
 			log.Errorf("Plugin error: name: %v, err: %v", m.name, err)
 		}
+		m.closeProgress()
 		log.Infof("Complete Plugin %v\n", m.name)
 		err = p.Complete(err)
 		if err != nil {
@@ -129,6 +147,50 @@ func run(m *PluginMainFuncs) error {
 	}
 }
 
+func (m *PluginMainFuncs) openProgress(ctx context.Context, p *Plugin) {
+	stream, err := p.client.PluginReportProgress(ctx)
+	if err != nil {
+		log.Debugf("Get progress stream error, %v", err)
+		stream = nil
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.progress = stream
+}
+
+func (m *PluginMainFuncs) closeProgress() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.progress == nil {
+		return
+	}
+	if _, err := m.progress.CloseAndRecv(); err != nil {
+		log.Debugf("Close progress stream error, %v", err)
+	}
+	m.progress = nil
+}
+
+// ReportProgress sends the progress of plugin name to the workflow server:
+// current of total units of work are done in phase, total is 0 if it is
+// unknown. The progress is dropped if the plugin is not running.
+func ReportProgress(name string, phase string, current int64, total int64) {
+	for _, m := range mains {
+		if m.name == name {
+			m.lock.Lock()
+			defer m.lock.Unlock()
+			if m.progress == nil {
+				return
+			}
+			if err := m.progress.Send(&wfapi.Progress{Plugin: name, Phase: phase, Current: current, Total: total}); err != nil {
+				log.Debugf("Send progress error, %v", err)
+				m.progress = nil
+			}
+			return
+		}
+	}
+	log.Debugf("Cannot find %v", name)
+}
+
 func StartPlugin(name string, errch chan error) error {
 	for _, m := range mains {
 		if m.name == name {
@@ -14,7 +14,7 @@ import (
 
 func Test_Fire(t *testing.T) {
 	hook := &LogHook{
-		writer: func(s string) {
+		writer: func(entry *log.Entry, s string) {
 		},
 	}
 	log.AddHook(hook)
@@ -0,0 +1,192 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package pluginsdk
+
+import (
+	"io/ioutil"
+	fpath "path/filepath"
+	"strings"
+
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+
+	"sigs.k8s.io/yaml"
+)
+
+// TestingT is the part of *testing.T the harness needs.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// Fixture is one in-process run of a plugin main function.
+type Fixture struct {
+	// Input has the YAML files of the input data, by input name. The inputs
+	// without a file are empty.
+	Input map[string]string
+	// Output has the YAML files of the expected output data, by output
+	// name. The outputs without a file are not checked.
+	Output map[string]string
+	// ExpectError tells the main function should fail.
+	ExpectError bool
+}
+
+// FixtureDir returns the fixture in directory dir, with the input data in
+// dir/input/<input name>.yml and the expected output data in
+// dir/output/<output name>.yml.
+func FixtureDir(dir string) Fixture {
+	return Fixture{
+		Input:  yamlFiles(fpath.Join(dir, "input")),
+		Output: yamlFiles(fpath.Join(dir, "output")),
+	}
+}
+
+func yamlFiles(dir string) map[string]string {
+	files := map[string]string{}
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return files
+	}
+	for _, e := range entries {
+		if !e.IsDir() && fpath.Ext(e.Name()) == ".yml" {
+			files[strings.TrimSuffix(e.Name(), ".yml")] = fpath.Join(dir, e.Name())
+		}
+	}
+	return files
+}
+
+// newData returns new schema structs for the declared data, loaded with
+// the data from load by name. The data not given stays empty.
+func (p *Plugin) newData(schemas eputils.SchemaMapData, load func(n string, v eputils.SchemaStruct) error) (eputils.SchemaMapData, error) {
+	data := eputils.NewSchemaMapData()
+	for name := range schemas {
+		n := strings.TrimPrefix(name, p.Name+".")
+		v := eputils.SchemaStructNew(name)
+		if err := load(n, v); err != nil {
+			return nil, err
+		}
+		data[name] = v
+	}
+	return data, nil
+}
+
+func fromJson(data map[string][]byte) func(string, eputils.SchemaStruct) error {
+	return func(n string, v eputils.SchemaStruct) error {
+		if data[n] == nil {
+			return nil
+		}
+		return v.UnmarshalBinary(data[n])
+	}
+}
+
+func fromYamlFiles(files map[string]string) func(string, eputils.SchemaStruct) error {
+	return func(n string, v eputils.SchemaStruct) error {
+		if file, has := files[n]; has {
+			return loadYamlFile(v, file)
+		}
+		return nil
+	}
+}
+
+func loadYamlFile(v eputils.SchemaStruct, file string) error {
+	yml, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	json, err := yaml.YAMLToJSON(yml)
+	if err != nil {
+		return err
+	}
+	if err := v.UnmarshalBinary(json); err != nil {
+		return err
+	}
+	return v.Validate(nil)
+}
+
+// NewInput returns the input data of the plugin, with the inputs loaded from
+// JSON by input name. It returns nil if any input can not be unmarshalled.
+func (p *Plugin) NewInput(data map[string][]byte) eputils.SchemaMapData {
+	in, err := p.newData(p.Input, fromJson(data))
+	if err != nil {
+		return nil
+	}
+	return in
+}
+
+// NewOutput returns the output data of the plugin, with the outputs loaded
+// from JSON by output name. It returns nil if any output can not be
+// unmarshalled.
+func (p *Plugin) NewOutput(data map[string][]byte) eputils.SchemaMapData {
+	out, err := p.newData(p.Output, fromJson(data))
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+// Run runs main in process, the same way as the workflow server runs the
+// plugin, with the input data loaded from YAML files by input name, and
+// returns the output data.
+func (p *Plugin) Run(main MainFunc, input map[string]string) (eputils.SchemaMapData, error) {
+	for n := range input {
+		if _, has := p.Input[p.SchemaName(n)]; !has {
+			return nil, eputils.GetError("errInputSchema")
+		}
+	}
+	in, err := p.newData(p.Input, fromYamlFiles(input))
+	if err != nil {
+		return nil, err
+	}
+	out, err := p.newData(p.Output, fromYamlFiles(nil))
+	if err != nil {
+		return nil, err
+	}
+	return out, main(in, &out)
+}
+
+// RunFixture runs main with the input data of fixture f, and checks the
+// result and the output data against f.
+func (p *Plugin) RunFixture(t TestingT, main MainFunc, f Fixture) {
+	t.Helper()
+	out, err := p.Run(main, f.Input)
+	if f.ExpectError {
+		if err == nil {
+			t.Errorf("Plugin %s: expected an error, but it succeeded", p.Name)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("Plugin %s failed: %v", p.Name, err)
+		return
+	}
+	for n, file := range f.Output {
+		name := p.SchemaName(n)
+		v, has := out[name]
+		if !has {
+			t.Errorf("Plugin %s has no output %s", p.Name, n)
+			continue
+		}
+		expected := eputils.SchemaStructNew(name)
+		if err := loadYamlFile(expected, file); err != nil {
+			t.Fatalf("Failed to load expected output %s from %s: %v", n, file, err)
+			return
+		}
+		want, err := eputils.SchemaStructToYaml(expected)
+		if err != nil {
+			t.Fatalf("Failed to marshal expected output %s: %v", n, err)
+			return
+		}
+		got, err := eputils.SchemaStructToYaml(v)
+		if err != nil {
+			t.Fatalf("Failed to marshal output %s: %v", n, err)
+			return
+		}
+		if got != want {
+			t.Errorf("Plugin %s output %s is not expected.\nexpected (%s):\n%s\nactual:\n%s", p.Name, n, file, want, got)
+		}
+	}
+}
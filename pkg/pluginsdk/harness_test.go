@@ -0,0 +1,84 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package pluginsdk
+
+import (
+	"fmt"
+	"io/ioutil"
+	fpath "path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+)
+
+type fakeT struct {
+	errors []string
+	fatal  bool
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Fatalf(format string, args ...interface{}) {
+	t.Errorf(format, args...)
+	t.fatal = true
+}
+
+func TestFixtureDir(t *testing.T) {
+	f := FixtureDir("testdata/copy")
+	require.Equal(t, map[string]string{"content": "testdata/copy/input/content.yml"}, f.Input)
+	require.Equal(t, map[string]string{"path": "testdata/copy/output/path.yml"}, f.Output)
+
+	f = FixtureDir("testdata/unknown")
+	require.Empty(t, f.Input)
+	require.Empty(t, f.Output)
+}
+
+func TestRun(t *testing.T) {
+	out, err := testPlugin.Run(testMain, map[string]string{"content": "testdata/copy/input/content.yml"})
+	require.NoError(t, err)
+	require.Equal(t, "hello.txt", out["sdk-test.path"].(*pluginapi.Filepath).Path)
+
+	_, err = testPlugin.Run(testMain, nil)
+	require.Equal(t, eputils.GetError("errParameter"), err, "the input without a file is empty")
+
+	_, err = testPlugin.Run(testMain, map[string]string{"unknown": "testdata/copy/input/content.yml"})
+	require.Equal(t, eputils.GetError("errInputSchema"), err)
+
+	_, err = testPlugin.Run(testMain, map[string]string{"content": "testdata/missing.yml"})
+	require.Error(t, err)
+}
+
+func TestRunFixture(t *testing.T) {
+	testPlugin.RunFixture(t, testMain, FixtureDir("testdata/copy"))
+
+	wrong := fpath.Join(t.TempDir(), "path.yml")
+	require.NoError(t, ioutil.WriteFile(wrong, []byte("path: wrong.txt\n"), 0600))
+	cases := []struct {
+		fixture Fixture
+		errors  int
+		fatal   bool
+	}{
+		{Fixture{Input: FixtureDir("testdata/copy").Input, Output: map[string]string{"path": wrong}}, 1, false},
+		{Fixture{Output: map[string]string{"unknown": wrong}, ExpectError: true}, 0, false},
+		{Fixture{Input: FixtureDir("testdata/copy").Input, ExpectError: true}, 1, false},
+		{Fixture{}, 1, true},
+		{Fixture{Input: FixtureDir("testdata/copy").Input, Output: map[string]string{"unknown": wrong}}, 1, false},
+	}
+	for n, tc := range cases {
+		ft := &fakeT{}
+		testPlugin.RunFixture(ft, testMain, tc.fixture)
+		require.Len(t, ft.errors, tc.errors, "case %d: %v", n, ft.errors)
+		require.Equal(t, tc.fatal, ft.fatal, "case %d", n)
+	}
+}
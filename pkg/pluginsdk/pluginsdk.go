@@ -0,0 +1,94 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+// Package pluginsdk helps to declare Edge Conductor plugins and to test them
+// in process, without the workflow server.
+package pluginsdk
+
+import (
+	"reflect"
+
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	epplugin "github.com/intel/edge-conductor/pkg/plugin"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// MainFunc is the main function of a plugin.
+type MainFunc func(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error
+
+// Plugin declares the name, the inputs and the outputs of a plugin.
+type Plugin struct {
+	Name   string
+	Input  eputils.SchemaMapData
+	Output eputils.SchemaMapData
+}
+
+func New(name string) *Plugin {
+	return &Plugin{
+		Name:   name,
+		Input:  eputils.NewSchemaMapData(),
+		Output: eputils.NewSchemaMapData(),
+	}
+}
+
+// SchemaName returns the name of the plugin data n in eputils.SchemaMapData.
+func (p *Plugin) SchemaName(n string) string {
+	return p.Name + "." + n
+}
+
+func newFunc(v eputils.SchemaStruct) func() eputils.SchemaStruct {
+	t := reflect.TypeOf(v).Elem()
+	return func() eputils.SchemaStruct {
+		return reflect.New(t).Interface().(eputils.SchemaStruct)
+	}
+}
+
+// WithInput declares input n with the schema struct type of v, for example
+// &pluginapi.Filecontent{}.
+func (p *Plugin) WithInput(n string, v eputils.SchemaStruct) *Plugin {
+	eputils.AddSchemaStruct(p.SchemaName(n), newFunc(v))
+	p.Input[p.SchemaName(n)] = newFunc(v)()
+	return p
+}
+
+// WithOutput declares output n with the schema struct type of v.
+func (p *Plugin) WithOutput(n string, v eputils.SchemaStruct) *Plugin {
+	eputils.AddSchemaStruct(p.SchemaName(n), newFunc(v))
+	p.Output[p.SchemaName(n)] = newFunc(v)()
+	return p
+}
+
+// Register registers main as the main function of the plugin, to be run by
+// conductor-plugin or a plugin executable.
+func (p *Plugin) Register(main MainFunc) {
+	epplugin.RegisterPlugin(p.Name, &p.Input, &p.Output, main)
+}
+
+// ReportProgress reports the progress of the running plugin to the workflow
+// server: current of total units of work are done in phase. Set total to 0
+// if it is unknown.
+func (p *Plugin) ReportProgress(phase string, current, total int64) {
+	epplugin.ReportProgress(p.Name, phase, current, total)
+}
+
+// Get sets ptr, a pointer to a schema struct pointer, to the plugin data n
+// in data. It fails if n is not in data or has another type.
+func (p *Plugin) Get(data eputils.SchemaMapData, n string, ptr interface{}) error {
+	v, has := data[p.SchemaName(n)]
+	if !has || v == nil {
+		log.Errorf("Plugin %s has no data %s", p.Name, n)
+		return eputils.GetError("errInputSchema")
+	}
+	pv := reflect.ValueOf(ptr)
+	if pv.Kind() != reflect.Ptr || pv.IsNil() || !reflect.TypeOf(v).AssignableTo(pv.Elem().Type()) {
+		log.Errorf("Data %s of plugin %s is %T, not %T", n, p.Name, v, ptr)
+		return eputils.GetError("errConvert")
+	}
+	pv.Elem().Set(reflect.ValueOf(v))
+	return nil
+}
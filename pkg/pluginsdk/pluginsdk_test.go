@@ -0,0 +1,69 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package pluginsdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+)
+
+var testPlugin = New("sdk-test").
+	WithInput("content", &pluginapi.Filecontent{}).
+	WithOutput("path", &pluginapi.Filepath{})
+
+func testMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
+	var content *pluginapi.Filecontent
+	if err := testPlugin.Get(in, "content", &content); err != nil {
+		return err
+	}
+	var path *pluginapi.Filepath
+	if err := testPlugin.Get(*outp, "path", &path); err != nil {
+		return err
+	}
+	if len(content.Content) == 0 {
+		return eputils.GetError("errParameter")
+	}
+	path.Path = content.Content + ".txt"
+	return nil
+}
+
+func TestPlugin(t *testing.T) {
+	require.Equal(t, "sdk-test.content", testPlugin.SchemaName("content"))
+	require.IsType(t, &pluginapi.Filecontent{}, testPlugin.Input["sdk-test.content"])
+	require.IsType(t, &pluginapi.Filepath{}, testPlugin.Output["sdk-test.path"])
+	require.IsType(t, &pluginapi.Filepath{}, eputils.SchemaStructNew("sdk-test.path"))
+	require.NotSame(t, testPlugin.Output["sdk-test.path"], eputils.SchemaStructNew("sdk-test.path"))
+}
+
+func TestGet(t *testing.T) {
+	in := testPlugin.NewInput(map[string][]byte{"content": []byte(`{"content":"hello"}`)})
+	require.NotNil(t, in)
+
+	var content *pluginapi.Filecontent
+	require.NoError(t, testPlugin.Get(in, "content", &content))
+	require.Equal(t, "hello", content.Content)
+
+	var path *pluginapi.Filepath
+	require.Equal(t, eputils.GetError("errConvert"), testPlugin.Get(in, "content", &path))
+	require.Equal(t, eputils.GetError("errConvert"), testPlugin.Get(in, "content", content))
+	require.Equal(t, eputils.GetError("errInputSchema"), testPlugin.Get(in, "unknown", &content))
+}
+
+func TestNewInput(t *testing.T) {
+	in := testPlugin.NewInput(nil)
+	require.Len(t, in, 1)
+	require.Empty(t, in["sdk-test.content"].(*pluginapi.Filecontent).Content)
+
+	require.Nil(t, testPlugin.NewInput(map[string][]byte{"content": []byte(`{`)}))
+
+	out := testPlugin.NewOutput(map[string][]byte{"path": []byte(`{"path":"p"}`)})
+	require.Equal(t, "p", out["sdk-test.path"].(*pluginapi.Filepath).Path)
+}
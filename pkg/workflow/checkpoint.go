@@ -0,0 +1,168 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	fpath "path/filepath"
+
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+
+	log "github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	RUNTIME_CHECKPOINT_DIR = "runtime/checkpoint"
+)
+
+// Resume makes Start skip the steps recorded as finished in the checkpoint
+// journal of the workflow, up to the first step whose inputs have changed.
+var Resume = false
+
+type stepCheckpoint struct {
+	Index     int               `json:"index"`
+	Plugin    string            `json:"plugin"`
+	InputHash string            `json:"inputHash"`
+	Outputs   map[string]string `json:"outputs,omitempty"`
+}
+
+type checkpoint struct {
+	Workflow string           `json:"workflow"`
+	Steps    []stepCheckpoint `json:"steps,omitempty"`
+	filepath string
+}
+
+func hashOf(b []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(b))
+}
+
+func checkpointFile(name string) string {
+	return fpath.Join(RUNTIME_CHECKPOINT_DIR, name+".yml")
+}
+
+// loadCheckpoint returns the journal of the workflow. The previous journal
+// is only honoured in resume mode, otherwise the workflow starts afresh.
+func loadCheckpoint(name string, resume bool) (*checkpoint, error) {
+	cp := &checkpoint{Workflow: name, filepath: checkpointFile(name)}
+	if !resume {
+		return cp, nil
+	}
+	buf, err := ioutil.ReadFile(cp.filepath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Infof("No checkpoint found for workflow %s, start from the first step", name)
+			return cp, nil
+		}
+		return nil, err
+	}
+	if err := yaml.Unmarshal(buf, cp); err != nil {
+		log.Errorf("Failed to load checkpoint %s: %v", cp.filepath, err)
+		return nil, eputils.GetError("errCheckpoint")
+	}
+	if cp.Workflow != name {
+		log.Warnf("Checkpoint %s belongs to workflow %s, ignore it", cp.filepath, cp.Workflow)
+		cp.Workflow = name
+		cp.Steps = nil
+	}
+	return cp, nil
+}
+
+func (cp *checkpoint) save() error {
+	if err := os.MkdirAll(RUNTIME_CHECKPOINT_DIR, os.FileMode(0700)); err != nil {
+		return err
+	}
+	buf, err := yaml.Marshal(cp)
+	if err != nil {
+		return eputils.GetError("errCheckpoint")
+	}
+	return ioutil.WriteFile(cp.filepath, buf, os.FileMode(0600))
+}
+
+func (cp *checkpoint) get(index int) *stepCheckpoint {
+	for k := range cp.Steps {
+		if cp.Steps[k].Index == index {
+			return &cp.Steps[k]
+		}
+	}
+	return nil
+}
+
+// record stores the finished step in the journal and persists it, so that
+// a later resume can skip it.
+func (cp *checkpoint) record(stcp stepCheckpoint) error {
+	if old := cp.get(stcp.Index); old != nil {
+		*old = stcp
+	} else {
+		cp.Steps = append(cp.Steps, stcp)
+	}
+	return cp.save()
+}
+
+// forget drops the journal entries of the steps, they are invalid once a
+// step they depend on has run again.
+func (cp *checkpoint) forget(indexes []int) {
+	drop := map[int]bool{}
+	for _, index := range indexes {
+		drop[index] = true
+	}
+	steps := []stepCheckpoint{}
+	for _, stcp := range cp.Steps {
+		if !drop[stcp.Index] {
+			steps = append(steps, stcp)
+		}
+	}
+	cp.Steps = steps
+}
+
+// restoreStep checks whether the step finished in the previous run with the
+// same inputs. If so, its outputs are reloaded from the runtime data files,
+// which must not have changed since they were recorded.
+func (s *server) restoreStep(index int, inputHash string) bool {
+	st := &s.steps[index]
+	stcp := s.checkpoint.get(index)
+	if stcp == nil || stcp.Plugin != st.plugin || stcp.InputHash != inputHash {
+		return false
+	}
+	outputs := eputils.SchemaMapData{}
+	for _, out := range st.outputs {
+		data, has := s.plugin_dataattrs[out.name]
+		if !has || data.confidential {
+			log.Infof("Output %s of plugin %v is not saved in runtime data, cannot skip", out.name, st.plugin)
+			return false
+		}
+		buf, err := ioutil.ReadFile(data.filepath)
+		if err != nil || hashOf(buf) != stcp.Outputs[out.name] {
+			log.Infof("Output %s of plugin %v changed since last run, cannot skip", out.name, st.plugin)
+			return false
+		}
+		v := eputils.SchemaStructNew(out.schemaName)
+		if err := eputils.LoadSchemaStructFromYaml(v, string(buf)); err != nil {
+			log.Warningf("Load output %s from %s error, %v", out.name, data.filepath, err)
+			return false
+		}
+		outputs[out.name] = v
+	}
+	for name, v := range outputs {
+		s.plugin_data[name] = v
+	}
+	return true
+}
+
+// skipStep releases the plugin waiting for the step, so it can move on to
+// its next pending step.
+func (s *server) skipStep(index int) {
+	s.lock.Lock()
+	st := &s.steps[index]
+	st.skipped = true
+	st.pending = false
+	s.lock.Unlock()
+	close(st.started)
+}
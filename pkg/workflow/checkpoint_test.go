@@ -0,0 +1,93 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"io/ioutil"
+	"os"
+	fpath "path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+)
+
+func Test_checkpoint(t *testing.T) {
+	defer os.RemoveAll(RUNTIME_CHECKPOINT_DIR)
+
+	cp, err := loadCheckpoint("test-checkpoint", true)
+	require.NoError(t, err, "Load Checkpoint Error:")
+	require.Empty(t, cp.Steps)
+
+	err = cp.record(stepCheckpoint{Index: 0, Plugin: "p0", InputHash: "h0"})
+	require.NoError(t, err, "Record Checkpoint Error:")
+	err = cp.record(stepCheckpoint{Index: 1, Plugin: "p1", InputHash: "h1"})
+	require.NoError(t, err, "Record Checkpoint Error:")
+
+	cp, err = loadCheckpoint("test-checkpoint", true)
+	require.NoError(t, err, "Load Checkpoint Error:")
+	require.Len(t, cp.Steps, 2)
+	require.Equal(t, "h1", cp.get(1).InputHash)
+
+	cp.forget([]int{0})
+	require.Nil(t, cp.get(0))
+	require.NotNil(t, cp.get(1), "step not depending on the forgotten one is kept")
+
+	cp, err = loadCheckpoint("test-checkpoint", false)
+	require.NoError(t, err, "Load Checkpoint Error:")
+	require.Empty(t, cp.Steps)
+
+	err = ioutil.WriteFile(checkpointFile("test-broken"), []byte("steps: {"), 0600)
+	require.NoError(t, err)
+	_, err = loadCheckpoint("test-broken", true)
+	require.Equal(t, eputils.GetError("errCheckpoint"), err)
+}
+
+func Test_restoreStep(t *testing.T) {
+	defer os.RemoveAll(RUNTIME_CHECKPOINT_DIR)
+
+	datafile := fpath.Join(RUNTIME_DATA_DIR, "test-restore")
+	yml := "content: restored\n"
+	require.NoError(t, os.MkdirAll(RUNTIME_DATA_DIR, 0700))
+	require.NoError(t, ioutil.WriteFile(datafile, []byte(yml), 0600))
+	defer os.Remove(datafile)
+
+	s := &server{
+		steps: []step{
+			{
+				plugin:  "test",
+				outputs: []io{{name: "test-restore", schemaName: "test.test-output"}},
+				started: make(chan bool),
+			},
+		},
+		plugin_data: eputils.SchemaMapData{},
+		plugin_dataattrs: map[string]dataAttr{
+			"test-restore": {name: "test-restore", filepath: datafile},
+		},
+	}
+	s.checkpoint, _ = loadCheckpoint("test-restore", true)
+	require.False(t, s.restoreStep(0, "h0"), "step without checkpoint must run")
+
+	require.NoError(t, s.checkpoint.record(stepCheckpoint{
+		Index:     0,
+		Plugin:    "test",
+		InputHash: "h0",
+		Outputs:   map[string]string{"test-restore": hashOf([]byte(yml))},
+	}))
+	require.False(t, s.restoreStep(0, "h1"), "step with changed inputs must run")
+	require.True(t, s.restoreStep(0, "h0"), "step with unchanged inputs should be skipped")
+	require.Contains(t, s.plugin_data, "test-restore")
+
+	require.NoError(t, ioutil.WriteFile(datafile, []byte("content: changed\n"), 0600))
+	require.False(t, s.restoreStep(0, "h0"), "step with changed outputs must run")
+
+	s.skipStep(0)
+	_, open := <-s.steps[0].started
+	require.False(t, open)
+	require.True(t, s.steps[0].skipped)
+}
@@ -0,0 +1,127 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"fmt"
+	"strings"
+
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func (s *server) findContainer(name string) *wfapi.ContainersItems0 {
+	for _, ctn := range s.workflow.Spec.Containers {
+		if ctn.Name == name {
+			return ctn
+		}
+	}
+	return nil
+}
+
+func (s *server) stepRunner(st *step) string {
+	if len(st.container) > 0 {
+		if ctn := s.findContainer(st.container); ctn != nil {
+			return fmt.Sprintf("container: %s, image: %s", ctn.Name, ctn.Image)
+		}
+		return fmt.Sprintf("container: %s, *container not found*", st.container)
+	}
+	if path := pluginExecutable(st.plugin); len(path) > 0 {
+		return "executable: " + path
+	}
+	if isBuiltInPlugin(st.plugin) {
+		return "built-in"
+	}
+	return "*plugin not found*"
+}
+
+// dataSource tells where the input data of step k comes from when the
+// workflow runs: the output of an earlier step or the init data.
+func (s *server) dataSource(k int, name string) string {
+	for i := k - 1; i >= 0; i-- {
+		for _, out := range s.steps[i].outputs {
+			if out.name == name {
+				return fmt.Sprintf("step %d", i+1)
+			}
+		}
+	}
+	if data, has := s.plugin_dataattrs[name]; has {
+		if data.confidential {
+			return "init data, confidential"
+		}
+		if len(data.filepath) > 0 && eputils.FileExists(data.filepath) {
+			return "init data, " + data.filepath
+		}
+		return "init data"
+	}
+	return "*not found*"
+}
+
+func (s *server) plan() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Workflow: %s, %d steps, parallel: %d\n", s.name, len(s.steps), s.parallel)
+	for k := range s.steps {
+		st := &s.steps[k]
+		fmt.Fprintf(&b, "Step %d: %s (%s)\n", k+1, st.plugin, s.stepRunner(st))
+		if len(st.when) > 0 {
+			fmt.Fprintf(&b, "    %-8s%s\n", "when:", strings.Join(st.when, ", "))
+		}
+		for n, in := range st.inputs {
+			label := ""
+			if n == 0 {
+				label = "input:"
+			}
+			fmt.Fprintf(&b, "    %-8s%s, schema: %s, from: %s\n", label, in.name, in.schemaName, s.dataSource(k, in.name))
+		}
+		for n, out := range st.outputs {
+			label := ""
+			if n == 0 {
+				label = "output:"
+			}
+			fmt.Fprintf(&b, "    %-8s%s, schema: %s\n", label, out.name, out.schemaName)
+		}
+		if st.timeout > 0 || st.retries > 0 {
+			fmt.Fprintf(&b, "    %-8s%v, retries: %d, backoff: %v\n", "timeout:", st.timeout, st.retries, st.backoff)
+		}
+		if s.parallel > 1 && len(st.deps) > 0 {
+			deps := []string{}
+			for _, d := range st.deps {
+				deps = append(deps, fmt.Sprintf("%d", d+1))
+			}
+			fmt.Fprintf(&b, "    %-8sstep %s\n", "after:", strings.Join(deps, ", "))
+		}
+	}
+	return b.String()
+}
+
+// DryRun loads the workflow the same way as Start and prints the steps it
+// would run, without starting the workflow server or any plugin.
+func DryRun(name string, configFile string) error {
+	log.Infof("load workflow config file %v", configFile)
+	wf := wfapi.Workflow{}
+	if err := eputils.LoadSchemaStructFromYamlFile(&wf, configFile); err != nil {
+		return err
+	}
+
+	s := &server{
+		name:             name,
+		workflow:         &wf,
+		steps:            []step{},
+		plugin_dataattrs: map[string]dataAttr{},
+	}
+	if err := s.loadSteps(); err != nil {
+		return err
+	}
+	s.loadPluginConfig()
+	if err := s.loadPluginData(); err != nil {
+		return err
+	}
+	fmt.Print(s.plan())
+	return nil
+}
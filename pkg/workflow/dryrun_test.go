@@ -0,0 +1,60 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+)
+
+func Test_plan(t *testing.T) {
+	s := &server{
+		name: "test-plan",
+		workflow: &wfapi.Workflow{
+			Spec: &wfapi.WorkflowSpec{
+				Containers: wfapi.Containers{
+					&wfapi.ContainersItems0{Name: "ep-plugin", Image: "conductor/plugin:test"},
+				},
+			},
+		},
+		steps: []step{
+			{
+				plugin:  "parser",
+				inputs:  []io{{name: "ep-params", schemaName: "parser.ep-params"}},
+				outputs: []io{{name: "files", schemaName: "parser.files"}},
+			},
+			{
+				plugin:    "downloader",
+				container: "ep-plugin",
+				inputs:    []io{{name: "files", schemaName: "downloader.files"}},
+			},
+			{
+				plugin: "unknown",
+				inputs: []io{{name: "missing", schemaName: "unknown.missing"}},
+			},
+		},
+		parallel: 2,
+		plugin_dataattrs: map[string]dataAttr{
+			"ep-params": {name: "ep-params", confidential: true},
+		},
+	}
+	s.buildGraph()
+
+	require.Equal(t, `Workflow: test-plan, 3 steps, parallel: 2
+Step 1: parser (*plugin not found*)
+    input:  ep-params, schema: parser.ep-params, from: init data, confidential
+    output: files, schema: parser.files
+Step 2: downloader (container: ep-plugin, image: conductor/plugin:test)
+    input:  files, schema: downloader.files, from: step 1
+    after:  step 1
+Step 3: unknown (*plugin not found*)
+    input:  missing, schema: unknown.missing, from: *not found*
+`, s.plan())
+}
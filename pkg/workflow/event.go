@@ -0,0 +1,89 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	EventWorkflowStart = "workflow-start"
+	EventWorkflowEnd   = "workflow-end"
+	EventStepStart     = "step-start"
+	EventStepSkip      = "step-skip"
+	EventStepRetry     = "step-retry"
+	EventStepProgress  = "step-progress"
+	EventStepEnd       = "step-end"
+	EventPluginConnect = "plugin-connect"
+	EventPluginLog     = "plugin-log"
+
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+// Event is one line of the JSON lines event stream of the workflow server.
+type Event struct {
+	Time     string  `json:"time"`
+	Type     string  `json:"type"`
+	Workflow string  `json:"workflow,omitempty"`
+	Step     int     `json:"step,omitempty"`
+	Plugin   string  `json:"plugin,omitempty"`
+	Level    string  `json:"level,omitempty"`
+	Message  string  `json:"message,omitempty"`
+	Phase    string  `json:"phase,omitempty"`
+	Current  int64   `json:"current,omitempty"`
+	Total    int64   `json:"total,omitempty"`
+	Result   string  `json:"result,omitempty"`
+	Code     string  `json:"code,omitempty"`
+	Duration float64 `json:"duration,omitempty"`
+}
+
+var (
+	// EventOutput receives the workflow events as JSON lines when it is set.
+	// If it is os.Stdout, the plugin logs are only sent as events.
+	EventOutput *os.File
+	eventLock   sync.Mutex
+)
+
+func emitEvent(ev Event) {
+	if EventOutput == nil {
+		return
+	}
+	ev.Time = time.Now().Format(time.RFC3339Nano)
+	buf, err := json.Marshal(ev)
+	if err != nil {
+		log.Debugf("Failed to marshal event: %v", err)
+		return
+	}
+	eventLock.Lock()
+	defer eventLock.Unlock()
+	if _, err := EventOutput.Write(append(buf, '\n')); err != nil {
+		log.Debugf("Failed to write event: %v", err)
+	}
+}
+
+// emitResult sets the result of the event from err, including the error
+// code for Edge Conductor errors, and emits it.
+func emitResult(ev Event, err error) {
+	if err == nil {
+		ev.Result = ResultSuccess
+	} else {
+		ev.Result = ResultError
+		ev.Message = err.Error()
+		if ecerr, ok := err.(*eputils.EC_errors); ok {
+			ev.Code = ecerr.Code()
+		}
+	}
+	emitEvent(ev)
+}
@@ -0,0 +1,58 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	fpath "path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+)
+
+func Test_emitEvent(t *testing.T) {
+	emitEvent(Event{Type: EventWorkflowStart})
+
+	f, err := os.Create(fpath.Join(t.TempDir(), "events.json"))
+	require.NoError(t, err)
+	EventOutput = f
+	defer func() { EventOutput = nil }()
+
+	emitEvent(Event{Type: EventWorkflowStart, Workflow: "test"})
+	emitResult(Event{Type: EventStepEnd, Workflow: "test", Step: 1, Plugin: "p0"}, nil)
+	emitResult(Event{Type: EventStepEnd, Workflow: "test", Step: 2, Plugin: "p1"}, eputils.GetError("errPluginComplete"))
+	emitResult(Event{Type: EventWorkflowEnd, Workflow: "test"}, errors.New("test error"))
+	require.NoError(t, f.Close())
+
+	f, err = os.Open(f.Name())
+	require.NoError(t, err)
+	defer f.Close()
+	events := []Event{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		ev := Event{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &ev), "Each line should be one JSON event")
+		require.NotEmpty(t, ev.Time)
+		events = append(events, ev)
+	}
+	require.Len(t, events, 4)
+
+	require.Equal(t, EventWorkflowStart, events[0].Type)
+	require.Empty(t, events[0].Result)
+	require.Equal(t, ResultSuccess, events[1].Result)
+	require.Equal(t, "p0", events[1].Plugin)
+	require.Equal(t, ResultError, events[2].Result)
+	require.Equal(t, eputils.GetError("errPluginComplete").(*eputils.EC_errors).Code(), events[2].Code)
+	require.Equal(t, ResultError, events[3].Result)
+	require.Equal(t, "test error", events[3].Message)
+	require.Empty(t, events[3].Code)
+}
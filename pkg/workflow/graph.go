@@ -0,0 +1,90 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+func hasCommonData(a, b []io) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x.name == y.name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dependsOn tells whether step j has to wait for the earlier step i.
+// Besides reading the data written by step i, step j also has to wait if it
+// overwrites data step i reads or writes, so that every step sees the same
+// data as when the steps run one by one. Steps of the same plugin keep their
+// order as one plugin runs one step at a time, and steps without any data
+// can not be ordered by data, so they are kept in place.
+func dependsOn(i, j *step) bool {
+	switch {
+	case i.plugin == j.plugin:
+		return true
+	case len(i.inputs)+len(i.outputs) == 0, len(j.inputs)+len(j.outputs) == 0:
+		return true
+	case hasCommonData(i.outputs, j.inputs):
+		return true
+	case hasCommonData(i.inputs, j.outputs):
+		return true
+	case hasCommonData(i.outputs, j.outputs):
+		return true
+	}
+	return false
+}
+
+// buildGraph records for each step the earlier steps it depends on.
+func (s *server) buildGraph() {
+	for j := range s.steps {
+		s.steps[j].deps = nil
+		for i := 0; i < j; i++ {
+			if dependsOn(&s.steps[i], &s.steps[j]) {
+				s.steps[j].deps = append(s.steps[j].deps, i)
+			}
+		}
+	}
+}
+
+// dependents returns step k and the later steps depending on it, directly
+// or through other steps.
+func (s *server) dependents(k int) []int {
+	found := map[int]bool{k: true}
+	steps := []int{k}
+	for j := k + 1; j < len(s.steps); j++ {
+		for _, d := range s.steps[j].deps {
+			if found[d] {
+				found[j] = true
+				steps = append(steps, j)
+				break
+			}
+		}
+	}
+	return steps
+}
+
+func (s *server) isReady(k int) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	st := &s.steps[k]
+	if st.scheduled || st.done {
+		return false
+	}
+	for _, d := range st.deps {
+		if !s.steps[d].done {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *server) setStepDone(k int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.steps[k].done = true
+}
@@ -0,0 +1,109 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_buildGraph(t *testing.T) {
+	s := &server{
+		steps: []step{
+			{
+				plugin:  "parser",
+				inputs:  []io{{name: "manifest"}},
+				outputs: []io{{name: "images"}, {name: "files"}},
+			},
+			{
+				plugin: "image-downloader",
+				inputs: []io{{name: "ep-params"}, {name: "images"}},
+			},
+			{
+				plugin:  "file-downloader",
+				inputs:  []io{{name: "ep-params"}, {name: "files"}},
+				outputs: []io{{name: "files"}},
+			},
+			{
+				plugin:  "injector",
+				inputs:  []io{{name: "files"}},
+				outputs: []io{{name: "images"}},
+			},
+			{
+				plugin: "cleanup",
+			},
+			{
+				plugin: "image-downloader",
+				inputs: []io{{name: "ep-params"}},
+			},
+		},
+	}
+	s.buildGraph()
+
+	require.Empty(t, s.steps[0].deps)
+	require.Equal(t, []int{0}, s.steps[1].deps, "read after write")
+	require.Equal(t, []int{0}, s.steps[2].deps, "independent of image downloader")
+	require.Equal(t, []int{0, 1, 2}, s.steps[3].deps, "write after read and write")
+	require.Equal(t, []int{0, 1, 2, 3}, s.steps[4].deps, "step without data keeps its place")
+	require.Equal(t, []int{1, 4}, s.steps[5].deps, "same plugin keeps its order")
+
+	require.Equal(t, []int{1, 3, 4, 5}, s.dependents(1), "file downloader does not depend on image downloader")
+	require.Equal(t, []int{5}, s.dependents(5))
+
+	require.True(t, s.isReady(0))
+	require.False(t, s.isReady(1))
+	s.setStepDone(0)
+	require.True(t, s.isReady(1))
+	require.True(t, s.isReady(2))
+	require.False(t, s.isReady(3))
+}
+
+func Test_runStepFailsWhileOthersRun(t *testing.T) {
+	defer os.RemoveAll(RUNTIME_CHECKPOINT_DIR)
+
+	newStep := func(k int, plugin string) step {
+		return step{
+			index:    k,
+			plugin:   plugin,
+			started:  make(chan bool),
+			finished: make(chan error),
+		}
+	}
+	s := &server{
+		steps:    []step{newStep(0, "failing"), newStep(1, "slow"), newStep(2, "next")},
+		parallel: 2,
+		data:     &wfapi.WorkflowData{},
+	}
+	s.steps[2].deps = []int{0, 1}
+	s.checkpoint, _ = loadCheckpoint("test-run", false)
+
+	errFailed := errors.New("failed")
+	go func() {
+		<-s.steps[0].started
+		<-s.steps[1].started
+		s.steps[0].finished <- errFailed
+		time.Sleep(50 * time.Millisecond)
+		s.steps[1].finished <- nil
+	}()
+
+	errc := make(chan error)
+	go func() { errc <- s.run() }()
+	select {
+	case err := <-errc:
+		require.Equal(t, errFailed, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("run did not return")
+	}
+	require.Nil(t, s.checkpoint.get(0), "failed step is not checkpointed")
+	require.NotNil(t, s.checkpoint.get(1), "step in flight is checkpointed")
+	require.False(t, s.steps[2].scheduled, "no step is kicked off after the failure")
+}
@@ -12,7 +12,10 @@ import (
 	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
 	certmgr "github.com/intel/edge-conductor/pkg/certmgr"
 	"github.com/intel/edge-conductor/pkg/eputils"
+	goio "io"
 	"net"
+	"os"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
@@ -32,10 +35,10 @@ func (s *server) serve(address string) error {
 	}
 	serverCreds := credentials.NewTLS(serverTLSConfig)
 
-	grpcServer := grpc.NewServer(grpc.Creds(serverCreds))
-	wfapi.RegisterWorkflowServer(grpcServer, s)
+	s.grpcServer = grpc.NewServer(grpc.Creds(serverCreds))
+	wfapi.RegisterWorkflowServer(s.grpcServer, s)
 	go func() {
-		if err := grpcServer.Serve(lis); err != nil {
+		if err := s.grpcServer.Serve(lis); err != nil {
 			log.Errorf("failed to serve: %v", err)
 			s.errch <- err
 			s.finished <- true
@@ -45,31 +48,60 @@ func (s *server) serve(address string) error {
 }
 
 func (s *server) PluginConnect(ctx context.Context, req *wfapi.PluginConnectRequest) (*wfapi.PluginConnectResponse, error) {
-	log.Infof("PluginConnect: plugin %v\n", req.Plugin.Name)
+	log.Infof("PluginConnect: plugin %v, version %v, protocol version %v\n", req.Plugin.Name, req.Version, req.ProtocolVersion)
 	res := &wfapi.PluginConnectResponse{Result: &wfapi.ConnectResult{Return: wfapi.ConnectResult_Connected}}
+	if err := checkPluginVersion(req); err != nil {
+		res.Result.Return = wfapi.ConnectResult_Error
+		go func() { s.errch <- err }()
+		return res, nil
+	}
+	s.addPluginVersion(req)
 	if req.Plugin.Name == "__init__" {
 		log.Infof("PluginConnect: __init__\n")
 		res.WorkflowData = s.data
 		return res, nil
 	}
-	st := s.getPendingStep(req.Plugin.Name)
-	if st == nil {
-		log.Infof("PluginConnect: no pending step needs this plugin\n")
-		res.Result.Return = wfapi.ConnectResult_Completed
-		return res, nil
+	for {
+		st := s.getPendingStep(req.Plugin.Name)
+		if st == nil {
+			log.Infof("PluginConnect: no pending step needs this plugin\n")
+			res.Result.Return = wfapi.ConnectResult_Completed
+			return res, nil
+		}
+		log.Infof("PluginConnect: wait\n")
+		<-st.started
+		s.lock.Lock()
+		skipped, aborted := st.skipped, st.aborted
+		st.pending = false
+		s.lock.Unlock()
+		if aborted {
+			log.Errorf("PluginConnect: step of plugin %v is aborted", req.Plugin.Name)
+			res.Result.Return = wfapi.ConnectResult_Error
+			return res, nil
+		}
+		if !skipped {
+			log.Infof("PluginConnect: plugin %v is connected", req.Plugin.Name)
+			emitEvent(Event{Type: EventPluginConnect, Workflow: s.name, Step: st.index + 1, Plugin: req.Plugin.Name})
+			res.WorkflowData = st.data
+			return res, nil
+		}
 	}
-	log.Infof("PluginConnect: wait\n")
-	<-st.started
-	s.current.pending = false
-	log.Infof("PluginConnect: plugin %v is connected", req.Plugin.Name)
-	res.WorkflowData = s.data
-	return res, nil
 }
 
 func (s *server) PluginPutLog(logstream wfapi.Workflow_PluginPutLogServer) error {
 	for {
 		if l, err := logstream.Recv(); err == nil {
-			fmt.Printf("%s", l.Log)
+			if EventOutput != nil {
+				msg := l.Message
+				if len(msg) == 0 {
+					msg = strings.TrimRight(l.Log, "\n")
+				}
+				emitEvent(Event{Type: EventPluginLog, Workflow: s.name, Plugin: l.Plugin, Level: l.Level, Message: msg})
+			}
+			if EventOutput != os.Stdout {
+				s.progress.clear()
+				fmt.Printf("%s", l.Log)
+			}
 		} else {
 			//cancelled ?
 			log.Debugf("logstream, err :%v\n", err)
@@ -79,15 +111,41 @@ func (s *server) PluginPutLog(logstream wfapi.Workflow_PluginPutLogServer) error
 	return nil
 }
 
+func (s *server) PluginReportProgress(stream wfapi.Workflow_PluginReportProgressServer) error {
+	for {
+		p, err := stream.Recv()
+		if err == goio.EOF {
+			return stream.SendAndClose(&wfapi.Result{Return: wfapi.Result_Success})
+		}
+		if err != nil {
+			log.Debugf("progress stream, err :%v\n", err)
+			return err
+		}
+		s.reportProgress(p)
+	}
+}
+
 func (s *server) PluginComplete(ctx context.Context, req *wfapi.PluginCompleteRequest) (*wfapi.Result, error) {
 	log.Infof("PluginComplete: plugin %v, res %v", req.Plugin.Name, req.Result.Return)
+	st := s.getRunningStep(req.Plugin.Name)
+	if st == nil {
+		log.Errorf("PluginComplete: no running step for plugin %v", req.Plugin.Name)
+		return nil, eputils.GetError("errPluginComplete")
+	}
+	r := &wfapi.Result{Return: wfapi.Result_Success}
 	if req.Result.Return != wfapi.Result_Success {
 		log.Errorf("PluginComplete error: plugin %v, res %v", req.Plugin.Name, req.Result.Return)
-		s.errch <- eputils.GetError("errPluginComplete")
-		s.finished <- true
+		// Mark the step to run again before the plugin connects for its
+		// next step, otherwise the plugin finds nothing to do and exits.
+		s.retryStep(st)
+		if err := s.finishStep(st, eputils.GetError("errPluginComplete")); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+	st.data.PluginData = req.WorkflowData.PluginData
+	if err := s.finishStep(st, nil); err != nil {
+		return nil, err
 	}
-	s.data.PluginData = req.WorkflowData.PluginData
-	r := &wfapi.Result{Return: wfapi.Result_Success}
-	s.current.finished <- true
 	return r, nil
 }
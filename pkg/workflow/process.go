@@ -0,0 +1,79 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"os"
+	"os/exec"
+	fpath "path/filepath"
+
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// PluginDir is where the standalone plugin executables are looked up, by
+// plugin name. A plugin executable is started with the address of the
+// workflow server and the log level, the same as conductor-plugin.
+var PluginDir = "addon/plugins"
+
+// pluginExecutable returns the path of the executable of plugin name in
+// PluginDir, or an empty string if there is none.
+func pluginExecutable(name string) string {
+	if len(PluginDir) == 0 {
+		return ""
+	}
+	path := fpath.Join(PluginDir, name)
+	fi, err := os.Stat(path)
+	if err != nil || !fi.Mode().IsRegular() || fi.Mode().Perm()&0111 == 0 {
+		return ""
+	}
+	return path
+}
+
+func (s *server) startProcess(name, path, address string) error {
+	if _, has := s.processes[name]; has {
+		return nil
+	}
+	logLevel := "Info"
+	if log.GetLevel() == log.DebugLevel {
+		logLevel = "Debug"
+	}
+	log.Infof("start plugin %s: %s\n", name, path)
+	cmd := exec.Command(path, address, logLevel)
+	cmd.Stdout = log.StandardLogger().Out
+	cmd.Stderr = log.StandardLogger().Out
+	if err := cmd.Start(); err != nil {
+		log.Errorf("Failed to start plugin %s: %v", path, err)
+		return eputils.GetError("errPluginExec")
+	}
+	s.processes[name] = cmd
+	go func() {
+		err := cmd.Wait()
+		s.lock.Lock()
+		stopping := s.stopping
+		s.lock.Unlock()
+		if err != nil && !stopping {
+			log.Errorf("Plugin %s exited: %v", path, err)
+			s.errch <- eputils.GetError("errPluginExec")
+		}
+	}()
+	return nil
+}
+
+// stopProcesses kills the plugin executables which are still running.
+func (s *server) stopProcesses() {
+	s.lock.Lock()
+	s.stopping = true
+	s.lock.Unlock()
+	for name, cmd := range s.processes {
+		log.Debugf("stop plugin %s\n", name)
+		if err := cmd.Process.Kill(); err != nil {
+			log.Debugf("Failed to stop plugin %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"io/ioutil"
+	"os/exec"
+	fpath "path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+)
+
+func Test_pluginExecutable(t *testing.T) {
+	dir := t.TempDir()
+	defer func(d string) { PluginDir = d }(PluginDir)
+	PluginDir = dir
+
+	require.NoError(t, ioutil.WriteFile(fpath.Join(dir, "site-plugin"), []byte("#!/bin/sh\n"), 0700))
+	require.NoError(t, ioutil.WriteFile(fpath.Join(dir, "not-executable"), []byte("#!/bin/sh\n"), 0600))
+
+	require.Equal(t, fpath.Join(dir, "site-plugin"), pluginExecutable("site-plugin"))
+	require.Empty(t, pluginExecutable("not-executable"))
+	require.Empty(t, pluginExecutable("unknown"))
+
+	PluginDir = ""
+	require.Empty(t, pluginExecutable("site-plugin"))
+}
+
+func Test_startProcess(t *testing.T) {
+	dir := t.TempDir()
+	args := fpath.Join(dir, "args")
+	script := "#!/bin/sh\necho \"$@\" > " + args + "\nexec sleep 60\n"
+	require.NoError(t, ioutil.WriteFile(fpath.Join(dir, "site-plugin"), []byte(script), 0700))
+	require.NoError(t, ioutil.WriteFile(fpath.Join(dir, "failed-plugin"), []byte("#!/bin/sh\nexit 1\n"), 0700))
+
+	s := &server{
+		errch:     make(chan error),
+		processes: map[string]*exec.Cmd{},
+	}
+	require.NoError(t, s.startProcess("site-plugin", fpath.Join(dir, "site-plugin"), "localhost:50088"))
+	require.NoError(t, s.startProcess("site-plugin", fpath.Join(dir, "site-plugin"), "localhost:50088"))
+	require.Len(t, s.processes, 1, "one process per plugin")
+	require.Eventually(t, func() bool {
+		buf, err := ioutil.ReadFile(args)
+		return err == nil && string(buf) == "localhost:50088 Info\n"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, s.startProcess("failed-plugin", fpath.Join(dir, "failed-plugin"), "localhost:50088"))
+	require.Equal(t, eputils.GetError("errPluginExec"), <-s.errch)
+
+	require.Equal(t, eputils.GetError("errPluginExec"), s.startProcess("missing", fpath.Join(dir, "missing"), "localhost:50088"))
+
+	s.stopProcesses()
+	select {
+	case err := <-s.errch:
+		t.Errorf("unexpected error of stopped plugin: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,170 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"fmt"
+	goio "io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+
+	"github.com/moby/term"
+	log "github.com/sirupsen/logrus"
+)
+
+const progressWidth = 30
+
+// progressBar shows the progress reported by the plugins. On a terminal the
+// progress of each running step is drawn as a bar on the last lines, which
+// are cleared before anything else is written. Otherwise a log line is
+// written when a step enters a new phase or another tenth of the total.
+type progressBar struct {
+	lock     sync.Mutex
+	out      goio.Writer
+	terminal bool
+	lines    int
+	bars     map[int]string
+	last     map[int]string
+}
+
+func newProgressBar(out *os.File) *progressBar {
+	_, terminal := term.GetFdInfo(out)
+	return &progressBar{out: out, terminal: terminal, bars: map[int]string{}, last: map[int]string{}}
+}
+
+func progressText(step int, p *wfapi.Progress) string {
+	text := fmt.Sprintf("[step %d] %s", step, p.Plugin)
+	if len(p.Phase) > 0 {
+		text += ": " + p.Phase
+	}
+	if p.Total <= 0 {
+		return fmt.Sprintf("%s %d", text, p.Current)
+	}
+	current := p.Current
+	if current > p.Total {
+		current = p.Total
+	}
+	n := int(current * progressWidth / p.Total)
+	bar := strings.Repeat("=", n)
+	if n < progressWidth {
+		bar += ">" + strings.Repeat(" ", progressWidth-n-1)
+	}
+	return fmt.Sprintf("%s [%s] %d/%d %d%%", text, bar, p.Current, p.Total, current*100/p.Total)
+}
+
+// progressKey changes when the progress of a step is worth another log line.
+func progressKey(p *wfapi.Progress) string {
+	if p.Total <= 0 {
+		return p.Phase
+	}
+	return fmt.Sprintf("%s/%d", p.Phase, p.Current*10/p.Total)
+}
+
+func (b *progressBar) update(step int, p *wfapi.Progress) {
+	if b == nil {
+		return
+	}
+	text := progressText(step, p)
+	b.lock.Lock()
+	if b.terminal {
+		b.bars[step] = text
+		b.draw()
+		b.lock.Unlock()
+		return
+	}
+	key := progressKey(p)
+	changed := b.last[step] != key
+	b.last[step] = key
+	b.lock.Unlock()
+	if changed {
+		log.Infof("Progress %s", text)
+	}
+}
+
+// erase removes the drawn bars from the terminal. The lock must be held.
+func (b *progressBar) erase() {
+	for i := 0; i < b.lines; i++ {
+		if i > 0 {
+			fmt.Fprint(b.out, "\033[1A")
+		}
+		fmt.Fprint(b.out, "\r\033[K")
+	}
+	b.lines = 0
+}
+
+// draw redraws the bars of the running steps in step order. The lock must
+// be held.
+func (b *progressBar) draw() {
+	b.erase()
+	steps := make([]int, 0, len(b.bars))
+	for step := range b.bars {
+		steps = append(steps, step)
+	}
+	sort.Ints(steps)
+	for i, step := range steps {
+		if i > 0 {
+			fmt.Fprint(b.out, "\n")
+		}
+		fmt.Fprint(b.out, b.bars[step])
+	}
+	b.lines = len(steps)
+}
+
+// clear removes the progress bars from the terminal.
+func (b *progressBar) clear() {
+	if b == nil {
+		return
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.erase()
+}
+
+// done forgets the progress of a finished step and removes its bar.
+func (b *progressBar) done(step int) {
+	if b == nil {
+		return
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	delete(b.last, step)
+	if _, ok := b.bars[step]; ok {
+		delete(b.bars, step)
+		b.draw()
+	}
+}
+
+// Fire clears the progress bars before a log line is written.
+func (b *progressBar) Fire(entry *log.Entry) error {
+	b.clear()
+	return nil
+}
+
+func (b *progressBar) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (s *server) reportProgress(p *wfapi.Progress) {
+	step := 0
+	if st := s.getRunningStep(p.Plugin); st != nil {
+		step = st.index + 1
+	}
+	emitEvent(Event{
+		Type:     EventStepProgress,
+		Workflow: s.name,
+		Step:     step,
+		Plugin:   p.Plugin,
+		Phase:    p.Phase,
+		Current:  p.Current,
+		Total:    p.Total,
+	})
+	s.progress.update(step, p)
+}
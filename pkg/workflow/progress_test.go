@@ -0,0 +1,112 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	fpath "path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func Test_progressText(t *testing.T) {
+	p := &wfapi.Progress{Plugin: "p0", Phase: "pull images", Current: 3, Total: 10}
+	require.Equal(t, "[step 2] p0: pull images [=========>                    ] 3/10 30%", progressText(2, p))
+	p.Current = 10
+	require.Equal(t, "[step 2] p0: pull images ["+strings.Repeat("=", progressWidth)+"] 10/10 100%", progressText(2, p))
+	p.Current, p.Total = 7, 0
+	require.Equal(t, "[step 2] p0: pull images 7", progressText(2, p))
+}
+
+func Test_progressBar_terminal(t *testing.T) {
+	out := &bytes.Buffer{}
+	b := &progressBar{out: out, terminal: true, bars: map[int]string{}, last: map[int]string{}}
+
+	b.update(1, &wfapi.Progress{Plugin: "p0", Phase: "a", Current: 1, Total: 2})
+	require.Equal(t, 1, b.lines)
+	require.True(t, strings.HasPrefix(out.String(), "[step 1] p0: a ["))
+	out.Reset()
+
+	b.update(3, &wfapi.Progress{Plugin: "p2", Phase: "b", Current: 1})
+	require.Equal(t, "\r\033[K"+progressText(1, &wfapi.Progress{Plugin: "p0", Phase: "a", Current: 1, Total: 2})+"\n[step 3] p2: b 1", out.String(), "one bar for each running step")
+	require.Equal(t, 2, b.lines)
+	out.Reset()
+
+	b.update(1, &wfapi.Progress{Plugin: "p0", Phase: "a", Current: 2, Total: 2})
+	require.True(t, strings.HasPrefix(out.String(), "\r\033[K\033[1A\r\033[K[step 1] p0: a [="), "both bars are redrawn")
+	require.True(t, strings.HasSuffix(out.String(), "\n[step 3] p2: b 1"))
+	out.Reset()
+
+	require.NoError(t, b.Fire(&log.Entry{}))
+	require.Equal(t, "\r\033[K\033[1A\r\033[K", out.String(), "a log line clears the bars")
+	require.Equal(t, 0, b.lines)
+	out.Reset()
+
+	b.done(1)
+	require.Equal(t, "[step 3] p2: b 1", out.String(), "the bar of a finished step is removed")
+	require.Equal(t, 1, b.lines)
+	out.Reset()
+
+	b.done(3)
+	require.Equal(t, "\r\033[K", out.String())
+	require.Equal(t, 0, b.lines)
+	require.Empty(t, b.bars)
+
+	var nilBar *progressBar
+	nilBar.update(1, &wfapi.Progress{})
+	nilBar.clear()
+	nilBar.done(1)
+}
+
+func Test_progressBar_log(t *testing.T) {
+	logs := &bytes.Buffer{}
+	log.SetOutput(logs)
+	defer log.SetOutput(os.Stdout)
+
+	out := &bytes.Buffer{}
+	b := &progressBar{out: out, last: map[int]string{}}
+	for i := int64(0); i <= 20; i++ {
+		b.update(1, &wfapi.Progress{Plugin: "p0", Phase: "a", Current: i, Total: 20})
+	}
+	b.update(1, &wfapi.Progress{Plugin: "p0", Phase: "b"})
+	b.update(1, &wfapi.Progress{Plugin: "p0", Phase: "b", Current: 1})
+	b.done(1)
+
+	require.Empty(t, out.String())
+	require.Equal(t, 12, strings.Count(logs.String(), "Progress [step 1] p0"), "one line for each tenth and new phase")
+	require.Empty(t, b.last)
+}
+
+func Test_reportProgress(t *testing.T) {
+	f, err := os.Create(fpath.Join(t.TempDir(), "events.json"))
+	require.NoError(t, err)
+	EventOutput = f
+	defer func() { EventOutput = nil }()
+
+	s := &server{name: "test", steps: []step{{index: 0, plugin: "p1"}, {index: 1, plugin: "p0"}}}
+	s.reportProgress(&wfapi.Progress{Plugin: "p0", Phase: "pull images", Current: 3, Total: 10})
+	require.NoError(t, f.Close())
+
+	buf, err := ioutil.ReadFile(f.Name())
+	require.NoError(t, err)
+	ev := Event{}
+	require.NoError(t, json.Unmarshal(buf, &ev))
+	require.Equal(t, EventStepProgress, ev.Type)
+	require.Equal(t, 2, ev.Step)
+	require.Equal(t, "pull images", ev.Phase)
+	require.Equal(t, int64(3), ev.Current)
+	require.Equal(t, int64(10), ev.Total)
+}
@@ -0,0 +1,202 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"fmt"
+	"time"
+
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	docker "github.com/intel/edge-conductor/pkg/eputils/docker"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// retryStep marks the step to be kicked off again if it has retries left.
+func (s *server) retryStep(st *step) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if st.attempt >= st.retries {
+		return false
+	}
+	st.attempt++
+	st.pending = true
+	return true
+}
+
+// isRetrying tells whether a failed step has been marked to run again.
+func (s *server) isRetrying(st *step) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return st.pending
+}
+
+// backoffOf returns how long to wait before the next attempt of the step, the
+// backoff of the step is doubled after each retry.
+func backoffOf(st *step) time.Duration {
+	if st.attempt < 1 {
+		return 0
+	}
+	return st.backoff << uint(st.attempt-1)
+}
+
+// canRestart tells whether the plugin of a timed out step can be restarted.
+// A built-in plugin runs inside conductor and can not be stopped, and a
+// container can only be restarted when no other step is running in it.
+func (s *server) canRestart(st *step) bool {
+	if len(st.container) == 0 {
+		return false
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for k := range s.steps {
+		o := &s.steps[k]
+		if o.index != st.index && o.container == st.container && o.scheduled && !o.done {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *server) restartContainer(name string) error {
+	for _, ctn := range s.containers {
+		if ctn.Name == name {
+			return run_container(ctn)
+		}
+	}
+	log.Errorf("Cannot find container %s", name)
+	return eputils.GetError("errFind")
+}
+
+// abortStep gives up the step, so that the plugin connecting or completing
+// it late gets an error instead of waiting for it forever.
+func (s *server) abortStep(st *step) {
+	s.lock.Lock()
+	st.aborted = true
+	st.pending = false
+	s.lock.Unlock()
+	close(st.started)
+	select {
+	case <-st.finished:
+	default:
+	}
+}
+
+// finishStep passes the result of the plugin to the step waiting for it.
+func (s *server) finishStep(st *step, err error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if st.aborted {
+		log.Errorf("step of plugin %v is aborted", st.plugin)
+		return eputils.GetError("errPluginComplete")
+	}
+	select {
+	case st.finished <- err:
+		return nil
+	default:
+		log.Errorf("step of plugin %v is not waiting for a result", st.plugin)
+		return eputils.GetError("errPluginComplete")
+	}
+}
+
+// runStep kicks off step k and waits for it to complete. A failed step is
+// kicked off again while it has retries left, and a step which does not
+// complete in its timeout, or when the workflow is cancelled, is aborted, or
+// re-kicked after restarting its plugin container on timeout.
+func (s *server) runStep(k int) error {
+	st := &s.steps[k]
+	for {
+		err := func() error {
+			var timeout <-chan time.Time
+			if st.timeout > 0 {
+				timer := time.NewTimer(st.timeout)
+				defer timer.Stop()
+				timeout = timer.C
+			}
+			select {
+			case st.started <- true:
+			case <-timeout:
+				return eputils.GetError("errStepTimeout")
+			case <-s.cancel:
+				return eputils.GetError("errWorkflowCancel")
+			}
+			select {
+			case err := <-st.finished:
+				return err
+			case <-timeout:
+				return eputils.GetError("errStepTimeout")
+			case <-s.cancel:
+				return eputils.GetError("errWorkflowCancel")
+			}
+		}()
+		if err == nil {
+			return nil
+		}
+		if err == eputils.GetError("errWorkflowCancel") {
+			s.abortStep(st)
+			return err
+		}
+		if err == eputils.GetError("errStepTimeout") {
+			log.Errorf("plugin %v timed out after %v", st.plugin, st.timeout)
+			if !s.canRestart(st) || !s.retryStep(st) {
+				s.abortStep(st)
+				return err
+			}
+			log.Infof("restart container %s of plugin %v", st.container, st.plugin)
+			if err := s.restartContainer(st.container); err != nil {
+				return err
+			}
+		} else if !s.isRetrying(st) {
+			return err
+		}
+
+		backoff := backoffOf(st)
+		log.Warnf("retry plugin %v in %v, attempt %d of %d", st.plugin, backoff, st.attempt, st.retries)
+		emitEvent(Event{
+			Type:     EventStepRetry,
+			Workflow: s.name,
+			Step:     k + 1,
+			Plugin:   st.plugin,
+			Message:  fmt.Sprintf("%v, attempt %d of %d", err, st.attempt, st.retries),
+		})
+		select {
+		case <-time.After(backoff):
+		case <-s.cancel:
+			s.abortStep(st)
+			return eputils.GetError("errWorkflowCancel")
+		}
+	}
+}
+
+func stop_container(ctn *wfapi.ContainersItems0) error {
+	c := &pluginapi.ContainersItems0{}
+	if err := eputils.ConvertSchemaStruct(&ctn, c); err != nil {
+		log.Errorf("Convert containers data error: %v\n", err)
+		return eputils.GetError("errConvContainers")
+	}
+	log.Infof("remove container %s\n", c.Name)
+	return docker.DockerRemove(c)
+}
+
+// stop shuts down the gRPC server, the plugin executables and the plugin
+// containers when the workflow is cancelled.
+func (s *server) stop() {
+	if s.cancel != nil {
+		close(s.cancel)
+	}
+	if s.grpcServer != nil {
+		s.grpcServer.Stop()
+	}
+	s.stopProcesses()
+	for _, ctn := range s.containers {
+		if err := stop_container(ctn); err != nil {
+			log.Warnf("Failed to stop container %s: %v", ctn.Name, err)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	plugin "github.com/intel/edge-conductor/pkg/plugin"
+
+	mpatch "github.com/undefinedlabs/go-mpatch"
+)
+
+func newRetryServer(st step) *server {
+	st.plugin = "test"
+	st.pending = true
+	st.started = make(chan bool)
+	st.finished = make(chan error, 1)
+	st.data = &wfapi.WorkflowData{}
+	return &server{
+		name:  "test",
+		steps: []step{st},
+		containers: wfapi.Containers{
+			&wfapi.ContainersItems0{Name: "test"},
+		},
+		pluginVersions: map[string]string{},
+	}
+}
+
+func connectPlugin(t *testing.T, s *server) {
+	res, err := s.PluginConnect(context.Background(), &wfapi.PluginConnectRequest{
+		Plugin:          &wfapi.Plugin{Name: "test"},
+		ProtocolVersion: plugin.PROTOCOL_VERSION,
+	})
+	require.NoError(t, err)
+	require.Equal(t, wfapi.ConnectResult_Connected, res.Result.Return)
+}
+
+func completePlugin(t *testing.T, s *server, ret wfapi.Result_Return) {
+	_, err := s.PluginComplete(context.Background(), &wfapi.PluginCompleteRequest{
+		Plugin:       &wfapi.Plugin{Name: "test"},
+		Result:       &wfapi.Result{Return: ret},
+		WorkflowData: &wfapi.WorkflowData{},
+	})
+	require.NoError(t, err)
+}
+
+func Test_runStep_retries(t *testing.T) {
+	s := newRetryServer(step{retries: 1, backoff: time.Millisecond})
+	go func() {
+		connectPlugin(t, s)
+		completePlugin(t, s, wfapi.Result_Error)
+		connectPlugin(t, s)
+		completePlugin(t, s, wfapi.Result_Success)
+	}()
+	require.NoError(t, s.runStep(0))
+	require.Equal(t, 1, s.steps[0].attempt)
+
+	s = newRetryServer(step{retries: 1})
+	go func() {
+		connectPlugin(t, s)
+		completePlugin(t, s, wfapi.Result_Error)
+		connectPlugin(t, s)
+		completePlugin(t, s, wfapi.Result_Error)
+	}()
+	require.Equal(t, eputils.GetError("errPluginComplete"), s.runStep(0), "step fails after the last retry")
+}
+
+func Test_runStep_timeout(t *testing.T) {
+	s := newRetryServer(step{timeout: 10 * time.Millisecond, retries: 1})
+	require.Equal(t, eputils.GetError("errStepTimeout"), s.runStep(0), "built-in plugin can not be restarted")
+	require.Equal(t, 0, s.steps[0].attempt)
+
+	s = newRetryServer(step{container: "test", timeout: 50 * time.Millisecond, retries: 1})
+	restarted := 0
+	p, err := mpatch.PatchMethod(run_container, func(*wfapi.ContainersItems0) error {
+		restarted++
+		go func() {
+			connectPlugin(t, s)
+			completePlugin(t, s, wfapi.Result_Success)
+		}()
+		return nil
+	})
+	require.NoError(t, err)
+	defer unpatch(t, p)
+
+	go connectPlugin(t, s)
+	require.NoError(t, s.runStep(0))
+	require.Equal(t, 1, restarted)
+}
+
+func Test_runStep_abort(t *testing.T) {
+	s := newRetryServer(step{timeout: 10 * time.Millisecond})
+	go connectPlugin(t, s)
+	require.Equal(t, eputils.GetError("errStepTimeout"), s.runStep(0))
+	require.True(t, s.steps[0].aborted)
+
+	done := make(chan error)
+	go func() {
+		_, err := s.PluginComplete(context.Background(), &wfapi.PluginCompleteRequest{
+			Plugin:       &wfapi.Plugin{Name: "test"},
+			Result:       &wfapi.Result{Return: wfapi.Result_Success},
+			WorkflowData: &wfapi.WorkflowData{},
+		})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		require.Equal(t, eputils.GetError("errPluginComplete"), err, "late complete of an aborted step fails")
+	case <-time.After(5 * time.Second):
+		t.Fatal("late complete of an aborted step is blocked")
+	}
+
+	// A plugin waiting for the step when it is aborted gets an error.
+	s = newRetryServer(step{})
+	connected := make(chan wfapi.ConnectResult_Return)
+	go func() {
+		res, err := s.PluginConnect(context.Background(), &wfapi.PluginConnectRequest{
+			Plugin:          &wfapi.Plugin{Name: "test"},
+			ProtocolVersion: plugin.PROTOCOL_VERSION,
+		})
+		require.NoError(t, err)
+		connected <- res.Result.Return
+	}()
+	time.Sleep(10 * time.Millisecond)
+	s.abortStep(&s.steps[0])
+	select {
+	case ret := <-connected:
+		require.Equal(t, wfapi.ConnectResult_Error, ret)
+	case <-time.After(5 * time.Second):
+		t.Fatal("plugin waiting for an aborted step is blocked")
+	}
+}
+
+func Test_runStep_cancel(t *testing.T) {
+	s := newRetryServer(step{})
+	s.cancel = make(chan struct{})
+	go func() {
+		connectPlugin(t, s)
+		close(s.cancel)
+	}()
+	require.Equal(t, eputils.GetError("errWorkflowCancel"), s.runStep(0), "step without timeout is cancelled")
+	require.True(t, s.steps[0].aborted)
+}
+
+func Test_backoffOf(t *testing.T) {
+	st := &step{backoff: time.Second}
+	require.Equal(t, time.Duration(0), backoffOf(st))
+	st.attempt = 1
+	require.Equal(t, time.Second, backoffOf(st))
+	st.attempt = 3
+	require.Equal(t, 4*time.Second, backoffOf(st))
+}
@@ -0,0 +1,63 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"sort"
+
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	plugin "github.com/intel/edge-conductor/pkg/plugin"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// checkPluginVersion rejects a plugin which speaks another protocol version
+// or was generated against schemas different from the ones of the server.
+// Schemas unknown to the server, e.g. those of a standalone plugin, are not
+// checked.
+func checkPluginVersion(req *wfapi.PluginConnectRequest) error {
+	name := req.Plugin.Name
+	if req.ProtocolVersion != plugin.PROTOCOL_VERSION {
+		log.Errorf("Plugin %v (version %v) uses protocol version %v, but the workflow server requires protocol version %v",
+			name, req.Version, req.ProtocolVersion, plugin.PROTOCOL_VERSION)
+		return eputils.GetError("errPluginVersion")
+	}
+	for _, sc := range req.Schemas {
+		if h, has := eputils.SchemaStructHash(sc.Name); has && h != sc.Hash {
+			log.Errorf("Plugin %v (version %v) was generated against schema %v with hash %v, but the workflow server has hash %v",
+				name, req.Version, sc.Name, sc.Hash, h)
+			return eputils.GetError("errPluginVersion")
+		}
+	}
+	if req.Version != plugin.Version {
+		log.Warnf("Plugin %v version %v is different from the workflow server version %v", name, req.Version, plugin.Version)
+	}
+	return nil
+}
+
+// addPluginVersion records the version of a connected plugin.
+func (s *server) addPluginVersion(req *wfapi.PluginConnectRequest) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.pluginVersions[req.Plugin.Name] = req.Version
+}
+
+// logPluginVersions lists the versions of all plugins connected so far.
+func (s *server) logPluginVersions() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	names := []string{}
+	for n := range s.pluginVersions {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	log.Infof("Workflow server version %v, protocol version %v", plugin.Version, plugin.PROTOCOL_VERSION)
+	for _, n := range names {
+		log.Infof("Connected plugin %v, version %v", n, s.pluginVersions[n])
+	}
+}
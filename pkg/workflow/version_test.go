@@ -0,0 +1,72 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	plugin "github.com/intel/edge-conductor/pkg/plugin"
+)
+
+func Test_checkPluginVersion(t *testing.T) {
+	eputils.AddSchemaStruct("version-test.files", func() eputils.SchemaStruct { return &pluginapi.Files{} })
+	h, has := eputils.SchemaStructHash("version-test.files")
+	require.True(t, has)
+
+	req := func(protocol uint32, schemas ...*wfapi.Schema) *wfapi.PluginConnectRequest {
+		return &wfapi.PluginConnectRequest{
+			Plugin:          &wfapi.Plugin{Name: "version-test"},
+			ProtocolVersion: protocol,
+			Version:         "v0.0.1",
+			Schemas:         schemas,
+		}
+	}
+	errVersion := eputils.GetError("errPluginVersion")
+
+	require.NoError(t, checkPluginVersion(req(plugin.PROTOCOL_VERSION)))
+	require.NoError(t, checkPluginVersion(req(plugin.PROTOCOL_VERSION, &wfapi.Schema{Name: "version-test.files", Hash: h})))
+	require.NoError(t, checkPluginVersion(req(plugin.PROTOCOL_VERSION, &wfapi.Schema{Name: "version-test.unknown", Hash: "x"})))
+	require.Equal(t, errVersion, checkPluginVersion(req(0)))
+	require.Equal(t, errVersion, checkPluginVersion(req(plugin.PROTOCOL_VERSION+1)))
+	require.Equal(t, errVersion, checkPluginVersion(req(plugin.PROTOCOL_VERSION, &wfapi.Schema{Name: "version-test.files", Hash: "x"})))
+}
+
+func Test_PluginConnect_version(t *testing.T) {
+	s := &server{
+		name:           "version-test",
+		errch:          make(chan error),
+		pluginVersions: map[string]string{},
+		data:           &wfapi.WorkflowData{},
+	}
+
+	res, err := s.PluginConnect(context.Background(), &wfapi.PluginConnectRequest{Plugin: &wfapi.Plugin{Name: "__init__"}})
+	require.NoError(t, err)
+	require.Equal(t, wfapi.ConnectResult_Error, res.Result.Return)
+	select {
+	case err = <-s.errch:
+		require.Equal(t, eputils.GetError("errPluginVersion"), err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("rejected plugin is not reported")
+	}
+	require.Empty(t, s.pluginVersions)
+
+	res, err = s.PluginConnect(context.Background(), &wfapi.PluginConnectRequest{
+		Plugin:          &wfapi.Plugin{Name: "__init__"},
+		ProtocolVersion: plugin.PROTOCOL_VERSION,
+		Version:         plugin.Version,
+	})
+	require.NoError(t, err)
+	require.Equal(t, wfapi.ConnectResult_Connected, res.Result.Return)
+	require.Equal(t, map[string]string{"__init__": plugin.Version}, s.pluginVersions)
+}
@@ -0,0 +1,134 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// whenParams is what the when condition of a step is evaluated against: the
+// fields of ep-params, and the plugin data written by the earlier steps in
+// Data, e.g. `eq .Kitconfig.Cluster.Provider "kind"` or
+// `index .Data "ep-kubeconfig"`.
+type whenParams struct {
+	*pluginapi.EpParams
+	Data eputils.SchemaMapData
+}
+
+func parseWhen(when string) (*template.Template, error) {
+	return template.New("when").Parse("{{ " + when + " }}")
+}
+
+// addSteps appends the steps of workflow wf to the server. The steps of a
+// called workflow are added in place of the call step, and keep the when
+// conditions of the call steps on the way. callers are the workflows being
+// expanded, to reject a workflow calling itself.
+func (s *server) addSteps(wf *wfapi.WorkflowSpecWorkflowsItems0, when []string, callers []string) error {
+	for _, st := range wf.Steps {
+		conds := when
+		if len(st.When) > 0 {
+			if _, err := parseWhen(st.When); err != nil {
+				log.Errorf("Invalid when condition %q in workflow %s: %v", st.When, wf.Name, err)
+				return eputils.GetError("errWhen")
+			}
+			conds = append(append([]string{}, when...), st.When)
+		}
+
+		if len(st.Call) > 0 {
+			if len(st.Name) > 0 || len(st.Input) > 0 || len(st.Output) > 0 ||
+				st.Timeout > 0 || st.Retries > 0 || st.Backoff > 0 {
+				log.Errorf("Step calling workflow %s in workflow %s can only have a when condition", st.Call, wf.Name)
+				return eputils.GetError("errWorkflowCall")
+			}
+			for _, c := range callers {
+				if c == st.Call {
+					log.Errorf("Workflow %s calls itself: %s -> %s", st.Call, strings.Join(callers, " -> "), st.Call)
+					return eputils.GetError("errWorkflowCall")
+				}
+			}
+			called := findNameFromWf(s.workflow.Spec.Workflows, st.Call)
+			if called == nil {
+				log.Errorf("Workflow %s called by workflow %s is not found", st.Call, wf.Name)
+				return eputils.GetError("errWorkflowCall")
+			}
+			if err := s.addSteps(called, conds, append(append([]string{}, callers...), st.Call)); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(st.Name) == 0 {
+			log.Errorf("Step without plugin name or workflow to call in workflow %s", wf.Name)
+			return eputils.GetError("errWorkflowCall")
+		}
+
+		inputs := []io{}
+		for _, in := range st.Input {
+			inputs = append(inputs, io{
+				name:       in.Name,
+				schemaName: st.Name + "." + in.Schema,
+			})
+		}
+
+		outputs := []io{}
+		for _, out := range st.Output {
+			outputs = append(outputs, io{
+				name:       out.Name,
+				schemaName: st.Name + "." + out.Schema,
+			})
+		}
+		s.steps = append(s.steps, step{
+			index:    len(s.steps),
+			plugin:   st.Name,
+			inputs:   inputs,
+			outputs:  outputs,
+			when:     conds,
+			pending:  true,
+			started:  make(chan bool),
+			finished: make(chan error, 1),
+			timeout:  time.Duration(st.Timeout) * time.Second,
+			retries:  int(st.Retries),
+			backoff:  time.Duration(st.Backoff) * time.Second,
+		})
+	}
+	return nil
+}
+
+// evalWhen tells whether all the when conditions of step st are true.
+func (s *server) evalWhen(st *step) (bool, error) {
+	params := &whenParams{EpParams: s.epParams, Data: s.plugin_data}
+	for _, when := range st.when {
+		tpl, err := parseWhen(when)
+		if err != nil {
+			log.Errorf("Invalid when condition %q of plugin %s: %v", when, st.plugin, err)
+			return false, eputils.GetError("errWhen")
+		}
+		var b bytes.Buffer
+		if err := tpl.Execute(&b, params); err != nil {
+			log.Errorf("Failed to evaluate when condition %q of plugin %s: %v", when, st.plugin, err)
+			return false, eputils.GetError("errWhen")
+		}
+		ok, err := strconv.ParseBool(strings.TrimSpace(b.String()))
+		if err != nil {
+			log.Errorf("When condition %q of plugin %s is %q, not true or false", when, st.plugin, b.String())
+			return false, eputils.GetError("errWhen")
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,143 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+)
+
+func newCallServer(workflows ...*wfapi.WorkflowSpecWorkflowsItems0) *server {
+	return &server{
+		name: workflows[0].Name,
+		workflow: &wfapi.Workflow{
+			Spec: &wfapi.WorkflowSpec{Workflows: workflows},
+		},
+		steps: []step{},
+	}
+}
+
+func Test_loadSteps_call(t *testing.T) {
+	s := newCallServer(
+		&wfapi.WorkflowSpecWorkflowsItems0{
+			Name: "cluster-build",
+			Steps: []*wfapi.WorkflowSpecWorkflowsItems0StepsItems0{
+				{Name: "kind-parser"},
+				{Call: "service-build", When: `eq .Kitconfig.Cluster.Provider "kind"`},
+				{Name: "file-exporter"},
+			},
+		},
+		&wfapi.WorkflowSpecWorkflowsItems0{
+			Name: "service-build",
+			Steps: []*wfapi.WorkflowSpecWorkflowsItems0StepsItems0{
+				{Name: "service-parser"},
+				{Name: "service-injector", When: "true"},
+			},
+		},
+	)
+	require.NoError(t, s.loadSteps())
+
+	plugins := []string{}
+	for _, st := range s.steps {
+		plugins = append(plugins, st.plugin)
+	}
+	require.Equal(t, []string{"kind-parser", "service-parser", "service-injector", "file-exporter"}, plugins)
+	require.Empty(t, s.steps[0].when)
+	require.Equal(t, []string{`eq .Kitconfig.Cluster.Provider "kind"`}, s.steps[1].when)
+	require.Equal(t, []string{`eq .Kitconfig.Cluster.Provider "kind"`, "true"}, s.steps[2].when)
+	require.Equal(t, 3, s.steps[3].index)
+}
+
+func Test_loadSteps_callError(t *testing.T) {
+	cases := []struct {
+		name  string
+		steps []*wfapi.WorkflowSpecWorkflowsItems0StepsItems0
+		err   string
+	}{
+		{"call itself", []*wfapi.WorkflowSpecWorkflowsItems0StepsItems0{{Call: "loop"}}, "errWorkflowCall"},
+		{"call unknown", []*wfapi.WorkflowSpecWorkflowsItems0StepsItems0{{Call: "unknown"}}, "errWorkflowCall"},
+		{"call and plugin", []*wfapi.WorkflowSpecWorkflowsItems0StepsItems0{{Call: "other", Name: "p"}}, "errWorkflowCall"},
+		{"call with retries", []*wfapi.WorkflowSpecWorkflowsItems0StepsItems0{{Call: "other", Retries: 1}}, "errWorkflowCall"},
+		{"empty step", []*wfapi.WorkflowSpecWorkflowsItems0StepsItems0{{}}, "errWorkflowCall"},
+		{"when evaluated at run time", []*wfapi.WorkflowSpecWorkflowsItems0StepsItems0{{Name: "p", When: "eq .A"}}, ""},
+		{"broken when", []*wfapi.WorkflowSpecWorkflowsItems0StepsItems0{{Name: "p", When: "}}"}}, "errWhen"},
+	}
+	for _, tc := range cases {
+		s := newCallServer(
+			&wfapi.WorkflowSpecWorkflowsItems0{Name: "loop", Steps: tc.steps},
+			&wfapi.WorkflowSpecWorkflowsItems0{
+				Name:  "other",
+				Steps: []*wfapi.WorkflowSpecWorkflowsItems0StepsItems0{{Call: "loop"}},
+			},
+		)
+		err := s.loadSteps()
+		if len(tc.err) == 0 {
+			require.NoError(t, err, tc.name)
+		} else {
+			require.Equal(t, eputils.GetError(tc.err), err, tc.name)
+		}
+	}
+}
+
+func Test_evalWhen(t *testing.T) {
+	s := &server{
+		epParams: &pluginapi.EpParams{
+			Kitconfig: &pluginapi.Kitconfig{
+				Cluster: &pluginapi.KitconfigCluster{Provider: "kind"},
+			},
+		},
+		plugin_data: eputils.SchemaMapData{
+			"ep-kubeconfig": &pluginapi.Filecontent{Content: "config"},
+		},
+	}
+	cases := []struct {
+		when []string
+		run  bool
+		err  error
+	}{
+		{nil, true, nil},
+		{[]string{`eq .Kitconfig.Cluster.Provider "kind"`}, true, nil},
+		{[]string{`eq .Kitconfig.Cluster.Provider "kind"`, `ne .Kitconfig.Cluster.Provider "kind"`}, false, nil},
+		{[]string{`eq (index .Data "ep-kubeconfig").Content "config"`}, true, nil},
+		{[]string{`not (index .Data "clusterfiles")`}, true, nil},
+		{[]string{`.Kitconfig.Cluster.Provider`}, false, eputils.GetError("errWhen")},
+		{[]string{`.Kitconfig.Unknown`}, false, eputils.GetError("errWhen")},
+	}
+	for n, tc := range cases {
+		run, err := s.evalWhen(&step{plugin: "test", when: tc.when})
+		require.Equal(t, tc.err, err, "case %d", n)
+		require.Equal(t, tc.run, run, "case %d", n)
+	}
+}
+
+func Test_stepSchema(t *testing.T) {
+	cases := []struct {
+		name  string
+		step  string
+		valid bool
+	}{
+		{"plugin", "name: p", true},
+		{"call", "call: other", true},
+		{"call and plugin", "{name: p, call: other}", false},
+		{"empty step", "{when: \"true\"}", false},
+	}
+	for _, tc := range cases {
+		wf := &wfapi.Workflow{}
+		err := eputils.LoadSchemaStructFromYaml(wf, "spec:\n  workflows:\n  - name: loop\n    steps:\n    - "+tc.step+"\n")
+		if tc.valid {
+			require.NoError(t, err, tc.name)
+		} else {
+			require.Error(t, err, tc.name)
+			require.Contains(t, err.Error(), "must validate one and only one schema (oneOf)", tc.name)
+		}
+	}
+}
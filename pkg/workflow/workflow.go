@@ -15,9 +15,16 @@ import (
 	plugin "github.com/intel/edge-conductor/pkg/plugin"
 	"io/ioutil"
 	"os"
+	"os/exec"
+	"os/signal"
 	fpath "path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
 )
 
 const (
@@ -38,13 +45,26 @@ type io struct {
 }
 
 type step struct {
+	index     int
 	plugin    string
 	container string
 	pending   bool
+	skipped   bool
+	aborted   bool
+	scheduled bool
+	done      bool
 	started   chan bool
-	finished  chan bool
+	finished  chan error
+	timeout   time.Duration
+	retries   int
+	backoff   time.Duration
+	attempt   int
 	inputs    []io
 	outputs   []io
+	when      []string
+	deps      []int
+	data      *wfapi.WorkflowData
+	begin     time.Time
 }
 
 type server struct {
@@ -52,13 +72,23 @@ type server struct {
 	name             string
 	workflow         *wfapi.Workflow
 	steps            []step
-	current          *step
+	parallel         int
+	lock             sync.Mutex
 	plugin_data      eputils.SchemaMapData
+	epParams         *pluginapi.EpParams
 	plugin_dataattrs map[string]dataAttr
 	containers       wfapi.Containers
+	grpcServer       *grpc.Server
+	processes        map[string]*exec.Cmd
+	pluginVersions   map[string]string
+	progress         *progressBar
+	stopping         bool
+	cancel           chan struct{}
 	finished         chan bool
 	data             *wfapi.WorkflowData
 	errch            chan error
+	checkpoint       *checkpoint
+	resuming         bool
 }
 
 func isBuiltInPlugin(name string) bool {
@@ -94,10 +124,15 @@ func (s *server) setInitData() error {
 		return eputils.GetError("errMarshalInitData")
 	}
 	s.data.Data = json
+	if p, ok := initData.(*pluginapi.EpParams); ok {
+		s.epParams = p
+	}
 	return nil
 }
 
 func (s *server) getPendingStep(name string) *step {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	for k := range s.steps {
 		if s.steps[k].plugin == name && s.steps[k].pending {
 			return &s.steps[k]
@@ -106,6 +141,20 @@ func (s *server) getPendingStep(name string) *step {
 	return nil
 }
 
+// getRunningStep returns the step the plugin is working on. A plugin only
+// runs one step at a time, so the name is enough to find it.
+func (s *server) getRunningStep(name string) *step {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for k := range s.steps {
+		st := &s.steps[k]
+		if st.plugin == name && !st.pending && !st.skipped && !st.aborted && !st.done {
+			return st
+		}
+	}
+	return nil
+}
+
 func (s *server) IsConfidentialData(name string) bool {
 	if data, has := s.plugin_dataattrs[name]; has {
 		return data.confidential
@@ -190,9 +239,16 @@ func run_container(ctn *wfapi.ContainersItems0) error {
 	return nil
 }
 
-func (s *server) startPlugins() error {
+func (s *server) startPlugins(address string) error {
 	for _, st := range s.steps {
-		if len(st.container) == 0 && isBuiltInPlugin(st.plugin) {
+		if len(st.container) > 0 {
+			continue
+		}
+		if path := pluginExecutable(st.plugin); len(path) > 0 {
+			if err := s.startProcess(st.plugin, path, address); err != nil {
+				return err
+			}
+		} else if isBuiltInPlugin(st.plugin) {
 			log.Debugf("start plugin: %v\n", st.plugin)
 			if err := plugin.StartPlugin(st.plugin, s.errch); err != nil {
 				return err
@@ -219,116 +275,220 @@ func (s *server) loadSteps() error {
 		return eputils.GetError("errCmdNotSupported")
 	}
 	log.Infof("Current workflow: %s\n", wf.Name)
-	for _, st := range wf.Steps {
-		inputs := []io{}
-		for _, in := range st.Input {
-			inputs = append(inputs, io{
-				name:       in.Name,
-				schemaName: st.Name + "." + in.Schema,
-			})
-		}
-
-		outputs := []io{}
-		for _, out := range st.Output {
-			outputs = append(outputs, io{
-				name:       out.Name,
-				schemaName: st.Name + "." + out.Schema,
-			})
-		}
-		s.steps = append(s.steps, step{
-			plugin:   st.Name,
-			inputs:   inputs,
-			outputs:  outputs,
-			pending:  true,
-			started:  make(chan bool),
-			finished: make(chan bool),
-		})
-	}
-	if len(s.steps) > 0 {
-		s.current = &s.steps[0]
+	if err := s.addSteps(wf, nil, []string{wf.Name}); err != nil {
+		return err
 	}
+	s.parallel = int(wf.Parallel)
+	if s.parallel < 1 {
+		s.parallel = 1
+	}
+	s.buildGraph()
 	log.Debugf("steps: %v\n", s.steps)
 	return nil
 }
 
-func (s *server) run() error {
-	for k, st := range s.steps {
-		pdata := eputils.SchemaMapData{}
-		log.Debugf("Prepare plugin data\n")
-		for _, in := range st.inputs {
-			if v, has := s.plugin_data[in.name]; has {
+func (s *server) prepareStep(k int) (string, error) {
+	st := &s.steps[k]
+	pdata := eputils.SchemaMapData{}
+	log.Debugf("Prepare plugin data\n")
+	for _, in := range st.inputs {
+		if v, has := s.plugin_data[in.name]; has {
+			if s.IsConfidentialData(in.name) {
+				log.Debugf("Input %s, schema: %s, value: *confidential data*\n", in.name, in.schemaName)
+			} else {
+				log.Debugf("Input %s, schema: %s, value: %v\n", in.name, in.schemaName, v)
+			}
+			pdata[in.schemaName] = v
+		} else {
+			pdata[in.schemaName] = eputils.SchemaStructNew(in.schemaName)
+			if data, has := s.plugin_dataattrs[in.name]; has {
+				if err := eputils.LoadSchemaStructFromYaml(pdata[in.schemaName], data.value); err != nil {
+					log.Warningf("Load plugin data [%s] from init data error, %v\n", in.name, err)
+					return "", eputils.GetError("errPluginData")
+				}
 				if s.IsConfidentialData(in.name) {
-					log.Debugf("Input %s, schema: %s, value: *confidential data*\n", in.name, in.schemaName)
+					log.Debugf("init plugin_data: [%s]: *confidential data*", in.name)
 				} else {
-					log.Debugf("Input %s, schema: %s, value: %v\n", in.name, in.schemaName, v)
+					log.Debugf("init plugin_data: [%s]: %s", in.name, data.value)
 				}
-				pdata[in.schemaName] = v
 			} else {
-				pdata[in.schemaName] = eputils.SchemaStructNew(in.schemaName)
-				if data, has := s.plugin_dataattrs[in.name]; has {
-					if err := eputils.LoadSchemaStructFromYaml(pdata[in.schemaName], data.value); err != nil {
-						log.Warningf("Load plugin data [%s] from init data error, %v\n", in.name, err)
-						return eputils.GetError("errPluginData")
-					}
-					if s.IsConfidentialData(in.name) {
-						log.Debugf("init plugin_data: [%s]: *confidential data*", in.name)
-					} else {
-						log.Debugf("init plugin_data: [%s]: %s", in.name, data.value)
-					}
-				} else {
-					log.Errorf("Cannot find schema %s in previous step's output or init data \n", in.name)
-					return eputils.GetError("errPreviousSchema")
-				}
+				log.Errorf("Cannot find schema %s in previous step's output or init data \n", in.name)
+				return "", eputils.GetError("errPreviousSchema")
 			}
 		}
-		json, err := pdata.MarshalBinary()
+	}
+	json, err := pdata.MarshalBinary()
+	if err != nil {
+		log.Errorf("pdata marshal error: %v", err)
+		return "", eputils.GetError("errMarshalPdata")
+	}
+	st.data = &wfapi.WorkflowData{Data: s.data.Data, PluginData: json}
+	return hashOf(json), nil
+}
+
+func (s *server) completeStep(k int, inputHash string) error {
+	st := &s.steps[k]
+	pdata := eputils.SchemaMapData{}
+	if err := pdata.UnmarshalBinary(st.data.PluginData); err != nil {
+		log.Errorf("Plugin return pdata error, err: %v, json dump: %v", err, string(st.data.PluginData))
+		return eputils.GetError("errPluginReturn")
+	}
+	stcp := stepCheckpoint{
+		Index:     k,
+		Plugin:    st.plugin,
+		InputHash: inputHash,
+		Outputs:   map[string]string{},
+	}
+	for _, out := range st.outputs {
+		if v, has := pdata[out.schemaName]; has {
+			if s.IsConfidentialData(out.name) {
+				log.Debugf("Output %s as %s, value: *confidential data*\n", out.name, out.schemaName)
+			} else {
+				log.Debugf("Output %s as %s, value: %v\n", out.name, out.schemaName, v)
+			}
+			s.plugin_data[out.name] = v
+		} else {
+			log.Errorf("Cannot find schema %s in output\n", out.schemaName)
+			return eputils.GetError("errSchemaOutData")
+		}
+		yml, err := eputils.SchemaStructToYaml(pdata[out.schemaName])
 		if err != nil {
-			log.Errorf("pdata marshal error: %v", err)
-			return eputils.GetError("errMarshalPdata")
+			return err
+		}
+		stcp.Outputs[out.name] = hashOf([]byte(yml))
+		if data, has := s.plugin_dataattrs[out.name]; has {
+			if !data.confidential {
+				log.Infof("Update data to file: %s\n", data.filepath)
+				if err := ioutil.WriteFile(data.filepath, []byte(yml), data.filemode); err != nil {
+					return err
+				}
+			}
 		}
-		s.data.PluginData = json
+	}
+	if err := s.checkpoint.record(stcp); err != nil {
+		log.Errorf("Failed to save checkpoint of plugin %v: %v", st.plugin, err)
+		return err
+	}
+	log.Debugf("PluginComplete: plugin_data: %v", s.plugin_data)
+	return nil
+}
 
-		log.Infof("kickoff plugin: %v", st.plugin)
-		s.current = &s.steps[k]
-		s.current.started <- true
-		<-s.current.finished
+// run kicks off the steps as soon as the steps they depend on are done, with
+// at most s.parallel steps running at the same time. Ready steps are always
+// picked in workflow order, so a limit of 1 runs the steps one by one. On the
+// first error, no more steps are kicked off, and the steps still running are
+// waited for, so that the ones succeeding are checkpointed.
+func (s *server) run() error {
+	done := make(chan int, len(s.steps))
+	errs := make([]error, len(s.steps))
+	inputHashes := make([]string, len(s.steps))
+	running := 0
+	left := len(s.steps)
 
-		if err := pdata.UnmarshalBinary(s.data.PluginData); err != nil {
-			log.Errorf("Plugin return pdata error, err: %v, json dump: %v", err, string(s.data.PluginData))
-			return eputils.GetError("errPluginReturn")
+	wait := func() error {
+		k := <-done
+		s.progress.done(k + 1)
+		running--
+		left--
+		s.setStepDone(k)
+		err := errs[k]
+		if err == nil {
+			err = s.completeStep(k, inputHashes[k])
 		}
-		for _, out := range st.outputs {
-			if v, has := pdata[out.schemaName]; has {
-				if s.IsConfidentialData(out.name) {
-					log.Debugf("Output %s as %s, value: *confidential data*\n", out.name, out.schemaName)
-				} else {
-					log.Debugf("Output %s as %s, value: %v\n", out.name, out.schemaName, v)
-				}
-				s.plugin_data[out.name] = v
-			} else {
-				log.Errorf("Cannot find schema %s in output\n", out.schemaName)
-				return eputils.GetError("errSchemaOutData")
+		emitResult(Event{
+			Type:     EventStepEnd,
+			Workflow: s.name,
+			Step:     k + 1,
+			Plugin:   s.steps[k].plugin,
+			Duration: time.Since(s.steps[k].begin).Seconds(),
+		}, err)
+		return err
+	}
+	drain := func(err error) error {
+		for running > 0 {
+			if werr := wait(); werr != nil {
+				log.Errorf("plugin failed while stopping the workflow: %v", werr)
 			}
-			if data, has := s.plugin_dataattrs[out.name]; has {
-				if !data.confidential {
-					log.Infof("Update data to file: %s\n", data.filepath)
-					yml, err := eputils.SchemaStructToYaml(pdata[out.schemaName])
-					if err != nil {
-						return err
-					}
-					if err := ioutil.WriteFile(data.filepath, []byte(yml), data.filemode); err != nil {
-						return err
-					}
+		}
+		return err
+	}
+
+	for left > 0 {
+		for k := range s.steps {
+			if running >= s.parallel {
+				break
+			}
+			if !s.isReady(k) {
+				continue
+			}
+			if run, err := s.evalWhen(&s.steps[k]); err != nil {
+				return drain(err)
+			} else if !run {
+				log.Infof("skip plugin: %v, when condition is false", s.steps[k].plugin)
+				emitEvent(Event{
+					Type:     EventStepSkip,
+					Workflow: s.name,
+					Step:     k + 1,
+					Plugin:   s.steps[k].plugin,
+					Message:  "when: " + strings.Join(s.steps[k].when, ", "),
+				})
+				s.skipStep(k)
+				s.setStepDone(k)
+				left--
+				continue
+			}
+			inputHash, err := s.prepareStep(k)
+			if err != nil {
+				return drain(err)
+			}
+			if s.resuming {
+				if s.restoreStep(k, inputHash) {
+					log.Infof("skip plugin: %v, inputs not changed since last run", s.steps[k].plugin)
+					emitEvent(Event{Type: EventStepSkip, Workflow: s.name, Step: k + 1, Plugin: s.steps[k].plugin})
+					s.skipStep(k)
+					s.setStepDone(k)
+					left--
+					continue
 				}
+				s.resuming = false
+				s.checkpoint.forget(s.dependents(k))
 			}
+			inputHashes[k] = inputHash
+
+			log.Infof("kickoff plugin: %v", s.steps[k].plugin)
+			emitEvent(Event{Type: EventStepStart, Workflow: s.name, Step: k + 1, Plugin: s.steps[k].plugin})
+			s.steps[k].begin = time.Now()
+			s.steps[k].scheduled = true
+			running++
+			go func(k int) {
+				errs[k] = s.runStep(k)
+				done <- k
+			}(k)
+		}
+		if running == 0 {
+			continue
+		}
+		if err := wait(); err != nil {
+			return drain(err)
 		}
-		log.Debugf("PluginComplete: plugin_data: %v", s.plugin_data)
 	}
 	log.Infof("workflow finished")
 	return nil
 }
 
 func Start(name string, address string, configFile string) error {
+	emitEvent(Event{Type: EventWorkflowStart, Workflow: name})
+	begin := time.Now()
+	err := start(name, address, configFile)
+	emitResult(Event{
+		Type:     EventWorkflowEnd,
+		Workflow: name,
+		Duration: time.Since(begin).Seconds(),
+	}, err)
+	return err
+}
+
+func start(name string, address string, configFile string) error {
 	log.Infof("load workflow config file %v", configFile)
 	wf := wfapi.Workflow{}
 	err := eputils.LoadSchemaStructFromYamlFile(&wf, configFile)
@@ -342,14 +502,21 @@ func Start(name string, address string, configFile string) error {
 		steps:            []step{},
 		plugin_data:      eputils.SchemaMapData{},
 		plugin_dataattrs: map[string]dataAttr{},
+		cancel:           make(chan struct{}),
 		finished:         make(chan bool),
 		data:             &wfapi.WorkflowData{},
 		errch:            make(chan error),
+		processes:        map[string]*exec.Cmd{},
+		pluginVersions:   map[string]string{},
+		resuming:         Resume,
 	}
 
 	if err := s.loadSteps(); err != nil {
 		return err
 	}
+	if s.checkpoint, err = loadCheckpoint(name, Resume); err != nil {
+		return err
+	}
 	if len(s.steps) <= 0 {
 		log.Infof("No step in this workflow to run.")
 		return nil
@@ -365,9 +532,20 @@ func Start(name string, address string, configFile string) error {
 	if err := s.serve(address); err != nil {
 		return err
 	}
-	if err := s.startPlugins(); err != nil {
+	defer s.stopProcesses()
+	defer s.logPluginVersions()
+	if EventOutput != os.Stdout {
+		s.progress = newProgressBar(os.Stdout)
+		if s.progress.terminal {
+			log.AddHook(s.progress)
+		}
+	}
+	if err := s.startPlugins(address); err != nil {
 		return err
 	}
+	sigch := make(chan os.Signal, 1)
+	signal.Notify(sigch, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigch)
 	go func() {
 		err := s.run()
 		if err != nil {
@@ -382,6 +560,18 @@ func Start(name string, address string, configFile string) error {
 			return err
 		case err = <-s.errch:
 			break
+		case sig := <-sigch:
+			log.Warnf("Workflow is cancelled by %v", sig)
+			s.stop()
+			// The running steps are cancelled, wait for them to be
+			// checkpointed.
+			for {
+				select {
+				case <-s.finished:
+					return eputils.GetError("errWorkflowCancel")
+				case <-s.errch:
+				}
+			}
 		}
 	}
 	return err